@@ -0,0 +1,181 @@
+package boot
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"todo-api/web"
+)
+
+var errInvalidAdminToken = errors.New("missing or invalid admin token")
+
+type (
+	// DiagnosticsReport is the body of /debug/vars on the admin listener.
+	DiagnosticsReport struct {
+		GoVersion  string         `json:"go_version"`
+		StartedAt  string         `json:"started_at"`
+		CPUs       int            `json:"cpus"`
+		Goroutines int            `json:"goroutines"`
+		Memory     MemoryStats    `json:"memory"`
+		GC         GCStats        `json:"gc"`
+		Components map[string]any `json:"components,omitempty"`
+	}
+
+	MemoryStats struct {
+		HeapAllocBytes uint64 `json:"heap_alloc_bytes"`
+		HeapInuseBytes uint64 `json:"heap_inuse_bytes"`
+		HeapObjects    uint64 `json:"heap_objects"`
+		SysBytes       uint64 `json:"sys_bytes"`
+	}
+
+	GCStats struct {
+		NumGC         int64   `json:"num_gc"`
+		LastGC        string  `json:"last_gc,omitempty"`
+		LastPauseMS   float64 `json:"last_pause_ms"`
+		PauseTotalMS  float64 `json:"pause_total_ms"`
+		NextGCBytes   uint64  `json:"next_gc_bytes"`
+		GCCPUFraction float64 `json:"gc_cpu_fraction"`
+	}
+
+	// diagnostics holds the sources of the components section of /debug/vars.
+	diagnostics struct {
+		startedAt time.Time
+
+		mu      sync.Mutex
+		sources map[string]func() any
+	}
+
+	diagnosticsKey struct{}
+)
+
+// RegisterDiagnostics adds the value returned by fn to /debug/vars under the name of the
+// component, e.g. the statistics of a database pool. fn is called on every request and
+// its result is marshalled to JSON.
+//
+// The context must be the one handed to the MiddlewareMapper or RoutesMapper; with any
+// other context the component is never reported.
+func RegisterDiagnostics(ctx context.Context, name string, fn func() any) {
+	d, ok := ctx.Value(diagnosticsKey{}).(*diagnostics)
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sources[name] = fn
+}
+
+func newDiagnostics() *diagnostics {
+	return &diagnostics{
+		startedAt: time.Now(),
+		sources:   map[string]func() any{},
+	}
+}
+
+func (d *diagnostics) context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, diagnosticsKey{}, d)
+}
+
+// vars reports the runtime statistics of the process and those of the components.
+func (d *diagnostics) vars() web.Handler {
+	return func(r web.Request) web.Response {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		var gc debug.GCStats
+		debug.ReadGCStats(&gc)
+
+		report := DiagnosticsReport{
+			GoVersion:  runtime.Version(),
+			StartedAt:  d.startedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			CPUs:       runtime.NumCPU(),
+			Goroutines: runtime.NumGoroutine(),
+			Memory: MemoryStats{
+				HeapAllocBytes: mem.HeapAlloc,
+				HeapInuseBytes: mem.HeapInuse,
+				HeapObjects:    mem.HeapObjects,
+				SysBytes:       mem.Sys,
+			},
+			GC: GCStats{
+				NumGC:         gc.NumGC,
+				PauseTotalMS:  float64(gc.PauseTotal.Microseconds()) / 1000,
+				NextGCBytes:   mem.NextGC,
+				GCCPUFraction: mem.GCCPUFraction,
+			},
+		}
+		if gc.NumGC > 0 {
+			report.GC.LastGC = gc.LastGC.UTC().Format("2006-01-02T15:04:05Z")
+			report.GC.LastPauseMS = float64(gc.Pause[0].Microseconds()) / 1000
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if len(d.sources) > 0 {
+			report.Components = make(map[string]any, len(d.sources))
+			for name, fn := range d.sources {
+				report.Components[name] = fn()
+			}
+		}
+
+		return web.NewJSONResponse(http.StatusOK, report)
+	}
+}
+
+// newAdminServer builds the admin listener, serving /debug/vars and the profiles when
+// enabled behind the admin token. It has no write timeout, so that CPU profiles and
+// traces can run for as long as they are asked to.
+func (m *mux[M, R]) newAdminServer(ctx context.Context, conf Config) Server {
+	ar, am := m.newRouterFn()
+	m.useMiddlewares(am, newAdminAuthInterceptor(conf.Admin.Token))
+	if conf.Admin.PProf {
+		m.mountPProfFn(ar)
+	}
+	m.handleJSONGet(ar, "/debug/vars", m.diagnostics.vars())
+
+	return m.newServerFn(ctx, ServerConfig{
+		Port:              conf.Admin.Port,
+		ReadTimeout:       conf.Server.ReadTimeout,
+		ReadHeaderTimeout: conf.Server.ReadHeaderTimeout,
+		IdleTimeout:       conf.Server.IdleTimeout,
+	}, ar)
+}
+
+// newAdminAuthInterceptor lets through the requests bearing the admin token, which is
+// unrelated to the credentials of the API so that its clients can never reach the admin
+// surface.
+func newAdminAuthInterceptor(token string) web.Interceptor {
+	return func(req web.InterceptedRequest) web.Response {
+		scheme, got, ok := strings.Cut(req.Raw().Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) != 1 {
+			resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusUnauthorized, errInvalidAdminToken))
+			resp.Headers.Set("WWW-Authenticate", `Bearer realm="admin"`)
+			return resp
+		}
+		return req.Next()
+	}
+}
+
+// servePProf serves the profiles of net/http/pprof under /debug/pprof/, for routers that
+// mount it on a single wildcard route.
+func servePProf(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimPrefix(r.URL.Path, "/debug/pprof/") {
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	case "profile":
+		pprof.Profile(w, r)
+	case "symbol":
+		pprof.Symbol(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	default:
+		pprof.Index(w, r)
+	}
+}
@@ -0,0 +1,160 @@
+package boot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testAdminToken = "0123456789abcdef"
+
+// newTestAdmin builds the admin listener of a Gin app and returns its router.
+func newTestAdmin(t *testing.T, pprof bool, register func(context.Context)) http.Handler {
+	t.Helper()
+	app := NewGin(DefaultGinMiddlewareMapper(), func(context.Context, Config, GinRouter) {})
+	gin.SetMode(gin.TestMode)
+
+	var router http.Handler
+	app.newServerFn = func(ctx context.Context, conf ServerConfig, r GinRouter) Server {
+		router = r
+		return newFakeServer()
+	}
+
+	ctx, _ := app.newBootableContext()
+	if register != nil {
+		register(ctx)
+	}
+	conf := validConfig()
+	conf.Admin = AdminConfig{Enabled: true, Port: 6060, Token: testAdminToken, PProf: pprof}
+	app.newAdminServer(ctx, conf)
+	return router
+}
+
+func serveAdmin(h http.Handler, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminServer_RequiresTheToken(t *testing.T) {
+	admin := newTestAdmin(t, false, nil)
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{name: "missing", status: http.StatusUnauthorized},
+		{name: "wrong", authorization: "Bearer fedcba9876543210", status: http.StatusUnauthorized},
+		{name: "prefix", authorization: "Bearer " + testAdminToken[:8], status: http.StatusUnauthorized},
+		{name: "other scheme", authorization: "Basic " + testAdminToken, status: http.StatusUnauthorized},
+		{name: "no scheme", authorization: testAdminToken, status: http.StatusUnauthorized},
+		{name: "valid", authorization: "Bearer " + testAdminToken, status: http.StatusOK},
+		{name: "scheme in lower case", authorization: "bearer " + testAdminToken, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveAdmin(admin, "/debug/vars", tt.authorization)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if tt.status != http.StatusUnauthorized {
+				return
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != `Bearer realm="admin"` {
+				t.Errorf("expected a bearer challenge, got %q", got)
+			}
+			if strings.Contains(w.Body.String(), "go_version") {
+				t.Errorf("expected the diagnostics to be withheld, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminServer_MountsPProfOnlyWhenEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		pprof  bool
+		status int
+	}{
+		{name: "enabled", pprof: true, status: http.StatusOK},
+		{name: "disabled", pprof: false, status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := newTestAdmin(t, tt.pprof, nil)
+
+			w := serveAdmin(admin, "/debug/pprof/", "Bearer "+testAdminToken)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestAdminServer_ReportsTheRuntimeAndTheComponents(t *testing.T) {
+	admin := newTestAdmin(t, false, func(ctx context.Context) {
+		RegisterDiagnostics(ctx, "database", func() any {
+			return map[string]int{"open_connections": 3}
+		})
+	})
+
+	w := serveAdmin(admin, "/debug/vars", "Bearer "+testAdminToken)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var report struct {
+		DiagnosticsReport
+		Components map[string]map[string]int `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode the report %q: %v", w.Body.String(), err)
+	}
+	if report.GoVersion != runtime.Version() || report.CPUs != runtime.NumCPU() {
+		t.Errorf("expected the runtime of the process, got %s on %d CPUs", report.GoVersion, report.CPUs)
+	}
+	if report.StartedAt == "" || report.Goroutines == 0 {
+		t.Errorf("expected the start time and the goroutines, got %q and %d", report.StartedAt, report.Goroutines)
+	}
+	if report.Memory.HeapAllocBytes == 0 || report.Memory.SysBytes == 0 || report.GC.NextGCBytes == 0 {
+		t.Errorf("expected the memory statistics, got %+v and %+v", report.Memory, report.GC)
+	}
+	if got := report.Components["database"]["open_connections"]; got != 3 {
+		t.Errorf("expected the database component, got %v", report.Components)
+	}
+}
+
+func TestGin_RunRejectsAShortAdminToken(t *testing.T) {
+	isolateConfig(t, nil)
+	t.Setenv("DATABASE_DSN", testDSN)
+	t.Setenv("AUTH_DISABLED", "true")
+	t.Setenv("ADMIN_ENABLED", "true")
+	t.Setenv("ADMIN_TOKEN", testAdminToken[:15])
+
+	app := NewGin(DefaultGinMiddlewareMapper(), func(context.Context, Config, GinRouter) {})
+	gin.SetMode(gin.TestMode)
+	app.newServerFn = func(ctx context.Context, conf ServerConfig, r GinRouter) Server {
+		t.Errorf("expected no server to start, got one on port %d", conf.Port)
+		return newFakeServer()
+	}
+
+	ctx, _ := app.newBootableContext()
+	err := app.run(ctx)
+
+	if err == nil || !strings.Contains(err.Error(), "admin.token must be at least 16 characters") {
+		t.Errorf("expected the short token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,419 @@
+package boot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"todo-api/pkg/domain"
+)
+
+const (
+	// defaultEnvironment is used when GO_ENVIRONMENT is unset.
+	defaultEnvironment = "local"
+	// productionEnvironment refuses the settings only meant for development.
+	productionEnvironment = "production"
+	// defaultConfigDir is where the configuration files are looked up when CONFIG_DIR is unset.
+	defaultConfigDir = "conf"
+	// featureEnvPrefix prefixes the environment variables toggling features, e.g.
+	// FEATURE_API_KEYS=false disables the api_keys feature.
+	featureEnvPrefix = "FEATURE_"
+	// secretFileSuffix suffixes the environment variables naming a file that holds the
+	// value of the variable, e.g. DATABASE_DSN_FILE=/run/secrets/dsn.
+	secretFileSuffix = "_FILE"
+	// minAdminTokenLength keeps the admin token from being guessable.
+	minAdminTokenLength = 16
+)
+
+type (
+	// Config is the configuration of the application, handed to the MiddlewareMapper and
+	// RoutesMapper. It is built by LoadConfig in layers, each one overriding the previous:
+	//
+	//  1. the defaults of DefaultConfig
+	//  2. the YAML file named after GO_ENVIRONMENT in CONFIG_DIR, e.g. conf/local.yml
+	//  3. the environment variables named by the env tags of the fields, or the content
+	//     of the file named by the same variable suffixed with _FILE, so that secrets
+	//     need not be put in the environment
+	Config struct {
+		// Environment is the value of GO_ENVIRONMENT, "local" when unset.
+		Environment string `yaml:"-"`
+
+		Server      ServerConfig      `yaml:"server"`
+		Database    DatabaseConfig    `yaml:"database"`
+		Admin       AdminConfig       `yaml:"admin"`
+		Tracing     TracingConfig     `yaml:"tracing"`
+		Auth        AuthConfig        `yaml:"auth"`
+		Policy      PolicyConfig      `yaml:"policy"`
+		Todos       TodosConfig       `yaml:"todos"`
+		Attachments AttachmentsConfig `yaml:"attachments"`
+		Workflow    WorkflowConfig    `yaml:"workflow"`
+		Features    map[string]bool   `yaml:"features"`
+	}
+
+	ServerConfig struct {
+		Port              int           `yaml:"port" env:"PORT"`
+		ReadTimeout       time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+		ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+		WriteTimeout      time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+		IdleTimeout       time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+		// ShutdownDelay is how long /ping reports the server as shutting down before it
+		// stops accepting connections, so that load balancers stop routing to it first.
+		ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SERVER_SHUTDOWN_DELAY"`
+		// ShutdownTimeout bounds the drain of the in-flight requests and the shutdown
+		// hooks, after the delay.
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+	}
+
+	// AdminConfig configures the admin listener, which serves /debug/vars and, when PProf
+	// is set, /debug/pprof/ on its own port, to requests bearing the admin token.
+	AdminConfig struct {
+		Enabled bool `yaml:"enabled" env:"ADMIN_ENABLED"`
+		Port    int  `yaml:"port" env:"ADMIN_PORT"`
+		// Token is better given through ADMIN_TOKEN_FILE than put in a configuration file.
+		Token string `yaml:"token" env:"ADMIN_TOKEN"`
+		PProf bool   `yaml:"pprof" env:"ADMIN_PPROF"`
+	}
+
+	// TracingConfig configures where the spans of the requests are exported: nowhere
+	// ("none"), to the standard output as JSON lines ("stdout") or appended to File
+	// ("file").
+	TracingConfig struct {
+		Exporter    string `yaml:"exporter" env:"TRACING_EXPORTER"`
+		File        string `yaml:"file" env:"TRACING_FILE"`
+		ServiceName string `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	}
+
+	// AuthConfig configures the bearer JWTs required by the API. JWKSFile is required
+	// unless Disabled is set, which leaves the API unauthenticated and is refused in
+	// production.
+	AuthConfig struct {
+		Disabled bool `yaml:"disabled" env:"AUTH_DISABLED"`
+		// JWKSFile is a JSON Web Key Set holding the keys the tokens may be signed with.
+		JWKSFile string `yaml:"jwks_file" env:"AUTH_JWKS_FILE"`
+		// Audience is required along with JWKSFile, so that tokens issued for other
+		// services are refused.
+		Audience string `yaml:"audience" env:"AUTH_AUDIENCE"`
+		Issuer   string `yaml:"issuer" env:"AUTH_ISSUER"`
+	}
+
+	// PolicyConfig names the YAML policy checked before every action. Without it every
+	// user can do everything.
+	PolicyConfig struct {
+		File string `yaml:"file" env:"POLICY_FILE"`
+	}
+
+	TodosConfig struct {
+		// TrashRetention is how long deleted todos stay in the trash before being purged.
+		TrashRetention time.Duration `yaml:"trash_retention" env:"TRASH_RETENTION"`
+	}
+
+	AttachmentsConfig struct {
+		// Dir is where the files of the attachments are stored.
+		Dir string `yaml:"dir" env:"ATTACHMENTS_DIR"`
+		// MaxSize is the largest accepted file, in bytes.
+		MaxSize int64 `yaml:"max_size" env:"MAX_ATTACHMENT_SIZE"`
+	}
+
+	// WorkflowConfig lists, for every status, the statuses a todo in it may move to, e.g.
+	// pending: [in_progress]. Todos follow domain.DefaultWorkflow when it is empty.
+	WorkflowConfig struct {
+		Transitions map[string][]string `yaml:"transitions"`
+	}
+
+	DatabaseConfig struct {
+		// DSN is a lib/pq connection string, either a URL or key=value pairs.
+		DSN             string        `yaml:"dsn" env:"DATABASE_DSN"`
+		MaxOpenConns    int           `yaml:"max_open_conns" env:"DATABASE_MAX_OPEN_CONNS"`
+		MaxIdleConns    int           `yaml:"max_idle_conns" env:"DATABASE_MAX_IDLE_CONNS"`
+		ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DATABASE_CONN_MAX_LIFETIME"`
+		ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DATABASE_CONN_MAX_IDLE_TIME"`
+	}
+)
+
+// DefaultConfig returns the configuration the layers of LoadConfig start from. It has no
+// DSN, which must always be configured.
+func DefaultConfig() Config {
+	return Config{
+		Environment: defaultEnvironment,
+		Server: ServerConfig{
+			Port:              8080,
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Admin: AdminConfig{
+			Port: 6060,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingExporterNone,
+			ServiceName: "todo-api",
+		},
+		Todos: TodosConfig{
+			TrashRetention: 30 * 24 * time.Hour,
+		},
+		Attachments: AttachmentsConfig{
+			Dir:     "data/attachments",
+			MaxSize: 10 << 20,
+		},
+		Features: map[string]bool{},
+	}
+}
+
+// LoadConfig builds the configuration from its layers and validates it. A missing file
+// for the environment is not an error, as everything can come from the environment.
+func LoadConfig() (Config, error) {
+	conf := DefaultConfig()
+	if env := os.Getenv("GO_ENVIRONMENT"); env != "" {
+		conf.Environment = env
+	}
+
+	dir := os.Getenv("CONFIG_DIR")
+	if dir == "" {
+		dir = defaultConfigDir
+	}
+
+	path := filepath.Join(dir, conf.Environment+".yml")
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return Config{}, fmt.Errorf("config: %w", err)
+	default:
+		if err := yaml.UnmarshalWithOptions(data, &conf, yaml.Strict()); err != nil {
+			return Config{}, fmt.Errorf("config: %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&conf).Elem()); err != nil {
+		return Config{}, fmt.Errorf("config: %w", err)
+	}
+	if conf.Features == nil {
+		conf.Features = map[string]bool{}
+	}
+	if err := applyFeatureEnv(conf.Features); err != nil {
+		return Config{}, fmt.Errorf("config: %w", err)
+	}
+
+	if err := conf.Validate(); err != nil {
+		return Config{}, fmt.Errorf("config: %w", err)
+	}
+	return conf, nil
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	timeouts := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+	}
+	for _, t := range timeouts {
+		if t.d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a positive duration, got %s", t.name, t.d))
+		}
+	}
+	if c.Server.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("server.shutdown_delay must not be negative, got %s", c.Server.ShutdownDelay))
+	}
+	if c.Admin.Enabled {
+		if c.Admin.Port < 1 || c.Admin.Port > 65535 {
+			errs = append(errs, fmt.Errorf("admin.port must be between 1 and 65535, got %d", c.Admin.Port))
+		}
+		if c.Admin.Port == c.Server.Port {
+			errs = append(errs, fmt.Errorf("admin.port must differ from server.port, got %d", c.Admin.Port))
+		}
+		if len(strings.TrimSpace(c.Admin.Token)) < minAdminTokenLength {
+			errs = append(errs, fmt.Errorf("admin.token must be at least %d characters", minAdminTokenLength))
+		}
+	}
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterFile:
+		if c.Tracing.File == "" {
+			errs = append(errs, errors.New("tracing.file is required by the file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be none, stdout or file, got %q", c.Tracing.Exporter))
+	}
+	switch {
+	case c.Auth.Disabled && c.Environment == productionEnvironment:
+		errs = append(errs, errors.New("auth.disabled is not allowed in production"))
+	case c.Auth.Disabled && c.Auth.JWKSFile != "":
+		errs = append(errs, errors.New("auth.jwks_file must be empty when auth.disabled is set"))
+	case !c.Auth.Disabled && strings.TrimSpace(c.Auth.JWKSFile) == "":
+		errs = append(errs, errors.New("auth.jwks_file is required unless auth.disabled is set"))
+	}
+	if c.Auth.JWKSFile != "" && strings.TrimSpace(c.Auth.Audience) == "" {
+		errs = append(errs, errors.New("auth.audience is required by auth.jwks_file"))
+	}
+	if c.Todos.TrashRetention <= 0 {
+		errs = append(errs, fmt.Errorf("todos.trash_retention must be a positive duration, got %s", c.Todos.TrashRetention))
+	}
+	if strings.TrimSpace(c.Attachments.Dir) == "" {
+		errs = append(errs, errors.New("attachments.dir is required"))
+	}
+	if c.Attachments.MaxSize <= 0 {
+		errs = append(errs, fmt.Errorf("attachments.max_size must be a positive number of bytes, got %d", c.Attachments.MaxSize))
+	}
+	for from, tos := range c.Workflow.Transitions {
+		for _, status := range append([]string{from}, tos...) {
+			if !domain.Status(status).IsValid() {
+				errs = append(errs, fmt.Errorf("workflow.transitions must only name pending, in_progress or completed, got %q", status))
+			}
+		}
+	}
+	if strings.TrimSpace(c.Database.DSN) == "" {
+		errs = append(errs, errors.New("database.dsn is required"))
+	}
+	if c.Database.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Errorf("database.max_open_conns must not be negative, got %d", c.Database.MaxOpenConns))
+	}
+	if c.Database.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns must not be negative, got %d", c.Database.MaxIdleConns))
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns must not exceed max_open_conns, got %d > %d", c.Database.MaxIdleConns, c.Database.MaxOpenConns))
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("database.conn_max_lifetime and conn_max_idle_time must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether the feature is toggled on. Features are off unless configured.
+func (c Config) Enabled(feature string) bool {
+	return c.Features[feature]
+}
+
+// applyEnv overrides the fields having an env tag with the environment variable it names.
+func applyEnv(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field, sf := v.Field(i), v.Type().Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := sf.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		value, ok, err := lookupEnv(name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+	}
+	return nil
+}
+
+// applyFeatureEnv toggles the features named by FEATURE_ environment variables, or by
+// the files their _FILE variants name; the feature name is the rest of the variable name
+// in lower case.
+func applyFeatureEnv(features map[string]bool) error {
+	seen := make(map[string]bool)
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		name = strings.TrimSuffix(name, secretFileSuffix)
+		feature, ok := strings.CutPrefix(name, featureEnvPrefix)
+		if !ok || feature == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		value, _, err := lookupEnv(name)
+		if err != nil {
+			return err
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: must be true or false", name, value)
+		}
+		features[strings.ToLower(feature)] = enabled
+	}
+	return nil
+}
+
+// lookupEnv reads the variable, or the file named by the variable suffixed with _FILE.
+// Setting both is an error, as one of them would silently be ignored.
+func lookupEnv(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	path, fromFile := os.LookupEnv(name + secretFileSuffix)
+	if !fromFile {
+		return value, ok, nil
+	}
+	if ok {
+		return "", false, fmt.Errorf("%s and %s%s are both set", name, name, secretFileSuffix)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid %s%s: %w", name, secretFileSuffix, err)
+	}
+	return strings.TrimSpace(string(data)), true, nil
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value)
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		field.SetInt(int64(n))
+	case int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		field.SetInt(n)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		field.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("must be a duration such as 30s")
+		}
+		field.SetInt(int64(d))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"todo-api/web"
 	webgin "todo-api/web/gin"
@@ -73,10 +74,17 @@ func NewGin(gmm MiddlewareMapper[GinMiddlewareRouter], gmr RoutesMapper[GinRoute
 				r.Use(gin.Recovery())
 				return r, r
 			},
-			func() (interface{}, bool) { return nil, false },
-			func(ctx context.Context, r GinRouter) Server { return NewHTTPServer(ctx, r) },
-			func(GinRouter) {}, // no pprof
-			func(GinMiddlewareRouter) func() error { return func() error { return nil } },
+			NewTracerProvider,
+			func(ctx context.Context, conf ServerConfig, r GinRouter) Server { return NewHTTPServer(ctx, conf, r) },
+			func(r GinRouter) {
+				h := gin.WrapF(servePProf)
+				r.GET("/debug/pprof/*profile", h)
+				r.POST("/debug/pprof/*profile", h)
+			},
+			func(gmr GinMiddlewareRouter, tp *sdktrace.TracerProvider) ShutDownFn {
+				gmr.Use(webgin.NewInterceptor(newTracingInterceptor(tp)))
+				return tp.Shutdown
+			},
 			func(r GinRouter, s string, h web.Handler) {
 				r.GET(s, webgin.NewHandlerRaw(h))
 			},
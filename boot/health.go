@@ -0,0 +1,200 @@
+package boot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"todo-api/web"
+)
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+
+	// defaultHealthCheckTimeout bounds a check registered without WithHealthCheckTimeout.
+	defaultHealthCheckTimeout = 2 * time.Second
+)
+
+type (
+	// HealthCheck reports whether a dependency of the application is usable, e.g. by
+	// pinging a database. It should give up once the context is done.
+	HealthCheck func(context.Context) error
+
+	HealthCheckOption func(*healthCheck)
+
+	// HealthReport is the body of /health/live and /health/ready.
+	HealthReport struct {
+		Status     string                     `json:"status"`
+		Components map[string]ComponentHealth `json:"components,omitempty"`
+	}
+
+	// ComponentHealth is the outcome of the last run of a check. CheckedAt tells how old
+	// it is when the result comes from the cache.
+	ComponentHealth struct {
+		Status    string  `json:"status"`
+		LatencyMS float64 `json:"latency_ms"`
+		CheckedAt string  `json:"checked_at"`
+		Error     string  `json:"error,omitempty"`
+	}
+
+	// healthRegistry holds the checks deciding the readiness of the application.
+	healthRegistry struct {
+		// now is the clock of the checks, time.Now when nil.
+		now func() time.Time
+
+		mu     sync.Mutex
+		checks []*healthCheck
+	}
+
+	healthCheck struct {
+		name    string
+		check   HealthCheck
+		timeout time.Duration
+		ttl     time.Duration
+		now     func() time.Time
+
+		mu        sync.Mutex
+		last      ComponentHealth
+		checkedAt time.Time
+	}
+
+	healthRegistryKey struct{}
+)
+
+// WithHealthCheckTimeout fails the check when it does not complete within the timeout,
+// 2s by default.
+func WithHealthCheckTimeout(d time.Duration) HealthCheckOption {
+	return func(c *healthCheck) {
+		c.timeout = d
+	}
+}
+
+// WithHealthCheckCache reuses the result of the check for the duration instead of running
+// it on every probe, so that frequent probes do not load the dependency. Results are not
+// cached by default.
+func WithHealthCheckCache(ttl time.Duration) HealthCheckOption {
+	return func(c *healthCheck) {
+		c.ttl = ttl
+	}
+}
+
+// RegisterHealthCheck adds a check to /health/ready under the name of the component: the
+// application is ready only while every check passes. Liveness never depends on checks,
+// as restarting the application does not bring a dependency back.
+//
+// The context must be the one handed to the MiddlewareMapper or RoutesMapper; with any
+// other context the check is never run.
+func RegisterHealthCheck(ctx context.Context, name string, check HealthCheck, opts ...HealthCheckOption) {
+	hr, ok := ctx.Value(healthRegistryKey{}).(*healthRegistry)
+	if !ok {
+		return
+	}
+
+	c := &healthCheck{
+		name:    name,
+		check:   check,
+		timeout: defaultHealthCheckTimeout,
+		now:     time.Now,
+	}
+	if hr.now != nil {
+		c.now = hr.now
+	}
+	for _, o := range opts {
+		o(c)
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	hr.checks = append(hr.checks, c)
+}
+
+func (hr *healthRegistry) context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, healthRegistryKey{}, hr)
+}
+
+// live answers 200 as long as the server can handle requests.
+func (hr *healthRegistry) live() web.Handler {
+	return func(r web.Request) web.Response {
+		return web.NewJSONResponse(http.StatusOK, HealthReport{Status: HealthStatusUp})
+	}
+}
+
+// ready runs every check concurrently and answers 503 when one of them fails or when
+// the application is shutting down.
+func (hr *healthRegistry) ready(lc *lifecycle) web.Handler {
+	return func(r web.Request) web.Response {
+		if lc.draining.Load() {
+			return web.NewJSONResponse(http.StatusServiceUnavailable, HealthReport{Status: HealthStatusDown})
+		}
+
+		hr.mu.Lock()
+		checks := hr.checks
+		hr.mu.Unlock()
+
+		results := make([]ComponentHealth, len(checks))
+		var wg sync.WaitGroup
+		for i, c := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = c.run(r.Context())
+			}()
+		}
+		wg.Wait()
+
+		report := HealthReport{Status: HealthStatusUp, Components: make(map[string]ComponentHealth, len(checks))}
+		status := http.StatusOK
+		for i, c := range checks {
+			report.Components[c.name] = results[i]
+			if results[i].Status != HealthStatusUp {
+				report.Status = HealthStatusDown
+				status = http.StatusServiceUnavailable
+			}
+		}
+		return web.NewJSONResponse(status, report)
+	}
+}
+
+// run returns the cached result while it is fresh, or runs the check. The check runs
+// apart from the request, so that a probe giving up does not cache a cancellation, and
+// a check ignoring its context cannot hold the probe past the timeout.
+func (c *healthCheck) run(ctx context.Context) ComponentHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !c.checkedAt.IsZero() && now.Sub(c.checkedAt) < c.ttl {
+		return c.last
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	result := ComponentHealth{
+		Status:    HealthStatusUp,
+		LatencyMS: float64(c.now().Sub(now).Microseconds()) / 1000,
+		CheckedAt: now.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+
+	c.last, c.checkedAt = result, now
+	return result
+}
@@ -0,0 +1,245 @@
+package boot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type (
+	// fakeClock only moves when told to.
+	fakeClock struct {
+		mu  sync.Mutex
+		now time.Time
+	}
+)
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 28, 10, 30, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// probe serves the readiness of the registry and decodes its report.
+func probe(t *testing.T, hr *healthRegistry, lc *lifecycle) (int, HealthReport) {
+	t.Helper()
+	w := serveHandler(hr.ready(lc))
+
+	var report HealthReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode the report %q: %v", w.Body.String(), err)
+	}
+	return w.Code, report
+}
+
+func TestHealthRegistry_LiveIgnoresTheChecks(t *testing.T) {
+	hr := &healthRegistry{}
+	RegisterHealthCheck(hr.context(context.Background()), "database", func(context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	w := serveHandler(hr.live())
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHealthRegistry_Ready(t *testing.T) {
+	failing := func(context.Context) error { return errors.New("connection refused") }
+	passing := func(context.Context) error { return nil }
+
+	tests := []struct {
+		name     string
+		checks   map[string]HealthCheck
+		draining bool
+		status   int
+		want     map[string]string
+	}{
+		{name: "no checks", status: http.StatusOK, want: map[string]string{}},
+		{
+			name:   "every check passes",
+			checks: map[string]HealthCheck{"database": passing, "cache": passing},
+			status: http.StatusOK,
+			want:   map[string]string{"database": HealthStatusUp, "cache": HealthStatusUp},
+		},
+		{
+			name:   "a check fails",
+			checks: map[string]HealthCheck{"database": failing, "cache": passing},
+			status: http.StatusServiceUnavailable,
+			want:   map[string]string{"database": HealthStatusDown, "cache": HealthStatusUp},
+		},
+		{
+			name:     "draining",
+			checks:   map[string]HealthCheck{"database": passing},
+			draining: true,
+			status:   http.StatusServiceUnavailable,
+			want:     map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr, lc := &healthRegistry{}, &lifecycle{}
+			lc.draining.Store(tt.draining)
+			for name, check := range tt.checks {
+				RegisterHealthCheck(hr.context(context.Background()), name, check)
+			}
+
+			status, report := probe(t, hr, lc)
+
+			if status != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, status)
+			}
+			wantStatus := HealthStatusUp
+			if tt.status != http.StatusOK {
+				wantStatus = HealthStatusDown
+			}
+			if report.Status != wantStatus {
+				t.Errorf("expected the report to be %s, got %s", wantStatus, report.Status)
+			}
+			if len(report.Components) != len(tt.want) {
+				t.Errorf("expected components %v, got %v", tt.want, report.Components)
+			}
+			for name, want := range tt.want {
+				if got := report.Components[name]; got.Status != want {
+					t.Errorf("expected %s to be %s, got %+v", name, want, got)
+				}
+			}
+			if got := report.Components["database"]; got.Status == HealthStatusDown && got.Error != "connection refused" {
+				t.Errorf("expected the error of the check, got %q", got.Error)
+			}
+		})
+	}
+}
+
+func TestHealthRegistry_ReadyTimesOutACheckIgnoringItsContext(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+	hr := &healthRegistry{}
+	release := make(chan struct{})
+	defer close(release)
+	RegisterHealthCheck(hr.context(context.Background()), "database", func(context.Context) error {
+		<-release
+		return nil
+	}, WithHealthCheckTimeout(timeout))
+
+	start := time.Now()
+	status, report := probe(t, hr, &lifecycle{})
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the probe to give up after %s, got %s", timeout, elapsed)
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, status)
+	}
+	if got := report.Components["database"]; got.Error != "timed out after 20ms" {
+		t.Errorf("expected a timeout, got %+v", got)
+	}
+}
+
+func TestHealthRegistry_ReadyCachesResultsForTheTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		advance time.Duration
+		runs    int32
+	}{
+		{name: "uncached", advance: 0, runs: 2},
+		{name: "fresh", ttl: 5 * time.Second, advance: 4 * time.Second, runs: 1},
+		{name: "expired", ttl: 5 * time.Second, advance: 5 * time.Second, runs: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			hr := &healthRegistry{now: clock.Now}
+			var runs atomic.Int32
+			RegisterHealthCheck(hr.context(context.Background()), "database", func(context.Context) error {
+				runs.Add(1)
+				return nil
+			}, WithHealthCheckCache(tt.ttl))
+
+			_, first := probe(t, hr, &lifecycle{})
+			clock.Advance(tt.advance)
+			_, second := probe(t, hr, &lifecycle{})
+
+			if got := runs.Load(); got != tt.runs {
+				t.Errorf("expected %d runs, got %d", tt.runs, got)
+			}
+			wantCheckedAt := first.Components["database"].CheckedAt
+			if tt.runs == 2 {
+				wantCheckedAt = clock.Now().Format("2006-01-02T15:04:05Z")
+			}
+			if got := second.Components["database"].CheckedAt; got != wantCheckedAt {
+				t.Errorf("expected the result checked at %s, got %s", wantCheckedAt, got)
+			}
+		})
+	}
+}
+
+func TestHealthRegistry_ReadyCachesFailures(t *testing.T) {
+	clock := newFakeClock()
+	hr := &healthRegistry{now: clock.Now}
+	var runs atomic.Int32
+	RegisterHealthCheck(hr.context(context.Background()), "database", func(context.Context) error {
+		if runs.Add(1) == 1 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, WithHealthCheckCache(5*time.Second))
+
+	first, _ := probe(t, hr, &lifecycle{})
+	clock.Advance(time.Second)
+	second, _ := probe(t, hr, &lifecycle{})
+	clock.Advance(5 * time.Second)
+	third, _ := probe(t, hr, &lifecycle{})
+
+	if first != http.StatusServiceUnavailable || second != http.StatusServiceUnavailable || third != http.StatusOK {
+		t.Errorf("expected 503, 503 then 200, got %d, %d then %d", first, second, third)
+	}
+}
+
+func TestHealthRegistry_ReadyRunsTheChecksConcurrently(t *testing.T) {
+	hr := &healthRegistry{}
+	var started sync.WaitGroup
+	started.Add(2)
+	allStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+
+	// each check passes only once the other one has started, which would time out if
+	// they ran one after the other
+	check := func(ctx context.Context) error {
+		started.Done()
+		select {
+		case <-allStarted:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	ctx := hr.context(context.Background())
+	RegisterHealthCheck(ctx, "database", check, WithHealthCheckTimeout(time.Second))
+	RegisterHealthCheck(ctx, "cache", check, WithHealthCheckTimeout(time.Second))
+
+	status, report := probe(t, hr, &lifecycle{})
+
+	if status != http.StatusOK {
+		t.Errorf("expected status %d, got %d with %+v", http.StatusOK, status, report.Components)
+	}
+}
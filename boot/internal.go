@@ -3,16 +3,22 @@ package boot
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"todo-api/web"
 )
 
 type (
-	// Config is a minimal config type for the boot layer (no external config package).
-	Config struct{}
-
 	// mux is the core structure that powers both Gin and other implementations.
 	mux[M any, R http.Handler] struct {
 		MiddlewareMapper MiddlewareMapper[M]
@@ -31,15 +37,18 @@ type (
 		handleJSONPost func(R, string, web.Handler)
 		handleJSONGet  func(R, string, web.Handler)
 
-		shutdownFn ShutDownFn
+		lifecycle   *lifecycle
+		health      *healthRegistry
+		diagnostics *diagnostics
+		shutdownFn  ShutDownFn
 	}
 
 	RouterFactory[M any, R http.Handler] func() (R, M)
-	TelemetryFactory                      func() (interface{}, bool)
-	ServerFactory[R http.Handler]         func(context.Context, R) Server
+	TelemetryFactory                     func(TracingConfig) (*sdktrace.TracerProvider, error)
+	ServerFactory[R http.Handler]        func(context.Context, ServerConfig, R) Server
 
-	PingMount[R http.Handler]   func(R, string, web.Handler)
-	OTELMount[M any]           func(M) func() error
+	PingMount[R http.Handler]  func(R, string, web.Handler)
+	OTELMount[M any]           func(M, *sdktrace.TracerProvider) ShutDownFn
 	PProfMount[R http.Handler] func(R)
 
 	MiddlewareMapper[M any] func(context.Context, Config, M)
@@ -57,11 +66,17 @@ type (
 	}
 )
 
-// NewHTTPServer returns an HTTP server configured with the provided handler.
-func NewHTTPServer(ctx context.Context, h http.Handler) Server {
-	port := getDefaultPort()
+// NewHTTPServer returns an HTTP server listening on the configured port with the configured timeouts.
+func NewHTTPServer(ctx context.Context, conf ServerConfig, h http.Handler) Server {
 	return &HTTPServerWrapper{
-		server: &http.Server{Addr: ":" + port, Handler: h},
+		server: &http.Server{
+			Addr:              ":" + strconv.Itoa(conf.Port),
+			Handler:           h,
+			ReadTimeout:       conf.ReadTimeout,
+			ReadHeaderTimeout: conf.ReadHeaderTimeout,
+			WriteTimeout:      conf.WriteTimeout,
+			IdleTimeout:       conf.IdleTimeout,
+		},
 	}
 }
 
@@ -98,6 +113,9 @@ func newMux[M any, R http.Handler](
 		useMiddlewares:   useMiddlewares,
 		handleJSONPost:   handleJSONPost,
 		handleJSONGet:    handleJSONGet,
+		lifecycle:        &lifecycle{},
+		health:           &healthRegistry{},
+		diagnostics:      newDiagnostics(),
 	}
 }
 
@@ -106,6 +124,9 @@ func (m *mux[M, R]) Run() error {
 	if err != nil {
 		return err
 	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	return m.run(ctx)
 }
 
@@ -124,34 +145,83 @@ func (m *mux[M, R]) Shutdown() error {
 }
 
 func (m *mux[M, R]) run(ctx context.Context) error {
+	conf, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	tp, err := m.newTelemetryFn(conf.Tracing)
+	if err != nil {
+		return err
+	}
+
 	mr, mm := m.newRouter()
-	conf := Config{}
+	if tp != nil {
+		// the layers below trace through the global provider, and outgoing calls
+		// propagate the trace with the W3C Trace Context headers
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+
+		// mounted before the mappers so that the server span covers their interceptors,
+		// and shut down last so that the spans of the drained requests are flushed
+		m.lifecycle.register("tracing", m.mountOtelFn(mm, tp))
+	}
+
+	var servers []Server
+	if conf.Admin.Enabled {
+		// registered first so that it shuts down last, and the shutdown can be diagnosed
+		admin := m.newAdminServer(ctx, conf)
+		m.lifecycle.register("admin server", admin.Shutdown)
+		servers = append(servers, admin)
+	}
 
 	m.MiddlewareMapper(ctx, conf, mm)
 	m.RoutesMapper(ctx, conf, mr)
 
-	sv := m.newServerFn(ctx, mr)
+	sv := m.newServerFn(ctx, conf.Server, mr)
+	var (
+		once        sync.Once
+		shutdownErr error
+	)
 	m.shutdownFn = func(ctx context.Context) error {
-		return sv.Shutdown(ctx)
+		once.Do(func() {
+			shutdownErr = m.lifecycle.shutdown(ctx, conf.Server, sv)
+		})
+		return shutdownErr
+	}
+
+	servers = append(servers, sv)
+	served := make(chan error, len(servers))
+	for _, s := range servers {
+		go func() {
+			served <- s.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			// a server could not start: still release what the mappers acquired
+			return errors.Join(err, m.shutdownFn(ctx))
+		}
+		// closed by Shutdown, wait for the drain and the hooks to be over
+		return m.shutdownFn(ctx)
+	case <-ctx.Done():
+		return m.shutdownFn(ctx)
 	}
-	return sv.ListenAndServe()
 }
 
 func (m *mux[M, R]) newRouter() (R, M) {
 	mr, mm := m.newRouterFn()
-	m.mountPProfFn(mr)
-	m.mountPingFn(mr, "/ping", web.NewHandlerPing())
+	m.mountPingFn(mr, "/ping", m.lifecycle.readiness(web.NewHandlerPing()))
+	m.handleJSONGet(mr, "/health/live", m.health.live())
+	m.handleJSONGet(mr, "/health/ready", m.health.ready(m.lifecycle))
 	return mr, mm
 }
 
 func (m *mux[M, R]) newBootableContext() (context.Context, error) {
-	return context.Background(), nil
-}
-
-func getDefaultPort() string {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	return port
+	ctx := m.lifecycle.context(context.Background())
+	ctx = m.health.context(ctx)
+	ctx = m.diagnostics.context(ctx)
+	return ctx, nil
 }
@@ -0,0 +1,101 @@
+package boot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"todo-api/web"
+)
+
+type (
+	// lifecycle tracks whether the application accepts traffic and the hooks releasing
+	// its resources once it stops.
+	lifecycle struct {
+		draining atomic.Bool
+
+		mu    sync.Mutex
+		hooks []shutdownHook
+	}
+
+	shutdownHook struct {
+		name string
+		fn   ShutDownFn
+	}
+
+	lifecycleKey struct{}
+)
+
+// OnShutdown registers a hook releasing a resource once the server has drained its
+// in-flight requests, e.g. closing a database. Hooks run in the reverse order of their
+// registration, so a resource is released before the ones it was built on, and all of
+// them run even when one fails. The context of a hook carries the deadline left from
+// the shutdown timeout.
+//
+// The context must be the one handed to the MiddlewareMapper or RoutesMapper; with any
+// other context the hook is never run.
+func OnShutdown(ctx context.Context, name string, fn ShutDownFn) {
+	if lc, ok := ctx.Value(lifecycleKey{}).(*lifecycle); ok {
+		lc.register(name, fn)
+	}
+}
+
+// Ready reports whether the application accepts traffic, that is, whether it has not
+// started shutting down. It is always true outside of the contexts handed to the mappers.
+func Ready(ctx context.Context) bool {
+	lc, ok := ctx.Value(lifecycleKey{}).(*lifecycle)
+	return !ok || !lc.draining.Load()
+}
+
+func (lc *lifecycle) register(name string, fn ShutDownFn) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.hooks = append(lc.hooks, shutdownHook{name: name, fn: fn})
+}
+
+func (lc *lifecycle) context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lifecycleKey{}, lc)
+}
+
+// readiness fails the handler with 503 once the application is shutting down, so that
+// load balancers stop routing to it while the in-flight requests drain.
+func (lc *lifecycle) readiness(h web.Handler) web.Handler {
+	return func(r web.Request) web.Response {
+		if lc.draining.Load() {
+			return web.NewResponse(http.StatusServiceUnavailable, []byte("shutting down"))
+		}
+		return h(r)
+	}
+}
+
+// shutdown flips the readiness, waits for the delay so that load balancers notice it,
+// then drains the server and runs the hooks, all within the timeout.
+func (lc *lifecycle) shutdown(ctx context.Context, conf ServerConfig, sv Server) error {
+	lc.draining.Store(true)
+
+	time.Sleep(conf.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), conf.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := sv.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("shutdown server: %w", err))
+	}
+
+	lc.mu.Lock()
+	hooks := lc.hooks
+	lc.hooks = nil
+	lc.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown %s: %w", hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,256 @@
+package boot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todo-api/web"
+	webgin "todo-api/web/gin"
+)
+
+type (
+	// fakeServer serves until it is shut down, and records how it was shut down.
+	fakeServer struct {
+		err error
+		// onShutdown runs at the start of Shutdown, e.g. to probe the handler while the
+		// requests drain.
+		onShutdown func()
+
+		started chan struct{}
+		closed  chan struct{}
+
+		mu         sync.Mutex
+		shutdowns  int
+		shutdownAt time.Time
+		// ctxErr and deadline are those of the context when Shutdown was called.
+		ctxErr   error
+		deadline bool
+	}
+)
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		started: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (s *fakeServer) ListenAndServe() error {
+	close(s.started)
+	<-s.closed
+	return http.ErrServerClosed
+}
+
+func (s *fakeServer) Shutdown(ctx context.Context) error {
+	if s.onShutdown != nil {
+		s.onShutdown()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdowns++
+	s.shutdownAt, s.ctxErr = time.Now(), ctx.Err()
+	_, s.deadline = ctx.Deadline()
+	if s.shutdowns == 1 {
+		close(s.closed)
+	}
+	return s.err
+}
+
+// serveHandler serves a single GET through the handler, as the Gin mux mounts it.
+func serveHandler(h web.Handler) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", webgin.NewHandlerRaw(h))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w
+}
+
+func shutdownConfig(delay, timeout time.Duration) ServerConfig {
+	return ServerConfig{ShutdownDelay: delay, ShutdownTimeout: timeout}
+}
+
+func TestLifecycle_ShutdownStopsReadinessBeforeTheServer(t *testing.T) {
+	lc := &lifecycle{}
+	ctx := lc.context(context.Background())
+	ping := lc.readiness(web.NewHandlerPing())
+	if w := serveHandler(ping); w.Code != http.StatusOK || !Ready(ctx) {
+		t.Fatalf("expected a ready application, got %d", w.Code)
+	}
+
+	sv := newFakeServer()
+	var probed *httptest.ResponseRecorder
+	var ready bool
+	sv.onShutdown = func() {
+		probed, ready = serveHandler(ping), Ready(ctx)
+	}
+
+	if err := lc.shutdown(context.Background(), shutdownConfig(0, time.Second), sv); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if probed.Code != http.StatusServiceUnavailable || ready {
+		t.Errorf("expected the application to be draining while the server shuts down, got %d", probed.Code)
+	}
+	if !strings.Contains(probed.Body.String(), "shutting down") {
+		t.Errorf("expected the body to tell why, got %q", probed.Body.String())
+	}
+}
+
+func TestLifecycle_ShutdownWaitsForTheDelayWhileDraining(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	lc := &lifecycle{}
+	ctx := lc.context(context.Background())
+	sv := newFakeServer()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- lc.shutdown(context.Background(), shutdownConfig(delay, time.Second), sv)
+	}()
+
+	deadline := time.Now().Add(delay / 2)
+	for Ready(ctx) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	sv.mu.Lock()
+	shutdowns := sv.shutdowns
+	sv.mu.Unlock()
+	if Ready(ctx) || shutdowns != 0 {
+		t.Errorf("expected the application to drain before the server shuts down, got ready %v and %d shutdowns", Ready(ctx), shutdowns)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if elapsed := sv.shutdownAt.Sub(start); elapsed < delay {
+		t.Errorf("expected the server to shut down after %s, got %s", delay, elapsed)
+	}
+}
+
+func TestLifecycle_ShutdownRunsEveryHookInReverseOrder(t *testing.T) {
+	lc := &lifecycle{}
+	ctx := lc.context(context.Background())
+
+	var ran []string
+	for _, name := range []string{"database", "cache", "queue"} {
+		OnShutdown(ctx, name, func(context.Context) error {
+			ran = append(ran, name)
+			if name == "cache" {
+				return errors.New("connection reset")
+			}
+			return nil
+		})
+	}
+	sv := newFakeServer()
+	sv.err = errors.New("listener closed")
+
+	err := lc.shutdown(context.Background(), shutdownConfig(0, time.Second), sv)
+
+	if want := []string{"queue", "cache", "database"}; !slices.Equal(ran, want) {
+		t.Errorf("expected hooks %v, got %v", want, ran)
+	}
+	if err == nil || !strings.Contains(err.Error(), "shutdown server: listener closed") || !strings.Contains(err.Error(), "shutdown cache: connection reset") {
+		t.Errorf("expected the errors of the server and the cache, got %v", err)
+	}
+
+	ran = nil
+	if err := lc.shutdown(context.Background(), shutdownConfig(0, time.Second), newFakeServer()); err != nil || len(ran) != 0 {
+		t.Errorf("expected the hooks to run once, got %v and %v", ran, err)
+	}
+}
+
+func TestLifecycle_ShutdownOutlivesTheCanceledSignalContextUntilTheTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	lc := &lifecycle{}
+	signalCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var hookErr error
+	lc.register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		hookErr = ctx.Err()
+		return hookErr
+	})
+	sv := newFakeServer()
+
+	start := time.Now()
+	err := lc.shutdown(signalCtx, shutdownConfig(0, timeout), sv)
+
+	if sv.ctxErr != nil || !sv.deadline {
+		t.Fatalf("expected the server to shut down with a live context bounded by the timeout, got %v", sv.ctxErr)
+	}
+	if !errors.Is(hookErr, context.DeadlineExceeded) {
+		t.Errorf("expected the hook to be stopped by the timeout, got %v", hookErr)
+	}
+	if elapsed := time.Since(start); elapsed < timeout {
+		t.Errorf("expected the hook to be given %s, got %s", timeout, elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "shutdown slow") {
+		t.Errorf("expected the error of the hook, got %v", err)
+	}
+}
+
+func TestGin_RunShutsDownOnceTheSignalContextIsDone(t *testing.T) {
+	isolateConfig(t, nil)
+	t.Setenv("DATABASE_DSN", testDSN)
+	t.Setenv("AUTH_DISABLED", "true")
+
+	var ran []string
+	hook := func(name string) ShutDownFn {
+		return func(context.Context) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	app := NewGin(
+		func(ctx context.Context, conf Config, router GinMiddlewareRouter) {
+			OnShutdown(ctx, "middlewares", hook("middlewares"))
+		},
+		func(ctx context.Context, conf Config, router GinRouter) {
+			OnShutdown(ctx, "routes", hook("routes"))
+		},
+	)
+	gin.SetMode(gin.TestMode)
+
+	servers := make(chan *fakeServer, 1)
+	ping := httptest.NewRecorder()
+	app.newServerFn = func(ctx context.Context, conf ServerConfig, r GinRouter) Server {
+		sv := newFakeServer()
+		sv.onShutdown = func() {
+			r.ServeHTTP(ping, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		}
+		servers <- sv
+		return sv
+	}
+
+	ctx, _ := app.newBootableContext()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- app.run(ctx)
+	}()
+	<-(<-servers).started
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ping.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected /ping to answer %d while draining, got %d", http.StatusServiceUnavailable, ping.Code)
+	}
+	if want := []string{"routes", "middlewares"}; !slices.Equal(ran, want) {
+		t.Errorf("expected hooks %v, got %v", want, ran)
+	}
+}
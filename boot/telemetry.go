@@ -0,0 +1,106 @@
+package boot
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"todo-api/web"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+type (
+	// fileExporter closes the file the spans are written to once the exporter is shut down.
+	fileExporter struct {
+		sdktrace.SpanExporter
+		file *os.File
+	}
+)
+
+// NewTracerProvider builds a provider batching the spans to the configured exporter, or
+// none when tracing is off.
+func NewTracerProvider(conf TracingConfig) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	switch conf.Exporter {
+	case TracingExporterStdout:
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		exporter = e
+	case TracingExporterFile:
+		f, err := os.OpenFile(conf.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			return nil, err
+		}
+		exporter = &fileExporter{SpanExporter: e, file: f}
+	default:
+		return nil, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(conf.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	if err := e.SpanExporter.Shutdown(ctx); err != nil {
+		return err
+	}
+	return e.file.Close()
+}
+
+// newTracingInterceptor starts the server span of every request, as the child of the
+// span of the caller when it sends a valid traceparent header. The layers below start
+// their spans from the context of the request.
+func newTracingInterceptor(tp trace.TracerProvider) web.Interceptor {
+	tracer := tp.Tracer("todo-api/boot")
+	return func(req web.InterceptedRequest) web.Response {
+		raw := req.Raw()
+		name, route := raw.Method, req.DeclaredPath()
+		if route != "" {
+			name += " " + route
+		}
+
+		ctx := propagation.TraceContext{}.Extract(req.Context(), propagation.HeaderCarrier(raw.Header))
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(raw.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(raw.URL.Path),
+			),
+		)
+		defer span.End()
+		req.Apply(ctx)
+
+		resp := req.Next()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.Status))
+		if resp.Status >= 500 {
+			span.SetStatus(codes.Error, "")
+		}
+		return resp
+	}
+}
@@ -0,0 +1,124 @@
+package boot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"todo-api/web"
+	webgin "todo-api/web/gin"
+)
+
+func serveTraced(t *testing.T, status int, header http.Header) sdktrace.ReadOnlySpan {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(webgin.NewInterceptor(newTracingInterceptor(tp)))
+	r.GET("/todos/:id", webgin.NewHandlerJSON(func(req web.Request) web.Response {
+		return web.NewJSONResponse(status, nil)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected a single span, got %d", len(spans))
+	}
+	return spans[0]
+}
+
+func TestTracingInterceptor_StartsServerSpanNamedAfterRoute(t *testing.T) {
+	span := serveTraced(t, http.StatusOK, nil)
+
+	if span.Name() != "GET /todos/:id" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("expected a GET /todos/:id server span, got %s %s", span.SpanKind(), span.Name())
+	}
+	if span.Parent().IsValid() {
+		t.Errorf("expected a new trace, got parent %s", span.Parent().SpanID())
+	}
+	if span.Status().Code == codes.Error {
+		t.Error("expected a successful span")
+	}
+}
+
+func TestTracingInterceptor_ContinuesTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	header := http.Header{"Traceparent": {"00-" + traceID + "-" + spanID + "-01"}}
+
+	span := serveTraced(t, http.StatusOK, header)
+
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("expected trace %s, got %s", traceID, got)
+	}
+	if got := span.Parent().SpanID().String(); got != spanID || !span.Parent().IsRemote() {
+		t.Errorf("expected remote parent %s, got %s", spanID, got)
+	}
+}
+
+func TestTracingInterceptor_IgnoresInvalidTraceparent(t *testing.T) {
+	header := http.Header{"Traceparent": {"00-00000000000000000000000000000000-00f067aa0ba902b7-01"}}
+
+	span := serveTraced(t, http.StatusOK, header)
+
+	if span.Parent().IsValid() {
+		t.Errorf("expected a new trace, got parent %s", span.Parent().SpanID())
+	}
+}
+
+func TestTracingInterceptor_FailsSpanOnServerError(t *testing.T) {
+	span := serveTraced(t, http.StatusInternalServerError, nil)
+
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected an error status, got %v", span.Status())
+	}
+}
+
+func TestNewTracerProvider_NoneDisablesTracing(t *testing.T) {
+	tp, err := NewTracerProvider(TracingConfig{Exporter: TracingExporterNone})
+
+	if err != nil || tp != nil {
+		t.Errorf("expected no provider, got %v, %v", tp, err)
+	}
+}
+
+func TestNewTracerProvider_FileExporterFlushesOnShutdown(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+	tp, err := NewTracerProvider(TracingConfig{Exporter: TracingExporterFile, File: file, ServiceName: "todo-api"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, span := tp.Tracer("test").Start(context.Background(), "GET /ping")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read the spans: %v", err)
+	}
+	if !strings.Contains(string(data), `"Name":"GET /ping"`) || !strings.Contains(string(data), `"todo-api"`) {
+		t.Errorf("expected the span of the service to be written, got %s", data)
+	}
+}
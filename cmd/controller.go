@@ -1,23 +1,121 @@
 package main
 
 import (
+	"database/sql"
+	"fmt"
 	"net/http"
 
+	"todo-api/boot"
+	"todo-api/pkg/auth"
 	"todo-api/pkg/controller"
 	"todo-api/pkg/domain"
 	"todo-api/web"
 )
 
-func NewTodoController() *controller.Todo {
-	uc := NewTodoUsecase()
+func NewTodoController(db *sql.DB, conf boot.Config) *controller.Todo {
+	uc := NewTodoUsecase(db, conf)
 	return controller.New(uc, newErrorHandler())
 }
 
+func NewCommentController(db *sql.DB, conf boot.Config) *controller.Comment {
+	uc := NewCommentUsecase(db, conf)
+	return controller.NewComment(uc, newErrorHandler())
+}
+
+func NewAttachmentController(db *sql.DB, conf boot.Config) *controller.Attachment {
+	uc := NewAttachmentUsecase(db, conf)
+	return controller.NewAttachment(uc, newErrorHandler())
+}
+
+func NewAPIKeyController(db *sql.DB, conf boot.Config) *controller.APIKey {
+	uc := NewAPIKeyUsecase(db, conf)
+	return controller.NewAPIKey(uc, newErrorHandler())
+}
+
+// NewAPIKeyInterceptor authenticates the clients sending an API key.
+func NewAPIKeyInterceptor(db *sql.DB, conf boot.Config) web.Interceptor {
+	return controller.NewAPIKeyInterceptor(NewAPIKeyUsecase(db, conf), newErrorHandler())
+}
+
+// NewAuthInterceptor requires a bearer JWT signed with a key of the JWKS file of the
+// configuration and issued for its audience, by its issuer when it is set. The API stays
+// unauthenticated only when auth.disabled is set, and /ping is always exempt.
+func NewAuthInterceptor(conf boot.Config) (web.Interceptor, bool) {
+	if conf.Auth.Disabled {
+		return nil, false
+	}
+
+	keys, err := auth.LoadJWKS(conf.Auth.JWKSFile)
+	if err != nil {
+		panic(fmt.Sprintf("invalid auth.jwks_file %q: %v", conf.Auth.JWKSFile, err))
+	}
+
+	var opts []auth.VerifierOption
+	if conf.Auth.Issuer != "" {
+		opts = append(opts, auth.WithIssuer(conf.Auth.Issuer))
+	}
+
+	return controller.NewAuthInterceptor(auth.NewVerifier(keys, conf.Auth.Audience, opts...), "/ping"), true
+}
+
 func newErrorHandler() web.ErrorHandler {
 	return web.NewErrorHandler(
 		web.NewErrorHandlerValueMapper(domain.ErrTodoNotFound, http.StatusNotFound),
 		web.NewErrorHandlerValueMapper(domain.ErrInvalidStatus, http.StatusBadRequest),
 		web.NewErrorHandlerValueMapper(domain.ErrInvalidPriority, http.StatusBadRequest),
 		web.NewErrorHandlerValueMapper(domain.ErrInvalidTitle, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidLimit, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidCursor, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidSort, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidSearchQuery, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrRelevanceSortQuery, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidDueAt, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidDueRange, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidOverdue, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidTransition, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrVersionMismatch, http.StatusPreconditionFailed),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBatchSize, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBatchMode, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBatchOp, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrDuplicateBatchID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrBatchAborted, http.StatusFailedDependency),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidParent, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrParentCycle, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrTodoHasChildren, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrParentTrashed, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidChildPolicy, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidInclude, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidTag, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrTooManyTags, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrConflictingTags, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidTagMatch, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidDependency, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrDependencyCycle, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrDependencyNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrTodoBlocked, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBlocked, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidRecurrence, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrCommentNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidCommentID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidCommentBody, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidUserID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAssignee, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrShareNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrAttachmentNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAttachmentID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrMissingAttachment, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAttachmentName, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge),
+		web.NewErrorHandlerValueMapper(domain.ErrUnsupportedAttachmentType, http.StatusUnsupportedMediaType),
+		web.NewErrorHandlerValueMapper(domain.ErrUnauthenticated, http.StatusUnauthorized),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKey, http.StatusUnauthorized),
+		web.NewErrorHandlerValueMapper(domain.ErrInsufficientScope, http.StatusForbidden),
+		web.NewErrorHandlerValueMapper(domain.ErrUnverifiedUser, http.StatusForbidden),
+		web.NewErrorHandlerValueMapper(domain.ErrForbidden, http.StatusForbidden),
+		web.NewErrorHandlerValueMapper(domain.ErrAPIKeyNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKeyID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKeyName, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKeyApp, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidScope, http.StatusBadRequest),
 	)
 }
@@ -2,17 +2,83 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"sync"
+	"time"
 
 	"todo-api/boot"
+	"todo-api/database"
+	"todo-api/pkg/controller"
+	webgin "todo-api/web/gin"
+)
+
+const (
+	// featureAPIKeys toggles the authentication of clients by API key and the routes managing keys.
+	featureAPIKeys = "api_keys"
+
+	// databaseHealthTimeout and databaseHealthCache bound the ping of the database by the
+	// readiness probe, so that a probe every few seconds per pod costs at most one ping.
+	databaseHealthTimeout = 2 * time.Second
+	databaseHealthCache   = 5 * time.Second
+)
+
+var (
+	sharedDB     *sql.DB
+	sharedDBOnce sync.Once
 )
 
 func main() {
 	boot.NewGin(
-		boot.DefaultGinMiddlewareMapper(),
+		middlewares,
 		setup,
 	).MustRun()
 }
 
-func setup(_ context.Context, _ boot.Config, router boot.GinRouter) {
-	registerTodoRoutes(router, NewTodoController())
+func middlewares(ctx context.Context, conf boot.Config, router boot.GinMiddlewareRouter) {
+	if conf.Enabled(featureAPIKeys) {
+		// a client authenticated by its API key needs no bearer token
+		router.Use(webgin.NewInterceptor(NewAPIKeyInterceptor(sharedDatabase(ctx, conf), conf)))
+	}
+	if auth, ok := NewAuthInterceptor(conf); ok {
+		router.Use(webgin.NewInterceptor(auth))
+	}
+	router.Use(webgin.NewInterceptor(controller.NewCallerInterceptor()))
+	// conditional GET buffers the response of the handler, so it stays the innermost interceptor
+	router.Use(webgin.NewInterceptor(webgin.NewConditionalGetInterceptor()))
+}
+
+func setup(ctx context.Context, conf boot.Config, router boot.GinRouter) {
+	db := sharedDatabase(ctx, conf)
+	registerTodoRoutes(router, NewTodoController(db, conf))
+	registerCommentRoutes(router, NewCommentController(db, conf))
+	registerAttachmentRoutes(router, NewAttachmentController(db, conf))
+	if conf.Enabled(featureAPIKeys) {
+		registerAPIKeyRoutes(router, NewAPIKeyController(db, conf))
+	}
+}
+
+// sharedDatabase opens the database once for the interceptors and the routes, checks it
+// for readiness, reports its pool to the diagnostics and closes it once the server has
+// drained.
+func sharedDatabase(ctx context.Context, conf boot.Config) *sql.DB {
+	sharedDBOnce.Do(func() {
+		sharedDB = database.NewDatabase(database.Config{
+			DSN:             conf.Database.DSN,
+			MaxOpenConns:    conf.Database.MaxOpenConns,
+			MaxIdleConns:    conf.Database.MaxIdleConns,
+			ConnMaxLifetime: conf.Database.ConnMaxLifetime,
+			ConnMaxIdleTime: conf.Database.ConnMaxIdleTime,
+		})
+		boot.RegisterHealthCheck(ctx, "database", sharedDB.PingContext,
+			boot.WithHealthCheckTimeout(databaseHealthTimeout),
+			boot.WithHealthCheckCache(databaseHealthCache),
+		)
+		boot.RegisterDiagnostics(ctx, "database", func() any {
+			return database.NewStats(sharedDB)
+		})
+		boot.OnShutdown(ctx, "database", func(context.Context) error {
+			return sharedDB.Close()
+		})
+	})
+	return sharedDB
 }
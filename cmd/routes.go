@@ -1,15 +1,83 @@
 package main
 
 import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
 	"todo-api/boot"
 	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/web"
 	webgin "todo-api/web/gin"
 )
 
+// Scope requirements of the routes, only enforced on clients authenticated by an API key.
+var (
+	read       = requireScope(domain.ScopeTodosRead)
+	write      = requireScope(domain.ScopeTodosWrite)
+	manageKeys = requireScope(domain.ScopeAPIKeysManage)
+)
+
 func registerTodoRoutes(router boot.GinRouter, ctrl *controller.Todo) {
-	router.GET("/api/todos", webgin.NewHandlerJSON(ctrl.Get))
-	router.GET("/api/todos/:id", webgin.NewHandlerJSON(ctrl.GetByID))
-	router.POST("/api/todos", webgin.NewHandlerJSON(ctrl.Create))
-	router.PATCH("/api/todos/:id", webgin.NewHandlerJSON(ctrl.Update))
-	router.DELETE("/api/todos/:id", webgin.NewHandlerJSON(ctrl.Delete))
+	router.GET("/api/todos", read, webgin.NewHandlerJSON(ctrl.Get))
+	router.GET("/api/todos/trash", read, webgin.NewHandlerJSON(ctrl.Trash))
+	router.DELETE("/api/todos/trash", write, webgin.NewHandlerJSON(ctrl.Purge))
+	router.GET("/api/todos/:id", read, webgin.NewHandlerJSON(ctrl.GetByID))
+	router.POST("/api/todos", write, webgin.NewHandlerJSON(ctrl.Create))
+	router.PATCH("/api/todos/:id", write, webgin.NewHandlerJSON(ctrl.Update))
+	router.DELETE("/api/todos/:id", write, webgin.NewHandlerJSON(ctrl.Delete))
+	router.POST("/api/todos/:id/restore", write, webgin.NewHandlerJSON(ctrl.Restore))
+	router.GET("/api/todos/:id/children", read, webgin.NewHandlerJSON(ctrl.Children))
+	router.GET("/api/todos/:id/dependencies", read, webgin.NewHandlerJSON(ctrl.Dependencies))
+	router.POST("/api/todos/:id/dependencies", write, webgin.NewHandlerJSON(ctrl.AddDependency))
+	router.DELETE("/api/todos/:id/dependencies", write, webgin.NewHandlerJSON(ctrl.RemoveDependency))
+	router.GET("/api/todos/:id/shares", read, webgin.NewHandlerJSON(ctrl.Shares))
+	router.POST("/api/todos/:id/shares", write, webgin.NewHandlerJSON(ctrl.Share))
+	router.DELETE("/api/todos/:id/shares", write, webgin.NewHandlerJSON(ctrl.Unshare))
+	router.GET("/api/todos/:id/occurrences", read, webgin.NewHandlerJSON(ctrl.Occurrences))
+	router.GET("/api/todos/:id/history", read, webgin.NewHandlerJSON(ctrl.History))
+	router.GET("/api/tags", read, webgin.NewHandlerJSON(ctrl.ListTags))
+
+	// gin only unescapes "\:" in literal paths when the engine is started through Run,
+	// so custom methods such as /api/todos:batch are matched with a parameter whose
+	// value keeps the leading colon.
+	router.POST("/api/todos:method", write, webgin.NewHandlerJSON(todoMethods(map[string]web.Handler{
+		":batch": ctrl.Batch,
+	})))
+}
+
+func registerCommentRoutes(router boot.GinRouter, ctrl *controller.Comment) {
+	router.GET("/api/todos/:id/comments", read, webgin.NewHandlerJSON(ctrl.Get))
+	router.POST("/api/todos/:id/comments", write, webgin.NewHandlerJSON(ctrl.Create))
+	router.PATCH("/api/todos/:id/comments/:commentId", write, webgin.NewHandlerJSON(ctrl.Update))
+	router.DELETE("/api/todos/:id/comments/:commentId", write, webgin.NewHandlerJSON(ctrl.Delete))
+}
+
+func registerAttachmentRoutes(router boot.GinRouter, ctrl *controller.Attachment) {
+	router.GET("/api/todos/:id/attachments", read, webgin.NewHandlerJSON(ctrl.Get))
+	router.POST("/api/todos/:id/attachments", write, webgin.NewHandlerJSON(ctrl.Create))
+	router.GET("/api/todos/:id/attachments/:attachmentId", read, webgin.NewHandlerJSON(ctrl.Download))
+}
+
+func registerAPIKeyRoutes(router boot.GinRouter, ctrl *controller.APIKey) {
+	router.GET("/api/keys", manageKeys, webgin.NewHandlerJSON(ctrl.Get))
+	router.POST("/api/keys", manageKeys, webgin.NewHandlerJSON(ctrl.Create))
+	router.DELETE("/api/keys/:id", manageKeys, webgin.NewHandlerJSON(ctrl.Delete))
+}
+
+func requireScope(scope string) gin.HandlerFunc {
+	return webgin.NewInterceptor(controller.NewScopeInterceptor(scope))
+}
+
+// todoMethods dispatches /api/todos:<method> to the handler registered for the method.
+func todoMethods(handlers map[string]web.Handler) web.Handler {
+	return func(req web.Request) web.Response {
+		method, _ := req.Param("method")
+		handler, ok := handlers[method]
+		if !ok {
+			return web.NewJSONResponseFromError(web.NewResponseError(http.StatusNotFound))
+		}
+		return handler(req)
+	}
 }
@@ -2,10 +2,33 @@ package main
 
 import (
 	"database/sql"
+	"fmt"
 
+	"todo-api/boot"
 	"todo-api/pkg/service"
 )
 
 func NewTodoService(db *sql.DB) service.Todo {
 	return service.New(db)
 }
+
+func NewCommentService(db *sql.DB) service.Comment {
+	return service.NewComment(db)
+}
+
+func NewAttachmentService(db *sql.DB) service.Attachment {
+	return service.NewAttachment(db)
+}
+
+func NewAPIKeyService(db *sql.DB) service.APIKey {
+	return service.NewAPIKey(db)
+}
+
+// NewBlobStore keeps attachments under the directory of the configuration.
+func NewBlobStore(conf boot.Config) service.BlobStore {
+	store, err := service.NewLocalBlobStore(conf.Attachments.Dir)
+	if err != nil {
+		panic(fmt.Sprintf("invalid attachments.dir %q: %v", conf.Attachments.Dir, err))
+	}
+	return store
+}
@@ -1,12 +1,78 @@
 package main
 
 import (
-	"todo-api/database"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"todo-api/boot"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
 	"todo-api/pkg/usecase"
 )
 
-func NewTodoUsecase() *usecase.Todo {
-	db := database.NewDatabase()
+var (
+	sharedPolicy     *policy.Policy
+	sharedPolicyOnce sync.Once
+)
+
+func NewTodoUsecase(db *sql.DB, conf boot.Config) *usecase.Todo {
 	svc := NewTodoService(db)
-	return usecase.New(svc)
+	opts := []usecase.Option{
+		usecase.WithTrashRetention(conf.Todos.TrashRetention),
+		usecase.WithPolicy(loadPolicy(conf)),
+		usecase.WithBlobStore(NewBlobStore(conf)),
+	}
+	if len(conf.Workflow.Transitions) > 0 {
+		opts = append(opts, usecase.WithWorkflow(newWorkflow(conf)))
+	}
+	return usecase.New(svc, opts...)
+}
+
+func NewCommentUsecase(db *sql.DB, conf boot.Config) *usecase.Comment {
+	return usecase.NewComment(NewCommentService(db), NewTodoService(db), usecase.WithCommentPolicy(loadPolicy(conf)))
+}
+
+func NewAttachmentUsecase(db *sql.DB, conf boot.Config) *usecase.Attachment {
+	return usecase.NewAttachment(
+		NewAttachmentService(db),
+		NewBlobStore(conf),
+		NewTodoService(db),
+		usecase.WithMaxAttachmentSize(conf.Attachments.MaxSize),
+		usecase.WithAttachmentPolicy(loadPolicy(conf)),
+	)
+}
+
+func NewAPIKeyUsecase(db *sql.DB, conf boot.Config) *usecase.APIKey {
+	return usecase.NewAPIKey(NewAPIKeyService(db), usecase.WithAPIKeyPolicy(loadPolicy(conf)))
+}
+
+// newWorkflow builds the workflow of the configuration, whose statuses Validate checked.
+func newWorkflow(conf boot.Config) domain.Workflow {
+	transitions := make(map[domain.Status][]domain.Status, len(conf.Workflow.Transitions))
+	for from, tos := range conf.Workflow.Transitions {
+		statuses := make([]domain.Status, len(tos))
+		for i, to := range tos {
+			statuses[i] = domain.Status(to)
+		}
+		transitions[domain.Status(from)] = statuses
+	}
+	return domain.MustNewWorkflow(transitions)
+}
+
+// loadPolicy reads the YAML policy file of the configuration once. Without it every user
+// can do everything.
+func loadPolicy(conf boot.Config) *policy.Policy {
+	sharedPolicyOnce.Do(func() {
+		if conf.Policy.File == "" {
+			return
+		}
+
+		p, err := policy.Load(conf.Policy.File)
+		if err != nil {
+			panic(fmt.Sprintf("invalid policy.file %q: %v", conf.Policy.File, err))
+		}
+		sharedPolicy = p
+	})
+	return sharedPolicy
 }
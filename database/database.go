@@ -4,14 +4,9 @@ import (
 	"database/sql"
 )
 
-func NewDatabase() *sql.DB {
-	db, err := NewPostgres(Config{
-		Host:     "localhost",
-		Port:     5432,
-		User:     "postgres",
-		Password: "postgres",
-		Database: "todos_db",
-	})
+// NewDatabase opens the Postgres database of the config, panicking when it cannot be reached.
+func NewDatabase(cfg Config) *sql.DB {
+	db, err := NewPostgres(cfg)
 	if err != nil {
 		panic(err)
 	}
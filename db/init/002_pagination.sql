@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_todos_created_at_id ON todos(created_at DESC, id DESC);
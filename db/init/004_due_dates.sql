@@ -0,0 +1,3 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS due_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_todos_due_at ON todos(due_at);
@@ -0,0 +1,3 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+UPDATE todos SET completed_at = updated_at WHERE status = 'completed' AND completed_at IS NULL;
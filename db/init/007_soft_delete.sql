@@ -0,0 +1,3 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at_id ON todos(deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES todos(id) ON DELETE SET NULL;
+
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_parent_not_self;
+ALTER TABLE todos ADD CONSTRAINT todos_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_todos_parent_id ON todos(parent_id) WHERE parent_id IS NOT NULL;
@@ -0,0 +1,11 @@
+-- comments stay attached to a todo moved to the trash, and come back with it when it is
+-- restored; purging the todo removes them
+CREATE TABLE IF NOT EXISTS todo_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_comments_todo_id ON todo_comments(todo_id, created_at, id);
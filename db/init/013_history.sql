@@ -0,0 +1,14 @@
+-- one row per change made to a todo; changes holds the field-level diff as a list of
+-- {field, before, after} objects. The history goes with the todo when it is purged
+CREATE TABLE IF NOT EXISTS todo_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    caller_app VARCHAR(255) NOT NULL DEFAULT '',
+    caller_scope VARCHAR(255) NOT NULL DEFAULT '',
+    caller_user VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_history_todo_id ON todo_history(todo_id, created_at DESC, id DESC);
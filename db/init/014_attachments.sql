@@ -0,0 +1,14 @@
+-- the content of an attachment lives in the blob store under storage_key; like comments,
+-- attachments follow their todo to the trash and back, and purging the todo removes both
+-- the rows and their blobs
+CREATE TABLE IF NOT EXISTS todo_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_attachments_todo_id ON todo_attachments(todo_id, created_at, id);
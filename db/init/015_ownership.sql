@@ -0,0 +1,15 @@
+-- todos created before ownership existed have no owner and stay visible to everyone
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255);
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS assignee_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_todos_owner_id ON todos(owner_id);
+CREATE INDEX IF NOT EXISTS idx_todos_assignee_id ON todos(assignee_id);
+
+CREATE TABLE IF NOT EXISTS todo_shares (
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (todo_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_shares_user_id ON todo_shares(user_id);
@@ -0,0 +1,14 @@
+-- only the SHA-256 of a key is stored; the key itself is shown once, when it is issued,
+-- and its prefix is kept so that it can be recognized in listings
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    app VARCHAR(255) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_app ON api_keys(app, created_at);
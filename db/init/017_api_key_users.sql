@@ -0,0 +1,3 @@
+-- the user a key acts for, if any: requests authenticated by the key see and change
+-- the todos of that user, as if they bore a token of theirs
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS user_id VARCHAR(255);
@@ -0,0 +1,3 @@
+-- the user who wrote a comment, if any: only they and admins may edit or delete it.
+-- comments written before authors were recorded, or without a user, have none
+ALTER TABLE todo_comments ADD COLUMN IF NOT EXISTS author VARCHAR(255);
@@ -0,0 +1,17 @@
+package auth
+
+import "errors"
+
+var (
+	ErrMissingToken         = errors.New("missing bearer token")
+	ErrMalformedToken       = errors.New("invalid token: must be a compact JWS")
+	ErrUnsupportedAlgorithm = errors.New("invalid token: alg must be HS256 or RS256")
+	ErrUnknownKey           = errors.New("invalid token: signed with an unknown key")
+	ErrInvalidSignature     = errors.New("invalid token: signature does not match")
+	ErrTokenExpired         = errors.New("invalid token: expired")
+	ErrTokenNotYetValid     = errors.New("invalid token: not valid yet")
+	ErrInvalidAudience      = errors.New("invalid token: not issued for this audience")
+	ErrInvalidIssuer        = errors.New("invalid token: not issued by a trusted issuer")
+	ErrMissingSubject       = errors.New("invalid token: missing sub")
+	ErrInvalidJWKS          = errors.New("invalid JWKS: must hold at least one oct or RSA signing key")
+)
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+type (
+	// Key is a verification key of a KeySet. HMAC keys verify HS256 tokens and RSA
+	// public keys RS256 ones; a key is never used for another algorithm.
+	Key struct {
+		ID        string
+		Algorithm string
+
+		secret    []byte
+		publicKey *rsa.PublicKey
+	}
+
+	// KeySet holds the keys tokens may be signed with.
+	KeySet struct {
+		keys []Key
+	}
+
+	jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		K   string `json:"k"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+)
+
+// NewHMACKey returns an HS256 key.
+func NewHMACKey(id string, secret []byte) Key {
+	return Key{ID: id, Algorithm: AlgHS256, secret: secret}
+}
+
+// NewRSAKey returns an RS256 key.
+func NewRSAKey(id string, publicKey *rsa.PublicKey) Key {
+	return Key{ID: id, Algorithm: AlgRS256, publicKey: publicKey}
+}
+
+func NewKeySet(keys ...Key) KeySet {
+	return KeySet{keys: keys}
+}
+
+// LoadJWKS reads a JSON Web Key Set (RFC 7517) from a local file.
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return KeySet{}, err
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS reads the oct (HS256) and RSA (RS256) signing keys of a JSON Web Key Set.
+// Keys of other types or meant for encryption are skipped, but a set left without
+// any key is refused.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return KeySet{}, fmt.Errorf("%w: %v", ErrInvalidJWKS, err)
+	}
+
+	var keys []Key
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var key Key
+		var err error
+		switch k.Kty {
+		case "oct":
+			key, err = k.hmacKey()
+		case "RSA":
+			key, err = k.rsaKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return KeySet{}, fmt.Errorf("%w: key %q: %v", ErrInvalidJWKS, k.Kid, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return KeySet{}, ErrInvalidJWKS
+	}
+	return NewKeySet(keys...), nil
+}
+
+func (k jwk) hmacKey() (Key, error) {
+	if k.Alg != "" && k.Alg != AlgHS256 {
+		return Key{}, fmt.Errorf("unsupported alg %q", k.Alg)
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(k.K)
+	if err != nil || len(secret) == 0 {
+		return Key{}, fmt.Errorf("k must be a non empty base64url value")
+	}
+	return NewHMACKey(k.Kid, secret), nil
+}
+
+func (k jwk) rsaKey() (Key, error) {
+	if k.Alg != "" && k.Alg != AlgRS256 {
+		return Key{}, fmt.Errorf("unsupported alg %q", k.Alg)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil || len(n) == 0 {
+		return Key{}, fmt.Errorf("n must be a non empty base64url value")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return Key{}, fmt.Errorf("e must be a base64url value of at most 4 bytes")
+	}
+	publicKey := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	return NewRSAKey(k.Kid, publicKey), nil
+}
+
+// find returns the key a token signed with alg and kid must be verified with. Without
+// a kid the token can only match when the set holds a single key for the algorithm.
+func (s KeySet) find(alg, kid string) (Key, bool) {
+	var found Key
+	var count int
+	for _, k := range s.keys {
+		if k.Algorithm != alg {
+			continue
+		}
+		if kid != "" && k.ID == kid {
+			return k, true
+		}
+		found = k
+		count++
+	}
+	return found, kid == "" && count == 1
+}
@@ -0,0 +1,77 @@
+package auth_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"todo-api/pkg/auth"
+)
+
+func TestLoadJWKS(t *testing.T) {
+	key := newRSAKey(t)
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "oct", "kid": "hmac", "alg": "HS256", "k": %q},
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": "x", "y": "y"}
+	]}`,
+		base64.RawURLEncoding.EncodeToString(secret),
+		encodeInt(key.N), encodeInt(big.NewInt(int64(key.E))),
+		encodeInt(key.N), encodeInt(big.NewInt(int64(key.E))),
+	)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(jwks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := auth.LoadJWKS(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	verifier := newVerifier(keys)
+	if _, err := verifier.Verify(sign(t, map[string]any{"alg": "HS256", "kid": "hmac"}, validClaims(), secret)); err != nil {
+		t.Errorf("unexpected error for the HMAC key: %v", err)
+	}
+	if _, err := verifier.Verify(sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, validClaims(), key)); err != nil {
+		t.Errorf("unexpected error for the RSA key: %v", err)
+	}
+	if _, err := verifier.Verify(sign(t, map[string]any{"alg": "RS256", "kid": "enc"}, validClaims(), key)); !errors.Is(err, auth.ErrUnknownKey) {
+		t.Errorf("expected the encryption key to be skipped, got %v", err)
+	}
+}
+
+func TestLoadJWKS_MissingFile(t *testing.T) {
+	if _, err := auth.LoadJWKS(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+}
+
+func TestParseJWKS_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":         `{`,
+		"no keys":          `{"keys": []}`,
+		"only unsupported": `{"keys": [{"kty": "EC", "crv": "P-256"}]}`,
+		"empty secret":     `{"keys": [{"kty": "oct", "k": ""}]}`,
+		"bad modulus":      `{"keys": [{"kty": "RSA", "n": "!!", "e": "AQAB"}]}`,
+		"mismatched alg":   `{"keys": [{"kty": "oct", "alg": "RS256", "k": "c2VjcmV0"}]}`,
+	}
+
+	for name, jwks := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := auth.ParseJWKS([]byte(jwks)); !errors.Is(err, auth.ErrInvalidJWKS) {
+				t.Errorf("expected ErrInvalidJWKS, got %v", err)
+			}
+		})
+	}
+}
+
+func encodeInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
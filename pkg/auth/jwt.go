@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// Claims are the claims of a verified token. Extra holds every claim of the
+	// token, the registered ones included.
+	Claims struct {
+		Subject   string
+		Issuer    string
+		Audience  []string
+		ExpiresAt time.Time
+		NotBefore *time.Time
+		IssuedAt  *time.Time
+		Extra     map[string]any
+	}
+
+	// Verifier checks the signature and the registered claims of JWTs. Tokens must be
+	// signed with HS256 or RS256 by a key of its set, carry exp and sub claims, and be
+	// issued for its audience, so that a token meant for another service is refused.
+	Verifier struct {
+		keys     KeySet
+		audience string
+		issuer   string
+		leeway   time.Duration
+		now      func() time.Time
+	}
+
+	VerifierOption func(*Verifier)
+
+	claimsKey struct{}
+)
+
+// WithIssuer only accepts tokens whose iss claim is the issuer.
+func WithIssuer(issuer string) VerifierOption {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithLeeway tolerates that much clock skew when checking exp and nbf.
+func WithLeeway(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = d
+	}
+}
+
+// WithClock replaces time.Now as the source of the current time.
+func WithClock(now func() time.Time) VerifierOption {
+	return func(v *Verifier) {
+		v.now = now
+	}
+}
+
+// NewVerifier only accepts tokens whose aud claim names the audience. An empty audience
+// accepts no token.
+func NewVerifier(keys KeySet, audience string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		keys:     keys,
+		audience: audience,
+		now:      time.Now,
+	}
+	for _, o := range opts {
+		o(v)
+	}
+	return v
+}
+
+// Verify checks a compact serialized JWT and returns its claims.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	if header.Alg != AlgHS256 && header.Alg != AlgRS256 {
+		return Claims{}, ErrUnsupportedAlgorithm
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+
+	key, ok := v.keys.find(header.Alg, header.Kid)
+	if !ok {
+		return Claims{}, ErrUnknownKey
+	}
+	if !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	var payload map[string]any
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	claims, err := parseClaims(payload)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	return claims, v.validate(claims)
+}
+
+// validate checks the registered claims once the signature is known to be good.
+func (v *Verifier) validate(claims Claims) error {
+	now := v.now()
+	if !now.Before(claims.ExpiresAt.Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != nil && now.Add(v.leeway).Before(*claims.NotBefore) {
+		return ErrTokenNotYetValid
+	}
+	if v.audience == "" || !slices.Contains(claims.Audience, v.audience) {
+		return ErrInvalidAudience
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrInvalidIssuer
+	}
+	if claims.Subject == "" {
+		return ErrMissingSubject
+	}
+	return nil
+}
+
+func (k Key) verify(signed, signature []byte) bool {
+	switch k.Algorithm {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), signature)
+	case AlgRS256:
+		digest := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(k.publicKey, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// parseClaims reads the registered claims; exp is required, and aud may be a single
+// string or a list of them.
+func parseClaims(payload map[string]any) (Claims, error) {
+	claims := Claims{Extra: payload}
+
+	var ok bool
+	if v, set := payload["sub"]; set {
+		if claims.Subject, ok = v.(string); !ok {
+			return Claims{}, ErrMalformedToken
+		}
+	}
+	if v, set := payload["iss"]; set {
+		if claims.Issuer, ok = v.(string); !ok {
+			return Claims{}, ErrMalformedToken
+		}
+	}
+
+	switch aud := payload["aud"].(type) {
+	case nil:
+	case string:
+		claims.Audience = []string{aud}
+	case []any:
+		for _, a := range aud {
+			s, ok := a.(string)
+			if !ok {
+				return Claims{}, ErrMalformedToken
+			}
+			claims.Audience = append(claims.Audience, s)
+		}
+	default:
+		return Claims{}, ErrMalformedToken
+	}
+
+	exp, err := numericDate(payload, "exp")
+	if err != nil {
+		return Claims{}, err
+	}
+	if exp == nil {
+		return Claims{}, ErrMalformedToken
+	}
+	claims.ExpiresAt = *exp
+
+	if claims.NotBefore, err = numericDate(payload, "nbf"); err != nil {
+		return Claims{}, err
+	}
+	if claims.IssuedAt, err = numericDate(payload, "iat"); err != nil {
+		return Claims{}, err
+	}
+
+	return claims, nil
+}
+
+// numericDate reads a claim holding seconds since the epoch, nil when it is absent.
+func numericDate(payload map[string]any, name string) (*time.Time, error) {
+	v, set := payload[name]
+	if !set {
+		return nil, nil
+	}
+	seconds, ok := v.(float64)
+	if !ok {
+		return nil, ErrMalformedToken
+	}
+	t := time.Unix(0, 0).Add(time.Duration(seconds * float64(time.Second))).UTC()
+	return &t, nil
+}
+
+// WithClaims returns a copy of ctx carrying the claims of the token the request was
+// authenticated with.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims carried by ctx, if any.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
@@ -0,0 +1,227 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/pkg/auth"
+)
+
+var (
+	now    = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret = []byte("0123456789abcdef0123456789abcdef")
+)
+
+func sign(t *testing.T, header, claims map[string]any, key any) string {
+	t.Helper()
+
+	h, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "alice",
+		"iss": "https://issuer.example.com",
+		"aud": "todo-api",
+		"exp": now.Add(time.Hour).Unix(),
+		"nbf": now.Add(-time.Minute).Unix(),
+		"iat": now.Add(-time.Minute).Unix(),
+		"org": "acme",
+	}
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newVerifier(keys auth.KeySet, opts ...auth.VerifierOption) *auth.Verifier {
+	opts = append([]auth.VerifierOption{auth.WithClock(func() time.Time { return now })}, opts...)
+	return auth.NewVerifier(keys, "todo-api", opts...)
+}
+
+func TestVerifier_Verify_HS256(t *testing.T) {
+	verifier := newVerifier(auth.NewKeySet(auth.NewHMACKey("hmac", secret)), auth.WithIssuer("https://issuer.example.com"))
+	token := sign(t, map[string]any{"alg": "HS256", "typ": "JWT", "kid": "hmac"}, validClaims(), secret)
+
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if claims.Subject != "alice" {
+		t.Errorf("expected subject alice, got %q", claims.Subject)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != "todo-api" {
+		t.Errorf("expected audience [todo-api], got %v", claims.Audience)
+	}
+	if !claims.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected expiry %v, got %v", now.Add(time.Hour), claims.ExpiresAt)
+	}
+	if claims.NotBefore == nil || !claims.NotBefore.Equal(now.Add(-time.Minute)) {
+		t.Errorf("expected not before %v, got %v", now.Add(-time.Minute), claims.NotBefore)
+	}
+	if claims.Extra["org"] != "acme" {
+		t.Errorf("expected org claim acme, got %v", claims.Extra["org"])
+	}
+}
+
+func TestVerifier_Verify_RS256(t *testing.T) {
+	key := newRSAKey(t)
+	verifier := newVerifier(auth.NewKeySet(auth.NewRSAKey("rsa", &key.PublicKey)))
+
+	claims := validClaims()
+	claims["aud"] = []string{"other", "todo-api"}
+	token := sign(t, map[string]any{"alg": "RS256"}, claims, key)
+
+	got, err := verifier.Verify(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Audience) != 2 {
+		t.Errorf("expected 2 audiences, got %v", got.Audience)
+	}
+}
+
+func TestVerifier_Verify_Errors(t *testing.T) {
+	key := newRSAKey(t)
+	otherKey := newRSAKey(t)
+	keys := auth.NewKeySet(auth.NewHMACKey("hmac", secret), auth.NewRSAKey("rsa", &key.PublicKey))
+	verifier := newVerifier(keys, auth.WithIssuer("https://issuer.example.com"))
+
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"not a jws", "abc.def", auth.ErrMalformedToken},
+		{"bad header", "!!!.e30.", auth.ErrMalformedToken},
+		{"alg none", sign(t, map[string]any{"alg": "none"}, validClaims(), nil), auth.ErrUnsupportedAlgorithm},
+		{"alg HS512", sign(t, map[string]any{"alg": "HS512", "kid": "hmac"}, validClaims(), secret), auth.ErrUnsupportedAlgorithm},
+		{"unknown kid", sign(t, map[string]any{"alg": "HS256", "kid": "other"}, validClaims(), secret), auth.ErrUnknownKey},
+		{"kid of another alg", sign(t, map[string]any{"alg": "HS256", "kid": "rsa"}, validClaims(), secret), auth.ErrUnknownKey},
+		{"wrong secret", sign(t, map[string]any{"alg": "HS256", "kid": "hmac"}, validClaims(), []byte("wrong")), auth.ErrInvalidSignature},
+		{"wrong rsa key", sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, validClaims(), otherKey), auth.ErrInvalidSignature},
+		{"expired", sign(t, map[string]any{"alg": "HS256"}, with("exp", now.Unix()), secret), auth.ErrTokenExpired},
+		{"missing exp", sign(t, map[string]any{"alg": "HS256"}, with("exp", nil), secret), auth.ErrMalformedToken},
+		{"not yet valid", sign(t, map[string]any{"alg": "HS256"}, with("nbf", now.Add(time.Minute).Unix()), secret), auth.ErrTokenNotYetValid},
+		{"wrong audience", sign(t, map[string]any{"alg": "HS256"}, with("aud", "other"), secret), auth.ErrInvalidAudience},
+		{"missing audience", sign(t, map[string]any{"alg": "HS256"}, with("aud", nil), secret), auth.ErrInvalidAudience},
+		{"wrong issuer", sign(t, map[string]any{"alg": "HS256"}, with("iss", "https://evil.example.com"), secret), auth.ErrInvalidIssuer},
+		{"missing subject", sign(t, map[string]any{"alg": "HS256"}, with("sub", nil), secret), auth.ErrMissingSubject},
+		{"numeric subject", sign(t, map[string]any{"alg": "HS256"}, with("sub", 42), secret), auth.ErrMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.Verify(tt.token); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify_RSAPublicKeyAsHMACSecret(t *testing.T) {
+	key := newRSAKey(t)
+	verifier := newVerifier(auth.NewKeySet(auth.NewRSAKey("rsa", &key.PublicKey)))
+
+	// a token forged by using the public modulus as an HMAC secret must not pass as RS256
+	token := sign(t, map[string]any{"alg": "HS256", "kid": "rsa"}, validClaims(), key.PublicKey.N.Bytes())
+	if _, err := verifier.Verify(token); !errors.Is(err, auth.ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestVerifier_Verify_WithoutAudienceAcceptsNoToken(t *testing.T) {
+	verifier := auth.NewVerifier(auth.NewKeySet(auth.NewHMACKey("", secret)), "", auth.WithClock(func() time.Time { return now }))
+
+	for _, aud := range []any{nil, "", "todo-api"} {
+		claims := validClaims()
+		claims["aud"] = aud
+		if _, err := verifier.Verify(sign(t, map[string]any{"alg": "HS256"}, claims, secret)); !errors.Is(err, auth.ErrInvalidAudience) {
+			t.Errorf("expected ErrInvalidAudience for aud %v, got %v", aud, err)
+		}
+	}
+}
+
+func TestVerifier_Verify_Leeway(t *testing.T) {
+	verifier := newVerifier(auth.NewKeySet(auth.NewHMACKey("", secret)), auth.WithLeeway(time.Minute))
+
+	claims := validClaims()
+	claims["exp"] = now.Add(-30 * time.Second).Unix()
+	claims["nbf"] = now.Add(30 * time.Second).Unix()
+	if _, err := verifier.Verify(sign(t, map[string]any{"alg": "HS256"}, claims, secret)); err != nil {
+		t.Errorf("expected the leeway to absorb the skew, got %v", err)
+	}
+}
+
+func TestVerifier_Verify_AmbiguousKey(t *testing.T) {
+	keys := auth.NewKeySet(auth.NewHMACKey("a", secret), auth.NewHMACKey("b", []byte("other")))
+	verifier := newVerifier(keys)
+
+	if _, err := verifier.Verify(sign(t, map[string]any{"alg": "HS256"}, validClaims(), secret)); !errors.Is(err, auth.ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey without kid, got %v", err)
+	}
+	if _, err := verifier.Verify(sign(t, map[string]any{"alg": "HS256", "kid": "a"}, validClaims(), secret)); err != nil {
+		t.Errorf("unexpected error with kid: %v", err)
+	}
+}
+
+func TestClaimsFromContext(t *testing.T) {
+	if _, ok := auth.ClaimsFromContext(context.Background()); ok {
+		t.Error("expected no claims in an empty context")
+	}
+
+	ctx := auth.WithClaims(context.Background(), auth.Claims{Subject: "alice"})
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok || claims.Subject != "alice" {
+		t.Errorf("expected alice's claims, got %v %v", claims, ok)
+	}
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"net/http"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/usecase"
+	"todo-api/web"
+)
+
+type (
+	APIKey struct {
+		usecase    *usecase.APIKey
+		errHandler web.ErrorHandler
+	}
+
+	APIKeyResponse struct {
+		ID        string   `json:"id"`
+		Name      string   `json:"name"`
+		App       string   `json:"app"`
+		Scopes    []string `json:"scopes"`
+		Prefix    string   `json:"prefix"`
+		User      string   `json:"user,omitempty"`
+		CreatedAt string   `json:"created_at"`
+		RevokedAt *string  `json:"revoked_at,omitempty"`
+	}
+
+	// IssuedAPIKeyResponse is the only response holding the key itself.
+	IssuedAPIKeyResponse struct {
+		APIKeyResponse
+		Key string `json:"key"`
+	}
+
+	ListAPIKeysResponse struct {
+		Data []APIKeyResponse `json:"data"`
+	}
+
+	IssuedAPIKeyDataResponse struct {
+		Data IssuedAPIKeyResponse `json:"data"`
+	}
+
+	// APIKeyRequest is the body of the issue of an API key. The key acts for the caller;
+	// User binds it to another user, which only an admin may do.
+	APIKeyRequest struct {
+		Name   string   `json:"name"`
+		App    string   `json:"app"`
+		Scopes []string `json:"scopes"`
+		User   string   `json:"user,omitempty"`
+	}
+)
+
+func NewAPIKey(uc *usecase.APIKey, errHandler web.ErrorHandler) *APIKey {
+	return &APIKey{
+		usecase:    uc,
+		errHandler: errHandler,
+	}
+}
+
+// Get lists every API key, revoked ones included.
+func (c *APIKey) Get(req web.Request) web.Response {
+	output, err := c.usecase.List(req.Context())
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := ListAPIKeysResponse{Data: make([]APIKeyResponse, len(output.Keys))}
+	for i, key := range output.Keys {
+		response.Data[i] = MapAPIKeyToResponse(key)
+	}
+
+	return web.NewJSONResponse(http.StatusOK, response)
+}
+
+// Create issues a key. Its secret is in the response and cannot be retrieved afterwards.
+func (c *APIKey) Create(req web.Request) web.Response {
+	var body APIKeyRequest
+	if err := web.DecodeJSON(req.Body(), &body); err != nil {
+		return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+	}
+
+	if err := domain.ValidateAPIKeyName(body.Name); err != nil {
+		return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+	}
+	if err := domain.ValidateAPIKeyApp(body.App); err != nil {
+		return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+	}
+	if err := domain.ValidateScopes(body.Scopes); err != nil {
+		return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+	}
+	if body.User != "" {
+		if err := domain.ValidateUserID(body.User); err != nil {
+			return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+		}
+	}
+
+	output, err := c.usecase.Issue(req.Context(), usecase.IssueAPIKeyInput{
+		Name:   body.Name,
+		App:    body.App,
+		Scopes: body.Scopes,
+		User:   body.User,
+	})
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := IssuedAPIKeyDataResponse{
+		Data: IssuedAPIKeyResponse{
+			APIKeyResponse: MapAPIKeyToResponse(output.Key),
+			Key:            output.Secret,
+		},
+	}
+
+	return web.NewJSONResponse(http.StatusCreated, response)
+}
+
+// Delete revokes a key. Revoked keys stay listed but no longer authenticate anybody.
+func (c *APIKey) Delete(req web.Request) web.Response {
+	id, ok := req.Param("id")
+	if !ok || domain.ValidateUUID(id) != nil {
+		return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidAPIKeyID))
+	}
+
+	if err := c.usecase.Revoke(req.Context(), id); err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusNoContent, nil)
+}
+
+func MapAPIKeyToResponse(key domain.APIKey) APIKeyResponse {
+	response := APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		App:       key.App,
+		Scopes:    key.Scopes,
+		Prefix:    key.Prefix,
+		User:      key.User,
+		CreatedAt: key.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if key.RevokedAt != nil {
+		revokedAt := key.RevokedAt.Format("2006-01-02T15:04:05Z")
+		response.RevokedAt = &revokedAt
+	}
+	return response
+}
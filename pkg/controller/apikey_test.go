@@ -0,0 +1,378 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+	"todo-api/web"
+)
+
+const apiKeyUUID = "723e4567-e89b-12d3-a456-426614174000"
+
+func newAPIKeyController(mock *test.MockAPIKeyService) *controller.APIKey {
+	return controller.NewAPIKey(usecase.NewAPIKey(mock), newErrorHandler())
+}
+
+// asCaller authenticates the request as the user.
+func asCaller(req *test.MockRequest, user string) *test.MockRequest {
+	req.Ctx = domain.WithCaller(req.Ctx, domain.Caller{User: user})
+	return req
+}
+
+// issueAPIKey issues a key through the usecase and returns its secret, the mock keeping
+// the key by hash so that it authenticates afterwards.
+func issueAPIKey(t *testing.T, scopes ...string) (*usecase.APIKey, string) {
+	t.Helper()
+
+	keys := map[string]domain.APIKey{}
+	uc := usecase.NewAPIKey(&test.MockAPIKeyService{
+		CreateFn: func(ctx context.Context, input service.APIKeyInput) (domain.APIKey, error) {
+			key := domain.APIKey{ID: apiKeyUUID, Name: input.Name, App: input.App, Scopes: input.Scopes, Prefix: input.Prefix}
+			keys[input.Hash] = key
+			return key, nil
+		},
+		GetByHashFn: func(ctx context.Context, hash string) (domain.APIKey, error) {
+			key, ok := keys[hash]
+			if !ok {
+				return domain.APIKey{}, domain.ErrAPIKeyNotFound
+			}
+			return key, nil
+		},
+	})
+
+	ctx := domain.WithCaller(context.Background(), domain.Caller{User: "alice"})
+	output, err := uc.Issue(ctx, usecase.IssueAPIKeyInput{Name: "reminders", App: "reminders-job", Scopes: scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uc, output.Secret
+}
+
+func TestAPIKeyController_Get_Successfully(t *testing.T) {
+	revokedAt := fixedTime
+	ctrl := newAPIKeyController(&test.MockAPIKeyService{
+		ListFn: func(ctx context.Context) ([]domain.APIKey, error) {
+			return []domain.APIKey{
+				{ID: apiKeyUUID, Name: "reminders", App: "reminders-job", Scopes: []string{domain.ScopeTodosRead}, Prefix: "tk_abcdefgh", CreatedAt: fixedTime},
+				{ID: apiKeyUUID, Name: "old", App: "reminders-job", Scopes: []string{domain.ScopeTodosRead}, Prefix: "tk_ijklmnop", CreatedAt: fixedTime, RevokedAt: &revokedAt},
+			}, nil
+		},
+	})
+
+	response := ctrl.Get(asCaller(test.NewMockRequest(), "alice"))
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.ListAPIKeysResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Data) != 2 || body.Data[0].RevokedAt != nil || body.Data[1].RevokedAt == nil {
+		t.Errorf("expected a live and a revoked key, got %+v", body.Data)
+	}
+	if strings.Contains(string(response.Body), `"key"`) {
+		t.Errorf("expected listed keys not to expose their secret, got %s", response.Body)
+	}
+}
+
+func TestAPIKeyController_Create_Successfully(t *testing.T) {
+	var input service.APIKeyInput
+	ctrl := newAPIKeyController(&test.MockAPIKeyService{
+		CreateFn: func(ctx context.Context, in service.APIKeyInput) (domain.APIKey, error) {
+			input = in
+			return domain.APIKey{ID: apiKeyUUID, Name: in.Name, App: in.App, Scopes: in.Scopes, Prefix: in.Prefix, CreatedAt: fixedTime}, nil
+		},
+	})
+	req := asCaller(test.NewMockRequest().WithBody(`{"name": "reminders", "app": "reminders-job", "scopes": ["todos:read", "todos:write"]}`), "alice")
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, response.Status, response.Body)
+	}
+	var body controller.IssuedAPIKeyDataResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !strings.HasPrefix(body.Data.Key, body.Data.Prefix) || body.Data.Key == body.Data.Prefix {
+		t.Errorf("expected the key to start with its prefix, got %+v", body.Data)
+	}
+	if input.Hash == "" || input.Hash == body.Data.Key {
+		t.Errorf("expected only the hash of the key to be stored, got %+v", input)
+	}
+}
+
+func TestAPIKeyController_Create_BindsUser(t *testing.T) {
+	// bob manages keys without being an admin
+	p, err := policy.New(policy.Config{
+		Roles: map[string][]string{policy.RoleAdmin: {"*"}, "integrator": {string(policy.ActionManageKeys)}},
+		Users: map[string]string{"alice": policy.RoleAdmin, "bob": "integrator"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input service.APIKeyInput
+	uc := usecase.NewAPIKey(&test.MockAPIKeyService{
+		CreateFn: func(ctx context.Context, in service.APIKeyInput) (domain.APIKey, error) {
+			input = in
+			return domain.APIKey{ID: apiKeyUUID, Name: in.Name, App: in.App, Scopes: in.Scopes, Prefix: in.Prefix, User: in.User, CreatedAt: fixedTime}, nil
+		},
+	}, usecase.WithAPIKeyPolicy(p))
+	ctrl := controller.NewAPIKey(uc, newErrorHandler())
+
+	tests := []struct {
+		name   string
+		caller string
+		body   string
+		status int
+		user   string
+	}{
+		{name: "caller", caller: "bob", body: `{"name": "assistant", "app": "assistant", "scopes": ["todos:read"]}`, status: http.StatusCreated, user: "bob"},
+		{name: "non admin naming another user", caller: "bob", body: `{"name": "assistant", "app": "assistant", "scopes": ["todos:read"], "user": "carol"}`, status: http.StatusForbidden},
+		{name: "admin naming another user", caller: "alice", body: `{"name": "assistant", "app": "assistant", "scopes": ["todos:read"], "user": "carol"}`, status: http.StatusCreated, user: "carol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input = service.APIKeyInput{}
+
+			response := ctrl.Create(asCaller(test.NewMockRequest().WithBody(tt.body), tt.caller))
+
+			if response.Status != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, response.Status, response.Body)
+			}
+			if tt.status != http.StatusCreated {
+				return
+			}
+			var body controller.IssuedAPIKeyDataResponse
+			if err := json.Unmarshal(response.Body, &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if input.User != tt.user || body.Data.User != tt.user {
+				t.Errorf("expected the key to act for %s, got %+v", tt.user, body.Data)
+			}
+		})
+	}
+}
+
+func TestAPIKeyController_RequiresAnAuthenticatedCaller(t *testing.T) {
+	ctrl := newAPIKeyController(&test.MockAPIKeyService{})
+
+	responses := map[string]web.Response{
+		"get":    ctrl.Get(test.NewMockRequest()),
+		"create": ctrl.Create(test.NewMockRequest().WithBody(`{"name": "reminders", "app": "reminders-job", "scopes": ["todos:read"]}`)),
+		"delete": ctrl.Delete(test.NewMockRequest().WithParam("id", apiKeyUUID)),
+	}
+	for name, response := range responses {
+		if response.Status != http.StatusUnauthorized {
+			t.Errorf("expected status %d on %s, got %d", http.StatusUnauthorized, name, response.Status)
+		}
+	}
+}
+
+func TestAPIKeyController_Create_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed":     `{`,
+		"missing name":  `{"app": "reminders-job", "scopes": ["todos:read"]}`,
+		"invalid app":   `{"name": "reminders", "app": "reminders job", "scopes": ["todos:read"]}`,
+		"no scopes":     `{"name": "reminders", "app": "reminders-job", "scopes": []}`,
+		"unknown scope": `{"name": "reminders", "app": "reminders-job", "scopes": ["todos:delete"]}`,
+		"invalid user":  `{"name": "reminders", "app": "reminders-job", "scopes": ["todos:read"], "user": "alice smith"}`,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := newAPIKeyController(&test.MockAPIKeyService{}).Create(test.NewMockRequest().WithBody(body))
+
+			if response.Status != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+			}
+		})
+	}
+}
+
+func TestAPIKeyController_Delete(t *testing.T) {
+	var revoked string
+	ctrl := newAPIKeyController(&test.MockAPIKeyService{
+		RevokeFn: func(ctx context.Context, id string) error {
+			if revoked != "" {
+				return domain.ErrAPIKeyNotFound
+			}
+			revoked = id
+			return nil
+		},
+	})
+	req := asCaller(test.NewMockRequest().WithParam("id", apiKeyUUID), "alice")
+
+	if response := ctrl.Delete(req); response.Status != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, response.Status)
+	}
+	if response := ctrl.Delete(req); response.Status != http.StatusNotFound {
+		t.Errorf("expected status %d revoking twice, got %d", http.StatusNotFound, response.Status)
+	}
+	if response := ctrl.Delete(test.NewMockRequest().WithParam("id", "nope")); response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d for an invalid id, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestAPIKeyInterceptor_PutsKeyInContext(t *testing.T) {
+	uc, secret := issueAPIKey(t, domain.ScopeTodosRead, domain.ScopeTodosWrite)
+
+	var key domain.APIKey
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest().
+			WithHeader("X-Api-Key", secret).
+			WithHeader("X-Api-Client-Application", "reminders-job"),
+		NextFn: func(req *test.MockRequest) web.Response {
+			key, _ = domain.APIKeyFromContext(req.Context())
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+
+	response := controller.NewAPIKeyInterceptor(uc, newErrorHandler())(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if key.App != "reminders-job" || len(key.Scopes) != 2 {
+		t.Errorf("expected the key of reminders-job with its 2 scopes, got %+v", key)
+	}
+}
+
+func TestAPIKeyInterceptor_DeclaredScopeNarrowsKey(t *testing.T) {
+	uc, secret := issueAPIKey(t, domain.ScopeTodosRead, domain.ScopeTodosWrite)
+
+	var key domain.APIKey
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest().
+			WithHeader("X-Api-Key", secret).
+			WithHeader("X-Api-Client-Scope", domain.ScopeTodosRead),
+		NextFn: func(req *test.MockRequest) web.Response {
+			key, _ = domain.APIKeyFromContext(req.Context())
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+
+	controller.NewAPIKeyInterceptor(uc, newErrorHandler())(req)
+
+	if key.HasScope(domain.ScopeTodosWrite) || !key.HasScope(domain.ScopeTodosRead) {
+		t.Errorf("expected the key to be narrowed to todos:read, got %+v", key)
+	}
+}
+
+func TestAPIKeyInterceptor_Rejects(t *testing.T) {
+	uc, secret := issueAPIKey(t, domain.ScopeTodosRead)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"unknown key", map[string]string{"X-Api-Key": "tk_unknown"}, http.StatusUnauthorized},
+		{"other app", map[string]string{"X-Api-Key": secret, "X-Api-Client-Application": "billing"}, http.StatusUnauthorized},
+		{"scope not granted", map[string]string{"X-Api-Key": secret, "X-Api-Client-Scope": domain.ScopeTodosWrite}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := test.NewMockRequest()
+			for k, v := range tt.headers {
+				mock.WithHeader(k, v)
+			}
+			req := &test.MockInterceptedRequest{
+				MockRequest: mock,
+				NextFn: func(*test.MockRequest) web.Response {
+					t.Error("expected the request to be rejected")
+					return web.NewJSONResponse(http.StatusOK, nil)
+				},
+			}
+
+			response := controller.NewAPIKeyInterceptor(uc, newErrorHandler())(req)
+
+			if response.Status != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, response.Status)
+			}
+		})
+	}
+}
+
+func TestAPIKeyInterceptor_LetsRequestsWithoutKeyThrough(t *testing.T) {
+	uc, _ := issueAPIKey(t, domain.ScopeTodosRead)
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest(),
+		NextFn: func(req *test.MockRequest) web.Response {
+			if _, ok := domain.APIKeyFromContext(req.Context()); ok {
+				t.Error("expected no API key in context")
+			}
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+
+	if response := controller.NewAPIKeyInterceptor(uc, newErrorHandler())(req); response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+}
+
+func TestScopeInterceptor(t *testing.T) {
+	next := func(*test.MockRequest) web.Response { return web.NewJSONResponse(http.StatusOK, nil) }
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		status int
+	}{
+		{"without key", context.Background(), http.StatusOK},
+		{"granted", domain.WithAPIKey(context.Background(), domain.APIKey{Scopes: []string{domain.ScopeTodosWrite}}), http.StatusOK},
+		{"not granted", domain.WithAPIKey(context.Background(), domain.APIKey{Scopes: []string{domain.ScopeTodosRead}}), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &test.MockInterceptedRequest{MockRequest: test.NewMockRequest(), NextFn: next}
+			req.Ctx = tt.ctx
+
+			if response := controller.NewScopeInterceptor(domain.ScopeTodosWrite)(req); response.Status != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, response.Status)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_SkipsAPIKeyCallers(t *testing.T) {
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest(),
+		NextFn:      func(*test.MockRequest) web.Response { return web.NewJSONResponse(http.StatusOK, nil) },
+	}
+	req.Ctx = domain.WithAPIKey(req.Ctx, domain.APIKey{App: "reminders-job"})
+
+	if response := controller.NewAuthInterceptor(newAuthVerifier())(req); response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+}
+
+func TestCallerInterceptor_UsesAppOfAPIKey(t *testing.T) {
+	var caller domain.Caller
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest(),
+		NextFn: func(req *test.MockRequest) web.Response {
+			caller = domain.CallerFromContext(req.Context())
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+	req.Ctx = domain.WithAPIKey(req.Ctx, domain.APIKey{App: "reminders-job"})
+
+	controller.NewCallerInterceptor()(req)
+
+	if caller.App != "reminders-job" {
+		t.Errorf("expected app reminders-job, got %+v", caller)
+	}
+}
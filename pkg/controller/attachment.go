@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/usecase"
+	"todo-api/web"
+)
+
+const (
+	// attachmentField is the multipart form field holding an uploaded file.
+	attachmentField = "file"
+	// multipartOverhead is how much larger than the file an upload request may be, for
+	// the boundaries and part headers of the form.
+	multipartOverhead = 64 << 10
+)
+
+type (
+	Attachment struct {
+		usecase    *usecase.Attachment
+		errHandler web.ErrorHandler
+	}
+
+	AttachmentResponse struct {
+		ID          string `json:"id"`
+		TodoID      string `json:"todo_id"`
+		Filename    string `json:"filename"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+		CreatedAt   string `json:"created_at"`
+	}
+
+	ListAttachmentsResponse struct {
+		Data []AttachmentResponse `json:"data"`
+	}
+
+	AttachmentDataResponse struct {
+		Data AttachmentResponse `json:"data"`
+	}
+)
+
+func NewAttachment(uc *usecase.Attachment, errHandler web.ErrorHandler) *Attachment {
+	return &Attachment{
+		usecase:    uc,
+		errHandler: errHandler,
+	}
+}
+
+// Get lists the attachments of a todo, oldest first.
+func (c *Attachment) Get(req web.Request) web.Response {
+	todoID, errResp := parseTodoID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	output, err := c.usecase.List(req.Context(), todoID)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := ListAttachmentsResponse{Data: make([]AttachmentResponse, len(output.Attachments))}
+	for i, attachment := range output.Attachments {
+		response.Data[i] = MapAttachmentToResponse(attachment)
+	}
+
+	return web.NewJSONResponse(http.StatusOK, response)
+}
+
+// Create attaches the file sent in the file field of a multipart form.
+func (c *Attachment) Create(req web.Request) web.Response {
+	todoID, errResp := parseTodoID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	// bound the request before the form is parsed, as parsing spools the whole file
+	if raw := req.Raw(); raw != nil && raw.Body != nil {
+		raw.Body = http.MaxBytesReader(nil, raw.Body, c.usecase.MaxSize()+multipartOverhead)
+	}
+
+	fh, err := req.FormFile(attachmentField)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrAttachmentTooLarge))
+		}
+		return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, domain.ErrMissingAttachment))
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+	defer file.Close()
+
+	output, err := c.usecase.Upload(req.Context(), todoID, usecase.UploadInput{
+		Filename: fh.Filename,
+		Size:     fh.Size,
+		Content:  file,
+	})
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusCreated, AttachmentDataResponse{Data: MapAttachmentToResponse(output.Attachment)})
+}
+
+// Download returns the content of an attachment with its sniffed content type. Clients are
+// told not to sniff it again and to save it rather than display it.
+func (c *Attachment) Download(req web.Request) web.Response {
+	todoID, errResp := parseTodoID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	id, ok := req.Param("attachmentId")
+	if !ok || domain.ValidateUUID(id) != nil {
+		return web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidAttachmentID))
+	}
+
+	output, err := c.usecase.Download(req.Context(), todoID, id)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+	defer output.Content.Close()
+
+	// the upload limit bounds how much is held in memory
+	body, err := io.ReadAll(io.LimitReader(output.Content, c.usecase.MaxSize()+1))
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	headers := make(http.Header)
+	headers.Set("Content-Type", output.Attachment.ContentType)
+	headers.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": output.Attachment.Filename}))
+	headers.Set("X-Content-Type-Options", "nosniff")
+
+	return web.NewResponseWithHeader(http.StatusOK, body, headers)
+}
+
+func MapAttachmentToResponse(attachment domain.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          attachment.ID,
+		TodoID:      attachment.TodoID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,140 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+const attachmentUUID = "523e4567-e89b-12d3-a456-426614174000"
+
+func newTestAttachmentController(attachments *test.MockAttachmentService, blobs *test.MockBlobStore) *controller.Attachment {
+	todos := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	return controller.NewAttachment(usecase.NewAttachment(attachments, blobs, todos), newErrorHandler())
+}
+
+func TestAttachmentController_Create_Successfully(t *testing.T) {
+	ctrl := newTestAttachmentController(&test.MockAttachmentService{
+		CreateFn: func(ctx context.Context, input service.AttachmentInput) (domain.Attachment, error) {
+			return domain.Attachment{
+				ID:          attachmentUUID,
+				TodoID:      input.TodoID,
+				Filename:    input.Filename,
+				ContentType: input.ContentType,
+				Size:        input.Size,
+				CreatedAt:   fixedTime,
+			}, nil
+		},
+	}, test.NewMockBlobStore())
+	req := test.NewMockRequest().WithParam("id", validUUID).WithFile("file", "notes.txt", []byte("some notes"))
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, response.Status, response.Body)
+	}
+	var body controller.AttachmentDataResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.Filename != "notes.txt" || body.Data.ContentType != "text/plain; charset=utf-8" || body.Data.Size != 10 {
+		t.Errorf("expected the sniffed text attachment, got %+v", body.Data)
+	}
+}
+
+func TestAttachmentController_Create_MissingFile(t *testing.T) {
+	ctrl := newTestAttachmentController(&test.MockAttachmentService{}, test.NewMockBlobStore())
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestAttachmentController_Create_UnsupportedType(t *testing.T) {
+	ctrl := newTestAttachmentController(&test.MockAttachmentService{}, test.NewMockBlobStore())
+	req := test.NewMockRequest().WithParam("id", validUUID).WithFile("file", "page.txt", []byte("<html><body>hi</body></html>"))
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status %d, got %d", http.StatusUnsupportedMediaType, response.Status)
+	}
+}
+
+func TestAttachmentController_Get_Successfully(t *testing.T) {
+	ctrl := newTestAttachmentController(&test.MockAttachmentService{
+		ListFn: func(ctx context.Context, todoID string) ([]domain.Attachment, error) {
+			return []domain.Attachment{{ID: attachmentUUID, TodoID: todoID, Filename: "notes.txt", CreatedAt: fixedTime}}, nil
+		},
+	}, test.NewMockBlobStore())
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.ListAttachmentsResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Data) != 1 || body.Data[0].ID != attachmentUUID {
+		t.Errorf("expected the attachment, got %+v", body.Data)
+	}
+}
+
+func TestAttachmentController_Download_Successfully(t *testing.T) {
+	blobs := test.NewMockBlobStore()
+	blobs.Blobs[validUUID+"/key"] = []byte("some notes")
+	ctrl := newTestAttachmentController(&test.MockAttachmentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Attachment, error) {
+			return domain.Attachment{
+				ID:          id,
+				TodoID:      todoID,
+				Filename:    "notes.txt",
+				ContentType: "text/plain; charset=utf-8",
+				StorageKey:  todoID + "/key",
+			}, nil
+		},
+	}, blobs)
+	req := test.NewMockRequest().WithParam("id", validUUID).WithParam("attachmentId", attachmentUUID)
+
+	response := ctrl.Download(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if string(response.Body) != "some notes" {
+		t.Errorf("expected the content, got %q", response.Body)
+	}
+	if response.Headers.Get("Content-Type") != "text/plain; charset=utf-8" ||
+		response.Headers.Get("Content-Disposition") != "attachment; filename=notes.txt" ||
+		response.Headers.Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("expected download headers, got %v", response.Headers)
+	}
+}
+
+func TestAttachmentController_Download_InvalidAttachmentID(t *testing.T) {
+	ctrl := newTestAttachmentController(&test.MockAttachmentService{}, test.NewMockBlobStore())
+	req := test.NewMockRequest().WithParam("id", validUUID).WithParam("attachmentId", "nope")
+
+	response := ctrl.Download(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
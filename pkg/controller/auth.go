@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"todo-api/pkg/auth"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/usecase"
+	"todo-api/web"
+)
+
+// NewAuthInterceptor only lets through requests bearing a JWT the verifier accepts in
+// their Authorization header, except for the routes declared at the exempt paths. The
+// claims of the token go into the request context, and its subject becomes the user of
+// the domain.Caller, taking precedence over the one a client declares. Requests already
+// authenticated by NewAPIKeyInterceptor need no token.
+func NewAuthInterceptor(verifier *auth.Verifier, exempt ...string) web.Interceptor {
+	return func(req web.InterceptedRequest) web.Response {
+		if slices.Contains(exempt, req.DeclaredPath()) {
+			return req.Next()
+		}
+		if _, ok := domain.APIKeyFromContext(req.Context()); ok {
+			return req.Next()
+		}
+
+		token, ok := bearerToken(req)
+		if !ok {
+			return unauthorized(auth.ErrMissingToken, `Bearer`)
+		}
+
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			return unauthorized(err, `Bearer error="invalid_token"`)
+		}
+
+		ctx := auth.WithClaims(req.Context(), claims)
+		caller := domain.CallerFromContext(ctx)
+		caller.User = claims.Subject
+		req.Apply(domain.WithCaller(ctx, caller))
+		return req.Next()
+	}
+}
+
+// NewAPIKeyInterceptor authenticates the requests sending an API key in the X-Api-Key
+// header and puts the key into their context; requests without one go through untouched.
+// A client declaring an application must declare the one the key was issued to, and a
+// client declaring a scope narrows the key down to that scope, which it must grant.
+func NewAPIKeyInterceptor(uc *usecase.APIKey, errHandler web.ErrorHandler) web.Interceptor {
+	return func(req web.InterceptedRequest) web.Response {
+		secret := web.GetCallerAPIKey(req)
+		if secret == "" {
+			return req.Next()
+		}
+
+		key, err := uc.Authenticate(req.Context(), secret)
+		if err != nil {
+			return web.NewJSONResponseFromError(errHandler.Handle(err))
+		}
+
+		if app, ok := web.LookupCallerApp(req); ok && app != key.App {
+			return web.NewJSONResponseFromError(errHandler.Handle(domain.ErrInvalidAPIKey))
+		}
+		if scope, ok := web.LookupCallerScope(req); ok {
+			if !key.HasScope(scope) {
+				return web.NewJSONResponseFromError(errHandler.Handle(domain.ErrInsufficientScope))
+			}
+			key.Scopes = []string{scope}
+		}
+
+		req.Apply(domain.WithAPIKey(req.Context(), key))
+		return req.Next()
+	}
+}
+
+// NewScopeInterceptor restricts a route to the API keys granting the scope. Requests not
+// authenticated by an API key are left to the other interceptors.
+func NewScopeInterceptor(scope string) web.Interceptor {
+	return func(req web.InterceptedRequest) web.Response {
+		if key, ok := domain.APIKeyFromContext(req.Context()); ok && !key.HasScope(scope) {
+			return web.NewJSONResponseFromError(web.NewResponseError(http.StatusForbidden, domain.ErrInsufficientScope))
+		}
+		return req.Next()
+	}
+}
+
+func bearerToken(req web.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(req.Raw().Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// unauthorized answers 401 with the challenge of RFC 6750.
+func unauthorized(err error, challenge string) web.Response {
+	resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusUnauthorized, err))
+	resp.Headers.Set("WWW-Authenticate", challenge)
+	return resp
+}
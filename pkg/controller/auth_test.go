@@ -0,0 +1,160 @@
+package controller_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"todo-api/pkg/auth"
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/test"
+	"todo-api/web"
+)
+
+var authSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newAuthVerifier() *auth.Verifier {
+	return auth.NewVerifier(auth.NewKeySet(auth.NewHMACKey("test", authSecret)), "todo-api")
+}
+
+func signToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]any{"alg": "HS256", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, authSecret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthInterceptor_PutsClaimsInContext(t *testing.T) {
+	token := signToken(t, map[string]any{"sub": "alice", "aud": "todo-api", "exp": time.Now().Add(time.Hour).Unix()})
+
+	var claims auth.Claims
+	var caller domain.Caller
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest().WithHeader("Authorization", "Bearer "+token),
+		NextFn: func(req *test.MockRequest) web.Response {
+			claims, _ = auth.ClaimsFromContext(req.Context())
+			caller = domain.CallerFromContext(req.Context())
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+
+	response := controller.NewAuthInterceptor(newAuthVerifier())(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if claims.Subject != "alice" {
+		t.Errorf("expected the claims of alice, got %+v", claims)
+	}
+	if caller.User != "alice" {
+		t.Errorf("expected user alice, got %+v", caller)
+	}
+}
+
+func TestAuthInterceptor_DeclaredUserMustBeSubject(t *testing.T) {
+	token := signToken(t, map[string]any{"sub": "alice", "aud": "todo-api", "exp": time.Now().Add(time.Hour).Unix()})
+
+	tests := []struct {
+		declared string
+		status   int
+	}{
+		{"alice", http.StatusOK},
+		{"mallory", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.declared, func(t *testing.T) {
+			var caller domain.Caller
+			req := &test.MockInterceptedRequest{
+				MockRequest: test.NewMockRequest().
+					WithHeader("Authorization", "Bearer "+token).
+					WithHeader("X-Api-Client-User", tt.declared),
+			}
+			req.NextFn = func(*test.MockRequest) web.Response {
+				return controller.NewCallerInterceptor()(&test.MockInterceptedRequest{
+					MockRequest: req.MockRequest,
+					NextFn: func(req *test.MockRequest) web.Response {
+						caller = domain.CallerFromContext(req.Context())
+						return web.NewJSONResponse(http.StatusOK, nil)
+					},
+				})
+			}
+
+			response := controller.NewAuthInterceptor(newAuthVerifier())(req)
+
+			if response.Status != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, response.Status)
+			}
+			if tt.status == http.StatusOK && caller.User != "alice" {
+				t.Errorf("expected user alice, got %+v", caller)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_Rejects(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		challenge     string
+	}{
+		{"missing header", "", `Bearer`},
+		{"other scheme", "Basic YWxpY2U6c2VjcmV0", `Bearer`},
+		{"empty token", "Bearer ", `Bearer`},
+		{"garbage", "Bearer abc", `Bearer error="invalid_token"`},
+		{"expired", "Bearer " + signToken(t, map[string]any{"sub": "alice", "aud": "todo-api", "exp": time.Now().Add(-time.Hour).Unix()}), `Bearer error="invalid_token"`},
+		{"wrong audience", "Bearer " + signToken(t, map[string]any{"sub": "alice", "aud": "other", "exp": time.Now().Add(time.Hour).Unix()}), `Bearer error="invalid_token"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := test.NewMockRequest()
+			if tt.authorization != "" {
+				mock.WithHeader("Authorization", tt.authorization)
+			}
+			req := &test.MockInterceptedRequest{
+				MockRequest: mock,
+				NextFn: func(*test.MockRequest) web.Response {
+					t.Error("expected the request to be rejected")
+					return web.NewJSONResponse(http.StatusOK, nil)
+				},
+			}
+
+			response := controller.NewAuthInterceptor(newAuthVerifier())(req)
+
+			if response.Status != http.StatusUnauthorized {
+				t.Errorf("expected status %d, got %d", http.StatusUnauthorized, response.Status)
+			}
+			if got := response.Headers.Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("expected challenge %q, got %q", tt.challenge, got)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_ExemptPath(t *testing.T) {
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest().WithPath("/ping"),
+		NextFn: func(*test.MockRequest) web.Response {
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+
+	response := controller.NewAuthInterceptor(newAuthVerifier(), "/ping")(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+}
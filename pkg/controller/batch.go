@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/usecase"
+	"todo-api/web"
+)
+
+const (
+	batchModeAtomic  = "atomic"
+	batchModePartial = "partial"
+)
+
+type (
+	// BatchRequest is the body of POST /api/todos:batch. Mode defaults to atomic.
+	BatchRequest struct {
+		Mode       string                  `json:"mode,omitempty"`
+		Operations []BatchOperationRequest `json:"operations"`
+	}
+
+	// BatchOperationRequest is one operation of a batch. Data holds a CreateRequest or an
+	// UpdateRequest depending on Op, and IfMatch plays the role of the If-Match header.
+	BatchOperationRequest struct {
+		Op      string          `json:"op"`
+		ID      string          `json:"id,omitempty"`
+		IfMatch string          `json:"if_match,omitempty"`
+		Data    json.RawMessage `json:"data,omitempty"`
+	}
+
+	BatchResponse struct {
+		Results []BatchResultResponse `json:"results"`
+	}
+
+	BatchResultResponse struct {
+		Index  int                `json:"index"`
+		Op     string             `json:"op"`
+		Status int                `json:"status"`
+		Data   *TodoResponse      `json:"data,omitempty"`
+		Next   *TodoResponse      `json:"next,omitempty"`
+		Error  *web.ResponseError `json:"error,omitempty"`
+	}
+)
+
+// Batch runs several creates, updates and deletes in one request. In atomic mode nothing is
+// applied unless every operation succeeds, and the response status is the one of the first
+// failure; in partial mode the response is always 200 and each result carries its own status.
+func (c *Todo) Batch(req web.Request) web.Response {
+	var body BatchRequest
+	if err := web.DecodeJSON(req.Body(), &body); err != nil {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, err),
+		)
+	}
+
+	var atomic bool
+	switch body.Mode {
+	case "", batchModeAtomic:
+		atomic = true
+	case batchModePartial:
+	default:
+		return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidBatchMode))
+	}
+
+	if len(body.Operations) == 0 || len(body.Operations) > usecase.MaxBatchSize {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidBatchSize))
+	}
+
+	results := make([]BatchResultResponse, len(body.Operations))
+	operations := make([]usecase.BatchOperation, 0, len(body.Operations))
+	positions := make([]int, 0, len(body.Operations))
+	for i, item := range body.Operations {
+		results[i] = BatchResultResponse{Index: i, Op: item.Op}
+
+		op, err := item.toOperation()
+		if err != nil {
+			results[i].fail(web.NewResponseError(http.StatusBadRequest, err))
+			continue
+		}
+		operations = append(operations, op)
+		positions = append(positions, i)
+	}
+
+	if atomic && len(operations) < len(body.Operations) {
+		for _, i := range positions {
+			results[i].fail(c.errHandler.Handle(domain.ErrBatchAborted))
+		}
+		return web.NewJSONResponse(http.StatusBadRequest, BatchResponse{Results: results})
+	}
+
+	if len(operations) > 0 {
+		output, err := c.usecase.Batch(req.Context(), usecase.BatchInput{
+			Operations: operations,
+			Atomic:     atomic,
+		})
+		if err != nil {
+			return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+		}
+
+		for j, result := range output.Results {
+			c.mapBatchResult(&results[positions[j]], operations[j].Op, result)
+		}
+	}
+
+	return web.NewJSONResponse(batchStatus(results, atomic), BatchResponse{Results: results})
+}
+
+// toOperation validates the operation with the same rules as the single-todo endpoints.
+func (r BatchOperationRequest) toOperation() (usecase.BatchOperation, error) {
+	op := usecase.BatchOperation{Op: usecase.BatchOp(r.Op), ID: r.ID}
+
+	data := r.Data
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+
+	var err error
+	switch op.Op {
+	case usecase.BatchCreate:
+		var body CreateRequest
+		if err := web.DecodeJSON(bytes.NewReader(data), &body); err != nil {
+			return usecase.BatchOperation{}, err
+		}
+		op.Create, err = body.toInput()
+		return op, err
+	case usecase.BatchUpdate:
+		if err := domain.ValidateUUID(r.ID); err != nil {
+			return usecase.BatchOperation{}, err
+		}
+		var body UpdateRequest
+		if err := web.DecodeJSON(bytes.NewReader(data), &body); err != nil {
+			return usecase.BatchOperation{}, err
+		}
+		if op.Update, err = body.toInput(); err != nil {
+			return usecase.BatchOperation{}, err
+		}
+		op.Update.ExpectedVersion, err = r.expectedVersion()
+		return op, err
+	case usecase.BatchDelete:
+		if err := domain.ValidateUUID(r.ID); err != nil {
+			return usecase.BatchOperation{}, err
+		}
+		op.Delete.ExpectedVersion, err = r.expectedVersion()
+		return op, err
+	default:
+		return usecase.BatchOperation{}, domain.ErrInvalidBatchOp
+	}
+}
+
+func (r BatchOperationRequest) expectedVersion() (*int, error) {
+	if r.IfMatch == "" {
+		return nil, nil
+	}
+	return parseVersionTag(r.IfMatch)
+}
+
+func (c *Todo) mapBatchResult(dst *BatchResultResponse, op usecase.BatchOp, result usecase.BatchResult) {
+	if result.Err != nil {
+		dst.fail(c.errHandler.Handle(result.Err))
+		return
+	}
+
+	switch op {
+	case usecase.BatchCreate:
+		dst.Status = http.StatusCreated
+	case usecase.BatchUpdate:
+		dst.Status = http.StatusOK
+	case usecase.BatchDelete:
+		dst.Status = http.StatusNoContent
+	}
+
+	if result.Todo != nil {
+		data := MapTodoToResponse(*result.Todo)
+		dst.Data = &data
+	}
+	if result.Next != nil {
+		next := MapTodoToResponse(*result.Next)
+		dst.Next = &next
+	}
+}
+
+func (r *BatchResultResponse) fail(err *web.ResponseError) {
+	r.Status = err.Status
+	r.Error = err
+}
+
+// batchStatus is 200 unless an atomic batch failed, in which case it is the status of the
+// first operation that caused the rollback.
+func batchStatus(results []BatchResultResponse, atomic bool) int {
+	if !atomic {
+		return http.StatusOK
+	}
+	for _, r := range results {
+		if r.Error != nil && !errors.Is(r.Error, domain.ErrBatchAborted) {
+			return r.Status
+		}
+	}
+	return http.StatusOK
+}
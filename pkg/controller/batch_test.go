@@ -0,0 +1,175 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/test"
+	"todo-api/web"
+)
+
+func batchStatuses(t *testing.T, response web.Response) []int {
+	t.Helper()
+	var body struct {
+		Results []struct {
+			Status int `json:"status"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode batch response: %v", err)
+	}
+	statuses := make([]int, len(body.Results))
+	for i, r := range body.Results {
+		statuses[i] = r.Status
+	}
+	return statuses
+}
+
+func TestTodoController_Batch_AtomicSuccessfully(t *testing.T) {
+	var mock *test.MockTodoService
+	mock = &test.MockTodoService{
+		TransactionFn: func(ctx context.Context, fn func(service.Todo) error) error {
+			return fn(mock)
+		},
+		CreateBatchFn: func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		DeleteBatchFn: func(ctx context.Context, items []service.BatchDelete) ([]service.BatchResult, error) {
+			if items[0].Input.ExpectedVersion == nil || *items[0].Input.ExpectedVersion != 2 {
+				t.Errorf("expected version 2 from if_match, got %v", items[0].Input.ExpectedVersion)
+			}
+			return []service.BatchResult{{}}, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithBody(`{"operations":[
+		{"op":"create","data":{"title":"New Todo"}},
+		{"op":"delete","id":"` + validUUID + `","if_match":"\"2\""}
+	]}`)
+
+	response := ctrl.Batch(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	statuses := batchStatuses(t, response)
+	if statuses[0] != http.StatusCreated || statuses[1] != http.StatusNoContent {
+		t.Errorf("expected item statuses [201 204], got %v", statuses)
+	}
+}
+
+func TestTodoController_Batch_ReturnsBadRequestForInvalidMode(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithBody(`{"mode":"best_effort","operations":[{"op":"create","data":{"title":"New Todo"}}]}`)
+
+	response := ctrl.Batch(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Batch_ReturnsBadRequestForEmptyBatch(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithBody(`{"operations":[]}`)
+
+	response := ctrl.Batch(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Batch_AtomicRejectsInvalidItemWithoutExecuting(t *testing.T) {
+	ctrl := newTestControllerWithMock(&test.MockTodoService{})
+	req := test.NewMockRequest().WithBody(`{"operations":[
+		{"op":"create","data":{"title":"New Todo"}},
+		{"op":"update","id":"` + invalidUUID + `","data":{"title":"Updated"}},
+		{"op":"update","id":"` + validUUID + `","data":{}}
+	]}`)
+
+	response := ctrl.Batch(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+	statuses := batchStatuses(t, response)
+	if statuses[0] != http.StatusFailedDependency || statuses[1] != http.StatusBadRequest || statuses[2] != http.StatusBadRequest {
+		t.Errorf("expected item statuses [424 400 400], got %v", statuses)
+	}
+}
+
+func TestTodoController_Batch_PartialReportsEachItem(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		UpdateBatchFn: func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+			return []service.BatchResult{{Err: domain.ErrTodoNotFound}}, nil
+		},
+		CreateBatchFn: func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithBody(`{"mode":"partial","operations":[
+		{"op":"create","data":{"title":""}},
+		{"op":"update","id":"` + validUUID + `","data":{"title":"Updated"}},
+		{"op":"create","data":{"title":"New Todo"}},
+		{"op":"archive","id":"` + validUUID + `"}
+	]}`)
+
+	response := ctrl.Batch(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	statuses := batchStatuses(t, response)
+	expected := []int{http.StatusBadRequest, http.StatusNotFound, http.StatusCreated, http.StatusBadRequest}
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			t.Errorf("expected item statuses %v, got %v", expected, statuses)
+			break
+		}
+	}
+}
+
+func TestTodoController_Batch_AtomicReturnsStatusOfFailingItem(t *testing.T) {
+	var mock *test.MockTodoService
+	mock = &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		TransactionFn: func(ctx context.Context, fn func(service.Todo) error) error {
+			return fn(mock)
+		},
+		CreateBatchFn: func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		UpdateBatchFn: func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+			return []service.BatchResult{{Err: domain.ErrVersionMismatch}}, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithBody(`{"mode":"atomic","operations":[
+		{"op":"create","data":{"title":"New Todo"}},
+		{"op":"update","id":"` + validUUID + `","if_match":"\"1\"","data":{"title":"Updated"}}
+	]}`)
+
+	response := ctrl.Batch(req)
+
+	if response.Status != http.StatusPreconditionFailed {
+		t.Errorf("expected status %d, got %d", http.StatusPreconditionFailed, response.Status)
+	}
+	statuses := batchStatuses(t, response)
+	if statuses[0] != http.StatusFailedDependency || statuses[1] != http.StatusPreconditionFailed {
+		t.Errorf("expected item statuses [424 412], got %v", statuses)
+	}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"net/http"
+
+	"todo-api/pkg/domain"
+	"todo-api/web"
+)
+
+// NewCallerInterceptor puts the client application, scope and user of the request into
+// its context as a domain.Caller, which is how the history attributes changes and the
+// usecases learn who they act for. The user is the authenticated one only: the subject
+// of the token, already in the context, or the user the API key was issued for. A request
+// declaring another user than the authenticated one is rejected, and so is one declaring
+// a user without being authenticated as any. The application of an API key is kept over
+// the declared one.
+func NewCallerInterceptor() web.Interceptor {
+	return func(req web.InterceptedRequest) web.Response {
+		caller := domain.CallerFromContext(req.Context())
+		caller.App = web.GetCallerApp(req)
+		caller.Scope = web.GetCallerScope(req)
+		if key, ok := domain.APIKeyFromContext(req.Context()); ok {
+			caller.App = key.App
+			if caller.User == "" {
+				caller.User = key.User
+			}
+		}
+		if user := web.GetCallerUser(req); user != "" && user != caller.User {
+			return web.NewJSONResponseFromError(web.NewResponseError(http.StatusForbidden, domain.ErrUnverifiedUser))
+		}
+		req.Apply(domain.WithCaller(req.Context(), caller))
+		return req.Next()
+	}
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/usecase"
+	"todo-api/web"
+)
+
+type (
+	Comment struct {
+		usecase    *usecase.Comment
+		errHandler web.ErrorHandler
+	}
+
+	CommentResponse struct {
+		ID        string `json:"id"`
+		TodoID    string `json:"todo_id"`
+		Body      string `json:"body"`
+		Author    string `json:"author,omitempty"`
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+	}
+
+	ListCommentsResponse struct {
+		Data       []CommentResponse `json:"data"`
+		Total      int               `json:"total"`
+		NextCursor string            `json:"next_cursor,omitempty"`
+	}
+
+	// CommentRequest is the body of both the creation and the edition of a comment.
+	CommentRequest struct {
+		Body string `json:"body"`
+	}
+
+	CommentDataResponse struct {
+		Data CommentResponse `json:"data"`
+	}
+)
+
+func NewComment(uc *usecase.Comment, errHandler web.ErrorHandler) *Comment {
+	return &Comment{
+		usecase:    uc,
+		errHandler: errHandler,
+	}
+}
+
+// Get lists the comments of a todo in creation order, paged with ?limit and ?cursor.
+func (c *Comment) Get(req web.Request) web.Response {
+	todoID, errResp := parseTodoID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	var input usecase.ListCommentsInput
+	if limitStr, ok := req.Query("limit"); ok {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > usecase.MaxLimit {
+			return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidLimit))
+		}
+		input.Limit = limit
+	}
+	input.Cursor, _ = req.Query("cursor")
+
+	output, err := c.usecase.List(req.Context(), todoID, input)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := ListCommentsResponse{
+		Data:       make([]CommentResponse, len(output.Comments)),
+		Total:      output.Total,
+		NextCursor: output.NextCursor,
+	}
+	for i, comment := range output.Comments {
+		response.Data[i] = MapCommentToResponse(comment)
+	}
+
+	return web.NewJSONResponse(http.StatusOK, response)
+}
+
+func (c *Comment) Create(req web.Request) web.Response {
+	todoID, errResp := parseTodoID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	body, errResp := parseCommentBody(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	output, err := c.usecase.Create(req.Context(), todoID, body)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusCreated, CommentDataResponse{Data: MapCommentToResponse(output.Comment)})
+}
+
+func (c *Comment) Update(req web.Request) web.Response {
+	todoID, id, errResp := parseCommentID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	body, errResp := parseCommentBody(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	output, err := c.usecase.Update(req.Context(), todoID, id, body)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusOK, CommentDataResponse{Data: MapCommentToResponse(output.Comment)})
+}
+
+func (c *Comment) Delete(req web.Request) web.Response {
+	todoID, id, errResp := parseCommentID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	if err := c.usecase.Delete(req.Context(), todoID, id); err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusNoContent, nil)
+}
+
+func MapCommentToResponse(comment domain.Comment) CommentResponse {
+	return CommentResponse{
+		ID:        comment.ID,
+		TodoID:    comment.TodoID,
+		Body:      comment.Body,
+		Author:    comment.Author,
+		CreatedAt: comment.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: comment.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// parseTodoID reads and validates the todo id of a subresource path.
+func parseTodoID(req web.Request) (string, *web.Response) {
+	id, ok := req.Param("id")
+	if !ok {
+		resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidID))
+		return "", &resp
+	}
+
+	if err := domain.ValidateUUID(id); err != nil {
+		resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+		return "", &resp
+	}
+
+	return id, nil
+}
+
+// parseCommentID reads and validates the todo and comment ids of a comment path.
+func parseCommentID(req web.Request) (string, string, *web.Response) {
+	todoID, errResp := parseTodoID(req)
+	if errResp != nil {
+		return "", "", errResp
+	}
+
+	id, ok := req.Param("commentId")
+	if !ok || domain.ValidateUUID(id) != nil {
+		resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidCommentID))
+		return "", "", &resp
+	}
+
+	return todoID, id, nil
+}
+
+func parseCommentBody(req web.Request) (string, *web.Response) {
+	var body CommentRequest
+	if err := web.DecodeJSON(req.Body(), &body); err != nil {
+		resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+		return "", &resp
+	}
+
+	if err := domain.ValidateCommentBody(body.Body); err != nil {
+		resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+		return "", &resp
+	}
+
+	return body.Body, nil
+}
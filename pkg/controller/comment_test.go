@@ -0,0 +1,178 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+const commentUUID = "423e4567-e89b-12d3-a456-426614174000"
+
+func newTestCommentController(comments *test.MockCommentService) *controller.Comment {
+	todos := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	return controller.NewComment(usecase.NewComment(comments, todos), newErrorHandler())
+}
+
+func buildComment(body string) domain.Comment {
+	return domain.Comment{ID: commentUUID, TodoID: validUUID, Body: body, CreatedAt: fixedTime, UpdatedAt: fixedTime}
+}
+
+func TestCommentController_Get_Successfully(t *testing.T) {
+	var capturedPage service.CommentPage
+	ctrl := newTestCommentController(&test.MockCommentService{
+		ListFn: func(ctx context.Context, todoID string, page service.CommentPage) ([]domain.Comment, error) {
+			capturedPage = page
+			return []domain.Comment{buildComment("First")}, nil
+		},
+		CountFn: func(ctx context.Context, todoID string) (int, error) {
+			return 1, nil
+		},
+	})
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("limit", "5")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if capturedPage.Limit != 6 {
+		t.Errorf("expected one extra row to be requested, got limit %d", capturedPage.Limit)
+	}
+	var body controller.ListCommentsResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Total != 1 || len(body.Data) != 1 || body.Data[0].Body != "First" {
+		t.Errorf("expected the comment, got %+v", body)
+	}
+}
+
+func TestCommentController_Get_InvalidLimit(t *testing.T) {
+	ctrl := newTestCommentController(&test.MockCommentService{})
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("limit", "101")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestCommentController_Create_Successfully(t *testing.T) {
+	ctrl := newTestCommentController(&test.MockCommentService{
+		CreateFn: func(ctx context.Context, todoID, body, author string) (domain.Comment, error) {
+			comment := buildComment(body)
+			comment.Author = author
+			return comment, nil
+		},
+	})
+	req := asCaller(test.NewMockRequest().WithParam("id", validUUID).WithBody(`{"body": "Looks good"}`), "alice")
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, response.Status)
+	}
+	var body controller.CommentDataResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.Body != "Looks good" || body.Data.TodoID != validUUID || body.Data.Author != "alice" {
+		t.Errorf("expected the created comment, got %+v", body.Data)
+	}
+}
+
+func TestCommentController_Create_InvalidBody(t *testing.T) {
+	ctrl := newTestCommentController(&test.MockCommentService{})
+	for _, body := range []string{`{"body": "  "}`, `{"body": "` + strings.Repeat("a", domain.MaxCommentLength+1) + `"}`} {
+		req := test.NewMockRequest().WithParam("id", validUUID).WithBody(body)
+
+		response := ctrl.Create(req)
+
+		if response.Status != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+		}
+	}
+}
+
+func TestCommentController_Update_InvalidCommentID(t *testing.T) {
+	ctrl := newTestCommentController(&test.MockCommentService{})
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithParam("commentId", invalidUUID).
+		WithBody(`{"body": "Edited"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestCommentController_Update_NotFound(t *testing.T) {
+	ctrl := newTestCommentController(&test.MockCommentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Comment, error) {
+			return domain.Comment{}, domain.ErrCommentNotFound
+		},
+	})
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithParam("commentId", commentUUID).
+		WithBody(`{"body": "Edited"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, response.Status)
+	}
+}
+
+func TestCommentController_Update_ForbidsOtherUsers(t *testing.T) {
+	ctrl := newTestCommentController(&test.MockCommentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Comment, error) {
+			comment := buildComment("Looks good")
+			comment.Author = "alice"
+			return comment, nil
+		},
+	})
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithParam("commentId", commentUUID).
+		WithBody(`{"body": "Edited"}`)
+
+	response := ctrl.Update(asCaller(req, "bob"))
+
+	if response.Status != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, response.Status)
+	}
+}
+
+func TestCommentController_Delete_Successfully(t *testing.T) {
+	ctrl := newTestCommentController(&test.MockCommentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Comment, error) {
+			return buildComment("Looks good"), nil
+		},
+		DeleteFn: func(ctx context.Context, todoID, id string) error {
+			return nil
+		},
+	})
+	req := test.NewMockRequest().WithParam("id", validUUID).WithParam("commentId", commentUUID)
+
+	response := ctrl.Delete(req)
+
+	if response.Status != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, response.Status)
+	}
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"net/http"
+
+	"todo-api/pkg/domain"
+	"todo-api/web"
+)
+
+type (
+	// DependencyRequest names the todo that blocks the one in the path.
+	DependencyRequest struct {
+		BlockerID string `json:"blocker_id"`
+	}
+
+	DependenciesResponse struct {
+		Data []TodoResponse `json:"data"`
+	}
+
+	AddDependencyResponse struct {
+		Data TodoResponse `json:"data"`
+	}
+)
+
+// Dependencies lists the todos the todo is blocked by.
+func (c *Todo) Dependencies(req web.Request) web.Response {
+	id, ok := req.Param("id")
+	if !ok {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidID),
+		)
+	}
+
+	if err := domain.ValidateUUID(id); err != nil {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, err),
+		)
+	}
+
+	output, err := c.usecase.GetDependencies(req.Context(), id)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := DependenciesResponse{
+		Data: MapTodosToResponse(output.Blockers),
+	}
+
+	return web.NewJSONResponse(http.StatusOK, response)
+}
+
+// AddDependency makes the todo blocked by the one named in the body.
+func (c *Todo) AddDependency(req web.Request) web.Response {
+	id, blockerID, errResp := c.parseDependency(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	output, err := c.usecase.AddDependency(req.Context(), id, blockerID)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := AddDependencyResponse{
+		Data: MapTodoToResponse(output.Todo),
+	}
+
+	return withETag(web.NewJSONResponse(http.StatusCreated, response), output.Todo)
+}
+
+// RemoveDependency makes the todo no longer blocked by the one named in the body.
+func (c *Todo) RemoveDependency(req web.Request) web.Response {
+	id, blockerID, errResp := c.parseDependency(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	if err := c.usecase.RemoveDependency(req.Context(), id, blockerID); err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusNoContent, nil)
+}
+
+// parseDependency reads the todo id from the path and the blocker id from the body.
+func (c *Todo) parseDependency(req web.Request) (string, string, *web.Response) {
+	fail := func(err error) (string, string, *web.Response) {
+		resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+		return "", "", &resp
+	}
+
+	id, ok := req.Param("id")
+	if !ok {
+		return fail(domain.ErrInvalidID)
+	}
+
+	if err := domain.ValidateUUID(id); err != nil {
+		return fail(err)
+	}
+
+	var body DependencyRequest
+	if err := web.DecodeJSON(req.Body(), &body); err != nil {
+		return fail(err)
+	}
+
+	if domain.ValidateUUID(body.BlockerID) != nil {
+		return fail(domain.ErrInvalidDependency)
+	}
+
+	return id, body.BlockerID, nil
+}
@@ -0,0 +1,162 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/test"
+)
+
+const blockerUUID = "223e4567-e89b-12d3-a456-426614174000"
+
+func TestTodoController_Dependencies_Successfully(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetBlockersFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			blocker := buildValidTodo()
+			blocker.ID = blockerUUID
+			return []domain.Todo{blocker}, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Dependencies(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.DependenciesResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Data) != 1 || body.Data[0].ID != blockerUUID {
+		t.Errorf("expected blocker %s, got %+v", blockerUUID, body.Data)
+	}
+}
+
+func TestTodoController_AddDependency_Successfully(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.ID = id
+			todo.Dependencies = &domain.Dependencies{Total: 1, Open: 1}
+			return todo, nil
+		},
+		GetBlockerIDsFn: func(ctx context.Context, id string) ([]string, error) {
+			return []string{id}, nil
+		},
+		AddDependencyFn: func(ctx context.Context, id, blockerID string) error {
+			return nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"blocker_id": "` + blockerUUID + `"}`)
+
+	response := ctrl.AddDependency(req)
+
+	if response.Status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, response.Status)
+	}
+	var body controller.AddDependencyResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.Dependencies == nil || !body.Data.Dependencies.Blocked {
+		t.Errorf("expected the todo to be blocked, got %+v", body.Data.Dependencies)
+	}
+}
+
+func TestTodoController_AddDependency_InvalidBlockerID(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"blocker_id": "` + invalidUUID + `"}`)
+
+	response := ctrl.AddDependency(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_AddDependency_Cycle(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetBlockerIDsFn: func(ctx context.Context, id string) ([]string, error) {
+			return []string{blockerUUID, validUUID}, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"blocker_id": "` + blockerUUID + `"}`)
+
+	response := ctrl.AddDependency(req)
+
+	if response.Status != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, response.Status)
+	}
+}
+
+func TestTodoController_RemoveDependency_NotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		RemoveDependencyFn: func(ctx context.Context, id, blockerID string) error {
+			return domain.ErrDependencyNotFound
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"blocker_id": "` + blockerUUID + `"}`)
+
+	response := ctrl.RemoveDependency(req)
+
+	if response.Status != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, response.Status)
+	}
+}
+
+func TestTodoController_Get_InvalidBlocked(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("blocked", "maybe")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Update_BlockedTodoCannotStart(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Dependencies = &domain.Dependencies{Total: 1, Open: 1}
+			return todo, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"status": "in_progress"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, response.Status)
+	}
+}
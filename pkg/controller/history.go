@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/usecase"
+	"todo-api/web"
+)
+
+type (
+	HistoryEntryResponse struct {
+		ID        string                `json:"id"`
+		Action    string                `json:"action"`
+		Changes   []FieldChangeResponse `json:"changes"`
+		Caller    CallerResponse        `json:"caller"`
+		CreatedAt string                `json:"created_at"`
+	}
+
+	// FieldChangeResponse holds the values of a field before and after a change, null
+	// when the field was unset.
+	FieldChangeResponse struct {
+		Field  string `json:"field"`
+		Before any    `json:"before"`
+		After  any    `json:"after"`
+	}
+
+	CallerResponse struct {
+		App   string `json:"app,omitempty"`
+		Scope string `json:"scope,omitempty"`
+		User  string `json:"user,omitempty"`
+	}
+
+	HistoryResponse struct {
+		Data       []HistoryEntryResponse `json:"data"`
+		Total      int                    `json:"total"`
+		NextCursor string                 `json:"next_cursor,omitempty"`
+	}
+)
+
+// History lists the changes made to a todo, newest first, paged with ?limit and ?cursor.
+func (c *Todo) History(req web.Request) web.Response {
+	id, errResp := parseTodoID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	var input usecase.HistoryInput
+	if limitStr, ok := req.Query("limit"); ok {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > usecase.MaxLimit {
+			return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidLimit))
+		}
+		input.Limit = limit
+	}
+	input.Cursor, _ = req.Query("cursor")
+
+	output, err := c.usecase.History(req.Context(), id, input)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := HistoryResponse{
+		Data:       make([]HistoryEntryResponse, len(output.Entries)),
+		Total:      output.Total,
+		NextCursor: output.NextCursor,
+	}
+	for i, entry := range output.Entries {
+		response.Data[i] = MapHistoryEntryToResponse(entry)
+	}
+
+	return web.NewJSONResponse(http.StatusOK, response)
+}
+
+func MapHistoryEntryToResponse(entry domain.HistoryEntry) HistoryEntryResponse {
+	response := HistoryEntryResponse{
+		ID:        entry.ID,
+		Action:    string(entry.Action),
+		Changes:   make([]FieldChangeResponse, len(entry.Changes)),
+		Caller:    CallerResponse(entry.Caller),
+		CreatedAt: entry.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	for i, change := range entry.Changes {
+		response.Changes[i] = FieldChangeResponse(change)
+	}
+	return response
+}
@@ -0,0 +1,104 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/test"
+	"todo-api/web"
+)
+
+func TestTodoController_History_Successfully(t *testing.T) {
+	var capturedPage service.HistoryPage
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetHistoryFn: func(ctx context.Context, todoID string, page service.HistoryPage) ([]domain.HistoryEntry, error) {
+			capturedPage = page
+			return []domain.HistoryEntry{{
+				ID:        blockerUUID,
+				TodoID:    validUUID,
+				Action:    domain.HistoryUpdate,
+				Changes:   []domain.FieldChange{{Field: "status", Before: "pending", After: "completed"}},
+				Caller:    domain.Caller{App: "web", Scope: "prod"},
+				CreatedAt: fixedTime,
+			}}, nil
+		},
+		CountHistoryFn: func(ctx context.Context, todoID string) (int, error) {
+			return 1, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("limit", "5")
+
+	response := ctrl.History(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if capturedPage.Limit != 6 {
+		t.Errorf("expected one extra row to be requested, got limit %d", capturedPage.Limit)
+	}
+	var body controller.HistoryResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Data) != 1 || body.Data[0].Action != "update" || body.Data[0].Caller.App != "web" {
+		t.Fatalf("expected the update entry, got %+v", body)
+	}
+	if c := body.Data[0].Changes; len(c) != 1 || c[0].Field != "status" || c[0].After != "completed" {
+		t.Errorf("expected the status change, got %+v", c)
+	}
+}
+
+func TestTodoController_History_InvalidLimit(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("limit", "0")
+
+	response := ctrl.History(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_History_NotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.History(req)
+
+	if response.Status != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, response.Status)
+	}
+}
+
+func TestCallerInterceptor_PutsCallerInContext(t *testing.T) {
+	var caller domain.Caller
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest().
+			WithHeader("X-Api-Client-Application", "web").
+			WithHeader("X-Api-Client-Scope", "prod"),
+		NextFn: func(req *test.MockRequest) web.Response {
+			caller = domain.CallerFromContext(req.Context())
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+
+	controller.NewCallerInterceptor()(req)
+
+	if caller.App != "web" || caller.Scope != "prod" {
+		t.Errorf("expected the declared caller, got %+v", caller)
+	}
+}
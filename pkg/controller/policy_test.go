@@ -0,0 +1,29 @@
+package controller_test
+
+import (
+	"net/http"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+func TestTodoController_Create_ForbiddenByPolicy(t *testing.T) {
+	p, err := policy.New(policy.Config{DefaultRole: policy.RoleViewer, Roles: policy.Default().Roles})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.New(&test.MockTodoService{}, usecase.WithPolicy(p))
+	ctrl := controller.New(uc, newErrorHandler())
+	req := test.NewMockRequest().WithBody(`{"title": "Write report"}`)
+	req.Ctx = domain.WithCaller(req.Ctx, domain.Caller{User: "victor"})
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, response.Status)
+	}
+}
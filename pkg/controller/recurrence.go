@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/usecase"
+	"todo-api/web"
+)
+
+type (
+	// OccurrencesResponse lists upcoming due dates; it is empty when the todo does not recur.
+	OccurrencesResponse struct {
+		Recurrence *string  `json:"recurrence,omitempty"`
+		Data       []string `json:"data"`
+	}
+)
+
+// Occurrences previews the next due dates of a recurring todo, as many as ?limit asks for.
+func (c *Todo) Occurrences(req web.Request) web.Response {
+	id, ok := req.Param("id")
+	if !ok {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidID),
+		)
+	}
+
+	if err := domain.ValidateUUID(id); err != nil {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, err),
+		)
+	}
+
+	var limit int
+	if limitStr, ok := req.Query("limit"); ok {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > usecase.MaxLimit {
+			return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidLimit))
+		}
+	}
+
+	output, err := c.usecase.Occurrences(req.Context(), id, limit)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := OccurrencesResponse{
+		Data: make([]string, len(output.Occurrences)),
+	}
+	if output.Recurrence != nil {
+		recurrence := output.Recurrence.String()
+		response.Recurrence = &recurrence
+	}
+	for i, occurrence := range output.Occurrences {
+		response.Data[i] = occurrence.Format("2006-01-02T15:04:05Z")
+	}
+
+	return web.NewJSONResponse(http.StatusOK, response)
+}
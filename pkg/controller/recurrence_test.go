@@ -0,0 +1,157 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/test"
+)
+
+func TestTodoController_Create_WithRecurrence(t *testing.T) {
+	var captured service.CreateInput
+	mock := &test.MockTodoService{
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			captured = input
+			todo := buildValidTodo()
+			todo.Recurrence = input.Recurrence
+			return todo, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithBody(`{"title": "Water plants", "recurrence": "RRULE:FREQ=WEEKLY;BYDAY=SA"}`)
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, response.Status)
+	}
+	var body controller.CreateResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if captured.Recurrence == nil || body.Data.Recurrence == nil || *body.Data.Recurrence != "FREQ=WEEKLY;BYDAY=SA" {
+		t.Errorf("expected recurrence FREQ=WEEKLY;BYDAY=SA, got %v", body.Data.Recurrence)
+	}
+}
+
+func TestTodoController_Create_InvalidRecurrence(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithBody(`{"title": "Water plants", "recurrence": "FREQ=HOURLY"}`)
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Update_OnlyClearingRecurrence(t *testing.T) {
+	var captured service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			captured = input
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"recurrence": ""}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if !captured.ClearRecurrence {
+		t.Error("expected the recurrence to be cleared")
+	}
+}
+
+func TestTodoController_Update_CompletingRecurringTodoReturnsNext(t *testing.T) {
+	recurrence, _ := domain.ParseRecurrence("daily")
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Recurrence = &recurrence
+			return todo, nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Status = domain.StatusCompleted
+			todo.CompletedAt = &fixedTime
+			return todo, nil
+		},
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.DueAt = input.DueAt
+			todo.Recurrence = input.Recurrence
+			return todo, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"status": "completed"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.UpdateResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Next == nil || body.Next.DueAt == nil || *body.Next.DueAt != "2026-01-29T10:30:00Z" {
+		t.Errorf("expected the next occurrence due 2026-01-29T10:30:00Z, got %+v", body.Next)
+	}
+}
+
+func TestTodoController_Occurrences_Successfully(t *testing.T) {
+	recurrence, _ := domain.ParseRecurrence("FREQ=MONTHLY;BYMONTHDAY=-1")
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.DueAt = &fixedTime
+			todo.Recurrence = &recurrence
+			return todo, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("limit", "2")
+
+	response := ctrl.Occurrences(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.OccurrencesResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	expected := []string{"2026-01-31T10:30:00Z", "2026-02-28T10:30:00Z"}
+	if !reflect.DeepEqual(body.Data, expected) {
+		t.Errorf("expected %v, got %v", expected, body.Data)
+	}
+}
+
+func TestTodoController_Occurrences_InvalidLimit(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("limit", "0")
+
+	response := ctrl.Occurrences(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
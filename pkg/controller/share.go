@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"net/http"
+
+	"todo-api/pkg/domain"
+	"todo-api/web"
+)
+
+type (
+	// ShareRequest names the user a todo is shared with.
+	ShareRequest struct {
+		UserID string `json:"user_id"`
+	}
+
+	SharesResponse struct {
+		Data []string `json:"data"`
+	}
+
+	ShareResponse struct {
+		Data TodoResponse `json:"data"`
+	}
+)
+
+// Shares lists the users the todo was shared with.
+func (c *Todo) Shares(req web.Request) web.Response {
+	id, errResp := parseTodoID(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	output, err := c.usecase.Shares(req.Context(), id)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusOK, SharesResponse{Data: output.Users})
+}
+
+// Share lets the user named in the body see the todo.
+func (c *Todo) Share(req web.Request) web.Response {
+	id, userID, errResp := parseShare(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	output, err := c.usecase.Share(req.Context(), id, userID)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := ShareResponse{
+		Data: MapTodoToResponse(output.Todo),
+	}
+
+	return withETag(web.NewJSONResponse(http.StatusCreated, response), output.Todo)
+}
+
+// Unshare removes the share of the todo with the user named in the body.
+func (c *Todo) Unshare(req web.Request) web.Response {
+	id, userID, errResp := parseShare(req)
+	if errResp != nil {
+		return *errResp
+	}
+
+	if err := c.usecase.Unshare(req.Context(), id, userID); err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusNoContent, nil)
+}
+
+// parseShare reads the todo id from the path and the user id from the body.
+func parseShare(req web.Request) (string, string, *web.Response) {
+	id, errResp := parseTodoID(req)
+	if errResp != nil {
+		return "", "", errResp
+	}
+
+	fail := func(err error) (string, string, *web.Response) {
+		resp := web.NewJSONResponseFromError(web.NewResponseError(http.StatusBadRequest, err))
+		return "", "", &resp
+	}
+
+	var body ShareRequest
+	if err := web.DecodeJSON(req.Body(), &body); err != nil {
+		return fail(err)
+	}
+
+	if err := domain.ValidateUserID(body.UserID); err != nil {
+		return fail(err)
+	}
+
+	return id, body.UserID, nil
+}
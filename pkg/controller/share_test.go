@@ -0,0 +1,216 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/test"
+	"todo-api/web"
+)
+
+func TestTodoController_Shares_Successfully(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.SharedWith = []string{"bob", "carol"}
+			return todo, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Shares(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.SharesResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !reflect.DeepEqual(body.Data, []string{"bob", "carol"}) {
+		t.Errorf("expected bob and carol, got %v", body.Data)
+	}
+}
+
+func TestTodoController_Share_Successfully(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.SharedWith = []string{"bob"}
+			return todo, nil
+		},
+		ShareTodoFn: func(ctx context.Context, id, userID string) error {
+			if userID != "bob" {
+				t.Errorf("expected to share with bob, got %s", userID)
+			}
+			return nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"user_id": "bob"}`)
+
+	response := ctrl.Share(req)
+
+	if response.Status != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, response.Status)
+	}
+	var body controller.ShareResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !reflect.DeepEqual(body.Data.SharedWith, []string{"bob"}) {
+		t.Errorf("expected the todo to be shared with bob, got %v", body.Data.SharedWith)
+	}
+}
+
+func TestTodoController_Share_InvalidUserID(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"user_id": "bob smith"}`)
+
+	response := ctrl.Share(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Unshare_NotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UnshareTodoFn: func(ctx context.Context, id, userID string) error {
+			return domain.ErrShareNotFound
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"user_id": "bob"}`)
+
+	response := ctrl.Unshare(req)
+
+	if response.Status != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, response.Status)
+	}
+}
+
+func TestTodoController_Get_WithAssigneeMe(t *testing.T) {
+	var captured service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			captured = filters
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithQuery("assignee", "me")
+	req.Ctx = domain.WithCaller(req.Ctx, domain.Caller{User: "alice"})
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if captured.AssigneeID == nil || *captured.AssigneeID != "alice" {
+		t.Errorf("expected todos assigned to alice, got %v", captured.AssigneeID)
+	}
+}
+
+func TestTodoController_Get_AssigneeMeWithoutUser(t *testing.T) {
+	ctrl := newTestControllerWithMock(&test.MockTodoService{})
+	req := test.NewMockRequest().WithQuery("assignee", "me")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Update_ClearsAssignee(t *testing.T) {
+	var captured service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			captured = input
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"assignee_id": ""}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if !captured.ClearAssignee {
+		t.Error("expected the todo to be unassigned")
+	}
+}
+
+func TestCallerInterceptor_PutsUserOfAPIKeyInContext(t *testing.T) {
+	var caller domain.Caller
+	req := &test.MockInterceptedRequest{
+		MockRequest: test.NewMockRequest().WithHeader("X-Api-Client-User", "alice"),
+		NextFn: func(req *test.MockRequest) web.Response {
+			caller = domain.CallerFromContext(req.Context())
+			return web.NewJSONResponse(http.StatusOK, nil)
+		},
+	}
+	req.Ctx = domain.WithAPIKey(req.Ctx, domain.APIKey{App: "reminders-job", User: "alice"})
+
+	controller.NewCallerInterceptor()(req)
+
+	if caller.User != "alice" {
+		t.Errorf("expected user alice, got %+v", caller)
+	}
+}
+
+func TestCallerInterceptor_RejectsUnauthenticatedUser(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"no authentication", context.Background()},
+		{"API key without user", domain.WithAPIKey(context.Background(), domain.APIKey{App: "reminders-job"})},
+		{"API key of another user", domain.WithAPIKey(context.Background(), domain.APIKey{App: "reminders-job", User: "bob"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &test.MockInterceptedRequest{
+				MockRequest: test.NewMockRequest().WithHeader("X-Api-Client-User", "alice"),
+				NextFn: func(req *test.MockRequest) web.Response {
+					t.Error("expected the request to be rejected")
+					return web.NewJSONResponse(http.StatusOK, nil)
+				},
+			}
+			req.Ctx = tt.ctx
+
+			response := controller.NewCallerInterceptor()(req)
+
+			if response.Status != http.StatusForbidden {
+				t.Errorf("expected status %d, got %d", http.StatusForbidden, response.Status)
+			}
+		})
+	}
+}
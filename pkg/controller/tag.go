@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"net/http"
+
+	"todo-api/web"
+)
+
+type (
+	TagResponse struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	ListTagsResponse struct {
+		Data []TagResponse `json:"data"`
+	}
+)
+
+// ListTags lists the tags carried by the live todos visible to the caller with how many
+// todos carry each one, most used first.
+func (c *Todo) ListTags(req web.Request) web.Response {
+	output, err := c.usecase.ListTags(req.Context())
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := ListTagsResponse{Data: make([]TagResponse, len(output.Tags))}
+	for i, tag := range output.Tags {
+		response.Data[i] = TagResponse{Name: tag.Name, Count: tag.Count}
+	}
+
+	return web.NewJSONResponse(http.StatusOK, response)
+}
@@ -0,0 +1,199 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"todo-api/pkg/controller"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/test"
+)
+
+func TestTodoController_Get_WithTagFilter(t *testing.T) {
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithQuery("tag", "Q3").
+		WithQuery("tag", "backend").
+		WithQuery("tag_match", "all")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if !reflect.DeepEqual(capturedFilters.Tags, []string{"backend", "q3"}) || !capturedFilters.MatchAllTags {
+		t.Errorf("expected todos tagged with both backend and q3, got %+v", capturedFilters)
+	}
+}
+
+func TestTodoController_Get_InvalidTag(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("tag", "back end")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_InvalidTagMatch(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("tag", "backend").WithQuery("tag_match", "some")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Create_WithTags(t *testing.T) {
+	var added []service.TodoTags
+	mock := &test.MockTodoService{
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			added = items
+			return nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithBody(`{"title": "New Todo", "tags": ["Backend", "q3", "backend"]}`)
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, response.Status)
+	}
+	if len(added) != 1 || !reflect.DeepEqual(added[0].Names, []string{"backend", "q3"}) {
+		t.Errorf("expected normalized tags to be added, got %+v", added)
+	}
+}
+
+func TestTodoController_Create_TooManyTags(t *testing.T) {
+	ctrl := newTestController()
+	tags := make([]string, domain.MaxTags+1)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag%02d", i)
+	}
+	body, _ := json.Marshal(controller.CreateRequest{Title: "New Todo", Tags: tags})
+	req := test.NewMockRequest().WithBody(string(body))
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Update_OnlyTags(t *testing.T) {
+	var removed []service.TodoTags
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Tags = []string{"backend", "q3"}
+			return todo, nil
+		},
+		RemoveTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			removed = items
+			return nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"tags": {"remove": ["q3"]}}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if len(removed) != 1 {
+		t.Errorf("expected q3 to be removed, got %+v", removed)
+	}
+	var body controller.UpdateResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !reflect.DeepEqual(body.Data.Tags, []string{"backend"}) {
+		t.Errorf("expected tags [backend], got %v", body.Data.Tags)
+	}
+}
+
+func TestTodoController_Update_EmptyTagsIsEmptyRequest(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"tags": {}}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Update_ConflictingTags(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"tags": {"add": ["backend"], "remove": ["Backend"]}}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_ListTags_Successfully(t *testing.T) {
+	mock := &test.MockTodoService{
+		ListTagsFn: func(ctx context.Context, visibleTo *string) ([]domain.TagUsage, error) {
+			return []domain.TagUsage{{Name: "backend", Count: 3}, {Name: "q3", Count: 1}}, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+
+	response := ctrl.ListTags(test.NewMockRequest())
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.ListTagsResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	expected := []controller.TagResponse{{Name: "backend", Count: 3}, {Name: "q3", Count: 1}}
+	if !reflect.DeepEqual(body.Data, expected) {
+		t.Errorf("expected %v, got %v", expected, body.Data)
+	}
+}
+
+func TestMapTodoToResponse_AlwaysListsTags(t *testing.T) {
+	response := controller.MapTodoToResponse(buildValidTodo())
+
+	if response.Tags == nil || len(response.Tags) != 0 {
+		t.Errorf("expected an empty tag list, got %v", response.Tags)
+	}
+}
@@ -2,6 +2,10 @@ package controller
 
 import (
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"todo-api/pkg/domain"
 	"todo-api/pkg/usecase"
@@ -15,45 +19,117 @@ type (
 	}
 
 	TodoResponse struct {
-		ID          string `json:"id"`
-		Title       string `json:"title"`
+		ID          string   `json:"id"`
+		ParentID    *string  `json:"parent_id,omitempty"`
+		Title       string   `json:"title"`
+		Description string   `json:"description,omitempty"`
+		Status      string   `json:"status"`
+		Priority    string   `json:"priority"`
+		DueAt       *string  `json:"due_at,omitempty"`
+		Recurrence  *string  `json:"recurrence,omitempty"`
+		OwnerID     *string  `json:"owner_id,omitempty"`
+		AssigneeID  *string  `json:"assignee_id,omitempty"`
+		SharedWith  []string `json:"shared_with,omitempty"`
+		Overdue     bool     `json:"overdue"`
+		CompletedAt *string  `json:"completed_at,omitempty"`
+		DeletedAt   *string  `json:"deleted_at,omitempty"`
+		Version     int      `json:"version"`
+		CreatedAt   string   `json:"created_at"`
+		UpdatedAt   string   `json:"updated_at"`
+		Tags        []string `json:"tags"`
+
+		Score      *float64            `json:"score,omitempty"`
+		Highlights *HighlightsResponse `json:"highlights,omitempty"`
+
+		Subtasks     *SubtasksResponse         `json:"subtasks,omitempty"`
+		Dependencies *DependencyCountsResponse `json:"dependencies,omitempty"`
+	}
+
+	// SubtasksResponse summarises the live direct subtasks; Progress is the completed percentage.
+	SubtasksResponse struct {
+		Total     int `json:"total"`
+		Completed int `json:"completed"`
+		Progress  int `json:"progress"`
+	}
+
+	// DependencyCountsResponse summarises the todos a todo is blocked by; Blocked is set
+	// while some of them are not completed.
+	DependencyCountsResponse struct {
+		Total   int  `json:"total"`
+		Open    int  `json:"open"`
+		Blocked bool `json:"blocked"`
+	}
+
+	HighlightsResponse struct {
+		Title       string `json:"title,omitempty"`
 		Description string `json:"description,omitempty"`
-		Status      string `json:"status"`
-		Priority    string `json:"priority"`
-		CreatedAt   string `json:"created_at"`
-		UpdatedAt   string `json:"updated_at"`
 	}
 
 	GetResponse struct {
-		Data  []TodoResponse `json:"data"`
-		Total int            `json:"total"`
+		Data       []TodoResponse `json:"data"`
+		Total      int            `json:"total"`
+		NextCursor string         `json:"next_cursor,omitempty"`
 	}
 
+	// GetByIDResponse carries the subtasks when requested with ?include=children. Only the
+	// first page is embedded; ChildrenNextCursor continues on /api/todos/:id/children.
 	GetByIDResponse struct {
-		Data TodoResponse `json:"data"`
+		Data               TodoResponse    `json:"data"`
+		Children           *[]TodoResponse `json:"children,omitempty"`
+		ChildrenNextCursor string          `json:"children_next_cursor,omitempty"`
 	}
 
 	CreateRequest struct {
-		Title       string  `json:"title"`
-		Description *string `json:"description,omitempty"`
-		Status      *string `json:"status,omitempty"`
-		Priority    *string `json:"priority,omitempty"`
+		Title       string   `json:"title"`
+		Description *string  `json:"description,omitempty"`
+		Status      *string  `json:"status,omitempty"`
+		Priority    *string  `json:"priority,omitempty"`
+		DueAt       *string  `json:"due_at,omitempty"`
+		ParentID    *string  `json:"parent_id,omitempty"`
+		Tags        []string `json:"tags,omitempty"`
+		Recurrence  *string  `json:"recurrence,omitempty"`
+		AssigneeID  *string  `json:"assignee_id,omitempty"`
 	}
 
 	CreateResponse struct {
 		Data TodoResponse `json:"data"`
 	}
 
+	// UpdateRequest is a partial update. An empty due_at removes the due date, an
+	// empty parent_id turns a subtask back into a top-level todo and an empty recurrence
+	// stops the todo from recurring and an empty assignee_id unassigns it. Tags are
+	// changed relative to the current ones rather than replaced. An assignee_id of me
+	// stands for the acting user, in creations too.
 	UpdateRequest struct {
-		Title       *string `json:"title,omitempty"`
-		Description *string `json:"description,omitempty"`
-		Status      *string `json:"status,omitempty"`
-		Priority    *string `json:"priority,omitempty"`
+		Title       *string            `json:"title,omitempty"`
+		Description *string            `json:"description,omitempty"`
+		Status      *string            `json:"status,omitempty"`
+		Priority    *string            `json:"priority,omitempty"`
+		DueAt       *string            `json:"due_at,omitempty"`
+		ParentID    *string            `json:"parent_id,omitempty"`
+		Tags        *UpdateTagsRequest `json:"tags,omitempty"`
+		Recurrence  *string            `json:"recurrence,omitempty"`
+		AssigneeID  *string            `json:"assignee_id,omitempty"`
+	}
+
+	UpdateTagsRequest struct {
+		Add    []string `json:"add,omitempty"`
+		Remove []string `json:"remove,omitempty"`
 	}
 
+	// UpdateResponse carries, under next, the occurrence spawned by completing a recurring todo.
 	UpdateResponse struct {
+		Data TodoResponse  `json:"data"`
+		Next *TodoResponse `json:"next,omitempty"`
+	}
+
+	RestoreResponse struct {
 		Data TodoResponse `json:"data"`
 	}
+
+	PurgeResponse struct {
+		Purged int `json:"purged"`
+	}
 )
 
 func New(uc *usecase.Todo, errHandler web.ErrorHandler) *Todo {
@@ -71,76 +147,59 @@ func (c *Todo) Create(req web.Request) web.Response {
 		)
 	}
 
-	if len(body.Title) == 0 || len(body.Title) > 100 {
+	input, err := body.toInput()
+	if err != nil {
 		return web.NewJSONResponseFromError(
-			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidTitle),
+			web.NewResponseError(http.StatusBadRequest, err),
 		)
 	}
 
-	if body.Description != nil && len(*body.Description) > 500 {
-		return web.NewJSONResponseFromError(
-			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidDescription),
-		)
+	output, err := c.usecase.Create(req.Context(), input)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
 	}
 
-	input := usecase.CreateInput{
-		Title:       body.Title,
-		Description: body.Description,
+	response := CreateResponse{
+		Data: MapTodoToResponse(output.Todo),
 	}
 
-	if body.Status != nil {
-		status := domain.Status(*body.Status)
-		if !status.IsValid() {
-			return web.NewJSONResponseFromError(
-				web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidStatus),
-			)
-		}
-		input.Status = &status
-	}
+	return withETag(web.NewJSONResponse(http.StatusCreated, response), output.Todo)
+}
 
-	if body.Priority != nil {
-		priority := domain.Priority(*body.Priority)
-		if !priority.IsValid() {
-			return web.NewJSONResponseFromError(
-				web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidPriority),
-			)
-		}
-		input.Priority = &priority
+func (c *Todo) Get(req web.Request) web.Response {
+	input, err := parseListInput(req)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
 	}
 
-	output, err := c.usecase.Create(req.Context(), input)
+	output, err := c.usecase.Get(req.Context(), input)
 	if err != nil {
 		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
 	}
 
-	response := CreateResponse{
-		Data: MapTodoToResponse(output.Todo),
+	response := GetResponse{
+		Data:       MapTodosToResponse(output.Todos),
+		Total:      output.Total,
+		NextCursor: output.NextCursor,
 	}
 
-	return web.NewJSONResponse(http.StatusCreated, response)
+	return web.NewJSONResponse(http.StatusOK, response)
 }
 
-func (c *Todo) Get(req web.Request) web.Response {
-	input := usecase.ListInput{}
+// Trash lists soft-deleted todos, most recently deleted first.
+func (c *Todo) Trash(req web.Request) web.Response {
+	input := usecase.ListInput{Deleted: true}
 
-	if statusStr, ok := req.Query("status"); ok {
-		status := domain.Status(statusStr)
-		if !status.IsValid() {
-			return web.NewJSONResponseFromError(
-				web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidStatus),
-			)
+	if limitStr, ok := req.Query("limit"); ok {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > usecase.MaxLimit {
+			return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidLimit))
 		}
-		input.Status = &status
+		input.Limit = limit
 	}
 
-	if priorityStr, ok := req.Query("priority"); ok {
-		priority := domain.Priority(priorityStr)
-		if !priority.IsValid() {
-			return web.NewJSONResponseFromError(
-				web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidPriority),
-			)
-		}
-		input.Priority = &priority
+	if cursor, ok := req.Query("cursor"); ok {
+		input.Cursor = cursor
 	}
 
 	output, err := c.usecase.Get(req.Context(), input)
@@ -149,13 +208,17 @@ func (c *Todo) Get(req web.Request) web.Response {
 	}
 
 	response := GetResponse{
-		Data:  MapTodosToResponse(output.Todos),
-		Total: output.Total,
+		Data:       MapTodosToResponse(output.Todos),
+		Total:      output.Total,
+		NextCursor: output.NextCursor,
 	}
 
 	return web.NewJSONResponse(http.StatusOK, response)
 }
 
+// GetByID returns a todo, with a page of its children on ?include=children. Conditional
+// requests are answered from the ETag only: the response has no Last-Modified, so
+// If-Modified-Since never yields 304 Not Modified.
 func (c *Todo) GetByID(req web.Request) web.Response {
 	id, ok := req.Param("id")
 	if !ok {
@@ -170,7 +233,19 @@ func (c *Todo) GetByID(req web.Request) web.Response {
 		)
 	}
 
-	output, err := c.usecase.GetByID(req.Context(), id)
+	var input usecase.GetByIDInput
+	if include, ok := req.Query("include"); ok {
+		for _, v := range strings.Split(include, ",") {
+			switch strings.TrimSpace(v) {
+			case "children":
+				input.IncludeChildren = true
+			default:
+				return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidInclude))
+			}
+		}
+	}
+
+	output, err := c.usecase.GetByID(req.Context(), id, input)
 	if err != nil {
 		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
 	}
@@ -178,6 +253,47 @@ func (c *Todo) GetByID(req web.Request) web.Response {
 	response := GetByIDResponse{
 		Data: MapTodoToResponse(output.Todo),
 	}
+	if input.IncludeChildren {
+		children := MapTodosToResponse(output.Children)
+		response.Children = &children
+		response.ChildrenNextCursor = output.ChildrenNextCursor
+	}
+
+	// no Last-Modified: subtasks, dependencies, shares and children change the body
+	// without touching UpdatedAt, and removing one of them leaves no timestamp behind
+	return withETag(web.NewJSONResponse(http.StatusOK, response), output.Todo)
+}
+
+// Children lists the direct subtasks of a todo with the filters, sort and paging of Get.
+func (c *Todo) Children(req web.Request) web.Response {
+	id, ok := req.Param("id")
+	if !ok {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidID),
+		)
+	}
+
+	if err := domain.ValidateUUID(id); err != nil {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, err),
+		)
+	}
+
+	input, err := parseListInput(req)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	output, err := c.usecase.GetChildren(req.Context(), id, input)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := GetResponse{
+		Data:       MapTodosToResponse(output.Todos),
+		Total:      output.Total,
+		NextCursor: output.NextCursor,
+	}
 
 	return web.NewJSONResponse(http.StatusOK, response)
 }
@@ -203,62 +319,74 @@ func (c *Todo) Update(req web.Request) web.Response {
 		)
 	}
 
-	if body.Title == nil && body.Description == nil && body.Status == nil && body.Priority == nil {
+	input, err := body.toInput()
+	if err != nil {
 		return web.NewJSONResponseFromError(
-			web.NewResponseError(http.StatusBadRequest, domain.ErrEmptyUpdateRequest),
+			web.NewResponseError(http.StatusBadRequest, err),
 		)
 	}
 
-	if body.Title != nil && (len(*body.Title) == 0 || len(*body.Title) > 100) {
+	input.ExpectedVersion, err = parseIfMatch(req)
+	if err != nil {
 		return web.NewJSONResponseFromError(
-			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidTitle),
+			web.NewResponseError(http.StatusBadRequest, err),
 		)
 	}
 
-	if body.Description != nil && len(*body.Description) > 500 {
+	output, err := c.usecase.Update(req.Context(), id, input)
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	response := UpdateResponse{
+		Data: MapTodoToResponse(output.Todo),
+	}
+	if output.Next != nil {
+		next := MapTodoToResponse(*output.Next)
+		response.Next = &next
+	}
+
+	return withETag(web.NewJSONResponse(http.StatusOK, response), output.Todo)
+}
+
+func (c *Todo) Delete(req web.Request) web.Response {
+	id, ok := req.Param("id")
+	if !ok {
 		return web.NewJSONResponseFromError(
-			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidDescription),
+			web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidID),
 		)
 	}
 
-	input := usecase.UpdateInput{
-		Title:       body.Title,
-		Description: body.Description,
+	if err := domain.ValidateUUID(id); err != nil {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, err),
+		)
 	}
 
-	if body.Status != nil {
-		status := domain.Status(*body.Status)
-		if !status.IsValid() {
-			return web.NewJSONResponseFromError(
-				web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidStatus),
-			)
-		}
-		input.Status = &status
+	expectedVersion, err := parseIfMatch(req)
+	if err != nil {
+		return web.NewJSONResponseFromError(
+			web.NewResponseError(http.StatusBadRequest, err),
+		)
 	}
 
-	if body.Priority != nil {
-		priority := domain.Priority(*body.Priority)
-		if !priority.IsValid() {
-			return web.NewJSONResponseFromError(
-				web.NewResponseError(http.StatusBadRequest, domain.ErrInvalidPriority),
-			)
+	input := usecase.DeleteInput{ExpectedVersion: expectedVersion}
+	if children, ok := req.Query("children"); ok {
+		input.Children = domain.ChildPolicy(children)
+		if !input.Children.IsValid() {
+			return web.NewJSONResponseFromError(c.errHandler.Handle(domain.ErrInvalidChildPolicy))
 		}
-		input.Priority = &priority
 	}
 
-	output, err := c.usecase.Update(req.Context(), id, input)
+	err = c.usecase.Delete(req.Context(), id, input)
 	if err != nil {
 		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
 	}
 
-	response := UpdateResponse{
-		Data: MapTodoToResponse(output.Todo),
-	}
-
-	return web.NewJSONResponse(http.StatusOK, response)
+	return web.NewJSONResponse(http.StatusNoContent, nil)
 }
 
-func (c *Todo) Delete(req web.Request) web.Response {
+func (c *Todo) Restore(req web.Request) web.Response {
 	id, ok := req.Param("id")
 	if !ok {
 		return web.NewJSONResponseFromError(
@@ -272,24 +400,275 @@ func (c *Todo) Delete(req web.Request) web.Response {
 		)
 	}
 
-	err := c.usecase.Delete(req.Context(), id)
+	output, err := c.usecase.Restore(req.Context(), id)
 	if err != nil {
 		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
 	}
 
-	return web.NewJSONResponse(http.StatusNoContent, nil)
+	response := RestoreResponse{
+		Data: MapTodoToResponse(output.Todo),
+	}
+
+	return withETag(web.NewJSONResponse(http.StatusOK, response), output.Todo)
+}
+
+// Purge permanently removes the todos that outlived the trash retention.
+func (c *Todo) Purge(req web.Request) web.Response {
+	output, err := c.usecase.Purge(req.Context())
+	if err != nil {
+		return web.NewJSONResponseFromError(c.errHandler.Handle(err))
+	}
+
+	return web.NewJSONResponse(http.StatusOK, PurgeResponse{Purged: output.Purged})
+}
+
+// toInput validates the request and converts it into a usecase.CreateInput.
+func (r CreateRequest) toInput() (usecase.CreateInput, error) {
+	if len(r.Title) == 0 || len(r.Title) > 100 {
+		return usecase.CreateInput{}, domain.ErrInvalidTitle
+	}
+
+	if r.Description != nil && len(*r.Description) > 500 {
+		return usecase.CreateInput{}, domain.ErrInvalidDescription
+	}
+
+	input := usecase.CreateInput{
+		Title:       r.Title,
+		Description: r.Description,
+	}
+
+	if r.Status != nil {
+		status := domain.Status(*r.Status)
+		if !status.IsValid() {
+			return usecase.CreateInput{}, domain.ErrInvalidStatus
+		}
+		input.Status = &status
+	}
+
+	if r.Priority != nil {
+		priority := domain.Priority(*r.Priority)
+		if !priority.IsValid() {
+			return usecase.CreateInput{}, domain.ErrInvalidPriority
+		}
+		input.Priority = &priority
+	}
+
+	if r.DueAt != nil {
+		dueAt, err := domain.ParseDueAt(*r.DueAt)
+		if err != nil {
+			return usecase.CreateInput{}, err
+		}
+		input.DueAt = &dueAt
+	}
+
+	if r.ParentID != nil {
+		if domain.ValidateUUID(*r.ParentID) != nil {
+			return usecase.CreateInput{}, domain.ErrInvalidParent
+		}
+		input.ParentID = r.ParentID
+	}
+
+	if len(r.Tags) > 0 {
+		tags, err := domain.NormalizeTags(r.Tags)
+		if err != nil {
+			return usecase.CreateInput{}, err
+		}
+		if len(tags) > domain.MaxTags {
+			return usecase.CreateInput{}, domain.ErrTooManyTags
+		}
+		input.Tags = tags
+	}
+
+	if r.Recurrence != nil {
+		recurrence, err := domain.ParseRecurrence(*r.Recurrence)
+		if err != nil {
+			return usecase.CreateInput{}, err
+		}
+		input.Recurrence = &recurrence
+	}
+
+	if r.AssigneeID != nil {
+		if err := domain.ValidateAssignee(*r.AssigneeID); err != nil {
+			return usecase.CreateInput{}, err
+		}
+		input.AssigneeID = r.AssigneeID
+	}
+
+	return input, nil
+}
+
+// toInput validates the request and converts it into a usecase.UpdateInput.
+func (r UpdateRequest) toInput() (usecase.UpdateInput, error) {
+	hasTags := r.Tags != nil && len(r.Tags.Add)+len(r.Tags.Remove) > 0
+	if r.Title == nil && r.Description == nil && r.Status == nil && r.Priority == nil && r.DueAt == nil && r.ParentID == nil &&
+		r.Recurrence == nil && r.AssigneeID == nil && !hasTags {
+		return usecase.UpdateInput{}, domain.ErrEmptyUpdateRequest
+	}
+
+	if r.Title != nil && (len(*r.Title) == 0 || len(*r.Title) > 100) {
+		return usecase.UpdateInput{}, domain.ErrInvalidTitle
+	}
+
+	if r.Description != nil && len(*r.Description) > 500 {
+		return usecase.UpdateInput{}, domain.ErrInvalidDescription
+	}
+
+	input := usecase.UpdateInput{
+		Title:       r.Title,
+		Description: r.Description,
+	}
+
+	if r.Status != nil {
+		status := domain.Status(*r.Status)
+		if !status.IsValid() {
+			return usecase.UpdateInput{}, domain.ErrInvalidStatus
+		}
+		input.Status = &status
+	}
+
+	if r.Priority != nil {
+		priority := domain.Priority(*r.Priority)
+		if !priority.IsValid() {
+			return usecase.UpdateInput{}, domain.ErrInvalidPriority
+		}
+		input.Priority = &priority
+	}
+
+	if r.DueAt != nil {
+		if *r.DueAt == "" {
+			input.ClearDueAt = true
+		} else {
+			dueAt, err := domain.ParseDueAt(*r.DueAt)
+			if err != nil {
+				return usecase.UpdateInput{}, err
+			}
+			input.DueAt = &dueAt
+		}
+	}
+
+	if r.ParentID != nil {
+		if *r.ParentID == "" {
+			input.ClearParent = true
+		} else {
+			if domain.ValidateUUID(*r.ParentID) != nil {
+				return usecase.UpdateInput{}, domain.ErrInvalidParent
+			}
+			input.ParentID = r.ParentID
+		}
+	}
+
+	if r.Recurrence != nil {
+		if *r.Recurrence == "" {
+			input.ClearRecurrence = true
+		} else {
+			recurrence, err := domain.ParseRecurrence(*r.Recurrence)
+			if err != nil {
+				return usecase.UpdateInput{}, err
+			}
+			input.Recurrence = &recurrence
+		}
+	}
+
+	if r.AssigneeID != nil {
+		if *r.AssigneeID == "" {
+			input.ClearAssignee = true
+		} else {
+			if err := domain.ValidateAssignee(*r.AssigneeID); err != nil {
+				return usecase.UpdateInput{}, err
+			}
+			input.AssigneeID = r.AssigneeID
+		}
+	}
+
+	if hasTags {
+		var err error
+		if input.AddTags, err = domain.NormalizeTags(r.Tags.Add); err != nil {
+			return usecase.UpdateInput{}, err
+		}
+		if input.RemoveTags, err = domain.NormalizeTags(r.Tags.Remove); err != nil {
+			return usecase.UpdateInput{}, err
+		}
+		if len(input.AddTags) > domain.MaxTags {
+			return usecase.UpdateInput{}, domain.ErrTooManyTags
+		}
+		for _, tag := range input.RemoveTags {
+			if slices.Contains(input.AddTags, tag) {
+				return usecase.UpdateInput{}, domain.ErrConflictingTags
+			}
+		}
+	}
+
+	return input, nil
 }
 
 func MapTodoToResponse(todo domain.Todo) TodoResponse {
-	return TodoResponse{
+	response := TodoResponse{
 		ID:          todo.ID,
+		ParentID:    todo.ParentID,
 		Title:       todo.Title,
 		Description: todo.Description,
 		Status:      string(todo.Status),
 		Priority:    string(todo.Priority),
+		Overdue:     todo.IsOverdue(time.Now()),
+		Version:     todo.Version,
 		CreatedAt:   todo.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:   todo.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		Tags:        todo.Tags,
+		OwnerID:     todo.OwnerID,
+		AssigneeID:  todo.AssigneeID,
+		SharedWith:  todo.SharedWith,
+	}
+
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+
+	if todo.DueAt != nil {
+		dueAt := todo.DueAt.Format("2006-01-02T15:04:05Z")
+		response.DueAt = &dueAt
+	}
+
+	if todo.Recurrence != nil {
+		recurrence := todo.Recurrence.String()
+		response.Recurrence = &recurrence
+	}
+
+	if todo.CompletedAt != nil {
+		completedAt := todo.CompletedAt.Format("2006-01-02T15:04:05Z")
+		response.CompletedAt = &completedAt
+	}
+
+	if todo.DeletedAt != nil {
+		deletedAt := todo.DeletedAt.Format("2006-01-02T15:04:05Z")
+		response.DeletedAt = &deletedAt
+	}
+
+	if todo.Match != nil {
+		score := todo.Match.Rank
+		response.Score = &score
+		response.Highlights = &HighlightsResponse{
+			Title:       todo.Match.TitleHighlight,
+			Description: todo.Match.DescriptionHighlight,
+		}
+	}
+
+	if todo.Subtasks != nil && todo.Subtasks.Total > 0 {
+		response.Subtasks = &SubtasksResponse{
+			Total:     todo.Subtasks.Total,
+			Completed: todo.Subtasks.Completed,
+			Progress:  todo.Subtasks.Progress(),
+		}
+	}
+
+	if todo.Dependencies != nil && todo.Dependencies.Total > 0 {
+		response.Dependencies = &DependencyCountsResponse{
+			Total:   todo.Dependencies.Total,
+			Open:    todo.Dependencies.Open,
+			Blocked: todo.Dependencies.Blocked(),
+		}
 	}
+
+	return response
 }
 
 func MapTodosToResponse(todos []domain.Todo) []TodoResponse {
@@ -299,3 +678,181 @@ func MapTodosToResponse(todos []domain.Todo) []TodoResponse {
 	}
 	return result
 }
+
+// parseListInput reads the filters, sort and page shared by the list endpoints.
+func parseListInput(req web.Request) (usecase.ListInput, error) {
+	input := usecase.ListInput{}
+
+	if statusStr, ok := req.Query("status"); ok {
+		statuses, err := parseStatuses(statusStr)
+		if err != nil {
+			return usecase.ListInput{}, err
+		}
+		input.Statuses = statuses
+	}
+
+	if priorityStr, ok := req.Query("priority"); ok {
+		priorities, err := parsePriorities(priorityStr)
+		if err != nil {
+			return usecase.ListInput{}, err
+		}
+		input.Priorities = priorities
+	}
+
+	if q, ok := req.Query("q"); ok {
+		q = strings.TrimSpace(q)
+		if len(q) > 200 {
+			return usecase.ListInput{}, domain.ErrInvalidSearchQuery
+		}
+		input.Query = q
+	}
+
+	if dueBeforeStr, ok := req.Query("due_before"); ok {
+		dueBefore, err := domain.ParseDueAt(dueBeforeStr)
+		if err != nil {
+			return usecase.ListInput{}, err
+		}
+		input.DueBefore = &dueBefore
+	}
+
+	if dueAfterStr, ok := req.Query("due_after"); ok {
+		dueAfter, err := domain.ParseDueAt(dueAfterStr)
+		if err != nil {
+			return usecase.ListInput{}, err
+		}
+		input.DueAfter = &dueAfter
+	}
+
+	if input.DueBefore != nil && input.DueAfter != nil && !input.DueAfter.Before(*input.DueBefore) {
+		return usecase.ListInput{}, domain.ErrInvalidDueRange
+	}
+
+	if overdueStr, ok := req.Query("overdue"); ok {
+		overdue, err := strconv.ParseBool(overdueStr)
+		if err != nil {
+			return usecase.ListInput{}, domain.ErrInvalidOverdue
+		}
+		input.Overdue = &overdue
+	}
+
+	if values := req.Queries()["tag"]; len(values) > 0 {
+		var tags []string
+		for _, v := range values {
+			tags = append(tags, strings.Split(v, ",")...)
+		}
+		normalized, err := domain.NormalizeTags(tags)
+		if err != nil {
+			return usecase.ListInput{}, err
+		}
+		input.Tags = normalized
+	}
+
+	if tagMatch, ok := req.Query("tag_match"); ok {
+		input.TagMatch = domain.TagMatch(tagMatch)
+		if !input.TagMatch.IsValid() {
+			return usecase.ListInput{}, domain.ErrInvalidTagMatch
+		}
+	}
+
+	if blockedStr, ok := req.Query("blocked"); ok {
+		blocked, err := strconv.ParseBool(blockedStr)
+		if err != nil {
+			return usecase.ListInput{}, domain.ErrInvalidBlocked
+		}
+		input.Blocked = &blocked
+	}
+
+	if assignee, ok := req.Query("assignee"); ok {
+		if err := domain.ValidateAssignee(assignee); err != nil {
+			return usecase.ListInput{}, err
+		}
+		input.Assignee = assignee
+	}
+
+	if sortStr, ok := req.Query("sort"); ok {
+		sort, err := domain.ParseSort(sortStr)
+		if err != nil {
+			return usecase.ListInput{}, err
+		}
+		input.Sort = sort
+	}
+
+	if limitStr, ok := req.Query("limit"); ok {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > usecase.MaxLimit {
+			return usecase.ListInput{}, domain.ErrInvalidLimit
+		}
+		input.Limit = limit
+	}
+
+	if cursor, ok := req.Query("cursor"); ok {
+		input.Cursor = cursor
+	}
+
+	return input, nil
+}
+
+func parseStatuses(s string) ([]domain.Status, error) {
+	parts := strings.Split(s, ",")
+	statuses := make([]domain.Status, len(parts))
+	for i, p := range parts {
+		status := domain.Status(strings.TrimSpace(p))
+		if !status.IsValid() {
+			return nil, domain.ErrInvalidStatus
+		}
+		statuses[i] = status
+	}
+	return statuses, nil
+}
+
+func parsePriorities(s string) ([]domain.Priority, error) {
+	parts := strings.Split(s, ",")
+	priorities := make([]domain.Priority, len(parts))
+	for i, p := range parts {
+		priority := domain.Priority(strings.TrimSpace(p))
+		if !priority.IsValid() {
+			return nil, domain.ErrInvalidPriority
+		}
+		priorities[i] = priority
+	}
+	return priorities, nil
+}
+
+// withETag exposes the todo version as a strong entity tag so clients can send it back in If-Match.
+func withETag(resp web.Response, todo domain.Todo) web.Response {
+	resp.Headers.Set("ETag", strconv.Quote(strconv.Itoa(todo.Version)))
+	return resp
+}
+
+// parseIfMatch reads the expected version from the If-Match header.
+// A missing header or * means the write is unconditional and yields nil.
+func parseIfMatch(req web.Request) (*int, error) {
+	values, ok := req.Header("If-Match")
+	if !ok || len(values) == 0 {
+		return nil, nil
+	}
+	if len(values) > 1 {
+		return nil, domain.ErrInvalidIfMatch
+	}
+	return parseVersionTag(values[0])
+}
+
+// parseVersionTag turns an entity tag issued by withETag back into a version; * yields nil.
+// The body hash the conditional GET interceptor appends to the tag, as in "3-<hash>", is ignored.
+func parseVersionTag(tag string) (*int, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "*" {
+		return nil, nil
+	}
+
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil || !strings.HasPrefix(tag, `"`) {
+		return nil, domain.ErrInvalidIfMatch
+	}
+	unquoted, _, _ = strings.Cut(unquoted, "-")
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return nil, domain.ErrInvalidIfMatch
+	}
+	return &version, nil
+}
@@ -2,6 +2,7 @@ package controller_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"testing"
@@ -49,6 +50,7 @@ func buildValidTodo() domain.Todo {
 		Description: "This is a test description",
 		Status:      domain.StatusPending,
 		Priority:    domain.PriorityMedium,
+		Version:     1,
 		CreatedAt:   fixedTime,
 		UpdatedAt:   fixedTime,
 	}
@@ -62,6 +64,59 @@ func newErrorHandler() web.ErrorHandler {
 		web.NewErrorHandlerValueMapper(domain.ErrInvalidTitle, http.StatusBadRequest),
 		web.NewErrorHandlerValueMapper(domain.ErrInvalidID, http.StatusBadRequest),
 		web.NewErrorHandlerValueMapper(domain.ErrEmptyUpdateRequest, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidLimit, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidCursor, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidSort, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidSearchQuery, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrRelevanceSortQuery, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidDueAt, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidDueRange, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidOverdue, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidTransition, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrVersionMismatch, http.StatusPreconditionFailed),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBatchSize, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBatchMode, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBatchOp, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrDuplicateBatchID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrBatchAborted, http.StatusFailedDependency),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidParent, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrParentCycle, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrTodoHasChildren, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrParentTrashed, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidChildPolicy, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidInclude, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidTag, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrTooManyTags, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrConflictingTags, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidTagMatch, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidDependency, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrDependencyCycle, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrDependencyNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrTodoBlocked, http.StatusConflict),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidBlocked, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidRecurrence, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrCommentNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidCommentID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidCommentBody, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidUserID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAssignee, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrShareNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrAttachmentNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAttachmentID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrMissingAttachment, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAttachmentName, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge),
+		web.NewErrorHandlerValueMapper(domain.ErrUnsupportedAttachmentType, http.StatusUnsupportedMediaType),
+		web.NewErrorHandlerValueMapper(domain.ErrUnauthenticated, http.StatusUnauthorized),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKey, http.StatusUnauthorized),
+		web.NewErrorHandlerValueMapper(domain.ErrInsufficientScope, http.StatusForbidden),
+		web.NewErrorHandlerValueMapper(domain.ErrUnverifiedUser, http.StatusForbidden),
+		web.NewErrorHandlerValueMapper(domain.ErrForbidden, http.StatusForbidden),
+		web.NewErrorHandlerValueMapper(domain.ErrAPIKeyNotFound, http.StatusNotFound),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKeyID, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKeyName, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidAPIKeyApp, http.StatusBadRequest),
+		web.NewErrorHandlerValueMapper(domain.ErrInvalidScope, http.StatusBadRequest),
 	)
 }
 
@@ -77,9 +132,12 @@ func newTestControllerWithMock(mockService *test.MockTodoService) *controller.To
 func TestTodoController_Get_Successfully(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	mock := &test.MockTodoService{
-		GetFn: func(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
 			return []domain.Todo{expectedTodo}, nil
 		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 1, nil
+		},
 	}
 	ctrl := newTestControllerWithMock(mock)
 	req := test.NewMockRequest()
@@ -93,9 +151,12 @@ func TestTodoController_Get_Successfully(t *testing.T) {
 
 func TestTodoController_Get_WithValidStatusFilter(t *testing.T) {
 	mock := &test.MockTodoService{
-		GetFn: func(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
 			return []domain.Todo{}, nil
 		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
 	}
 	ctrl := newTestControllerWithMock(mock)
 	req := test.NewMockRequest().WithQuery("status", "pending")
@@ -109,9 +170,12 @@ func TestTodoController_Get_WithValidStatusFilter(t *testing.T) {
 
 func TestTodoController_Get_WithValidPriorityFilter(t *testing.T) {
 	mock := &test.MockTodoService{
-		GetFn: func(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
 			return []domain.Todo{}, nil
 		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
 	}
 	ctrl := newTestControllerWithMock(mock)
 	req := test.NewMockRequest().WithQuery("priority", "high")
@@ -125,7 +189,7 @@ func TestTodoController_Get_WithValidPriorityFilter(t *testing.T) {
 
 func TestTodoController_Get_ServiceError(t *testing.T) {
 	mock := &test.MockTodoService{
-		GetFn: func(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
 			return nil, errors.New("database error")
 		},
 	}
@@ -161,6 +225,210 @@ func TestTodoController_Get_InvalidPriorityFilter(t *testing.T) {
 	}
 }
 
+func TestTodoController_Get_WithValidLimit(t *testing.T) {
+	var capturedPage service.Page
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedPage = page
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithQuery("limit", "5")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if capturedPage.Limit != 6 {
+		t.Errorf("expected page limit %d, got %d", 6, capturedPage.Limit)
+	}
+}
+
+func TestTodoController_Get_NonNumericLimit(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("limit", "abc")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_LimitTooLarge(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("limit", "101")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_LimitZero(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("limit", "0")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_InvalidCursor(t *testing.T) {
+	ctrl := newTestControllerWithMock(&test.MockTodoService{})
+	req := test.NewMockRequest().WithQuery("cursor", "garbage")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_WithMultipleStatuses(t *testing.T) {
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithQuery("status", "pending,in_progress")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if len(capturedFilters.Statuses) != 2 {
+		t.Errorf("expected 2 statuses, got %d", len(capturedFilters.Statuses))
+	}
+}
+
+func TestTodoController_Get_InvalidStatusInList(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("status", "pending,"+invalidStatus)
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_WithValidSort(t *testing.T) {
+	var capturedPage service.Page
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedPage = page
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithQuery("sort", "-priority,updated_at")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if len(capturedPage.Sort) != 2 {
+		t.Errorf("expected 2 sort keys, got %d", len(capturedPage.Sort))
+	}
+}
+
+func TestTodoController_Get_InvalidSort(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("sort", "-description")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_SearchQueryTooLong(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("q", buildLongString(201))
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_WithDueFilters(t *testing.T) {
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithQuery("due_after", "2026-01-01T00:00:00Z").
+		WithQuery("due_before", "2026-02-01T00:00:00-03:00").
+		WithQuery("overdue", "true")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	expectedBefore := time.Date(2026, 2, 1, 3, 0, 0, 0, time.UTC)
+	if capturedFilters.DueBefore == nil || !capturedFilters.DueBefore.Equal(expectedBefore) {
+		t.Errorf("expected due_before %v, got %v", expectedBefore, capturedFilters.DueBefore)
+	}
+	if capturedFilters.Overdue == nil || !*capturedFilters.Overdue {
+		t.Error("expected overdue filter to be true")
+	}
+}
+
+func TestTodoController_Get_InvalidDueRange(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithQuery("due_after", "2026-02-01T00:00:00Z").
+		WithQuery("due_before", "2026-01-01T00:00:00Z")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Get_InvalidOverdue(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("overdue", "maybe")
+
+	response := ctrl.Get(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
 func TestTodoController_GetByID_Successfully(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	mock := &test.MockTodoService{
@@ -178,6 +446,38 @@ func TestTodoController_GetByID_Successfully(t *testing.T) {
 	}
 }
 
+func TestTodoController_GetByID_SetsETag(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.GetByID(req)
+
+	if etag := response.Headers.Get("ETag"); etag != `"1"` {
+		t.Errorf("expected ETag %q, got %q", `"1"`, etag)
+	}
+}
+
+func TestTodoController_GetByID_OmitsLastModified(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.GetByID(req)
+
+	if lastModified := response.Headers.Get("Last-Modified"); lastModified != "" {
+		t.Errorf("expected no Last-Modified, got %q", lastModified)
+	}
+}
+
 func TestTodoController_GetByID_NotFound(t *testing.T) {
 	mock := &test.MockTodoService{
 		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
@@ -349,9 +649,44 @@ func TestTodoController_Create_DescriptionTooLong(t *testing.T) {
 	}
 }
 
+func TestTodoController_Create_WithDueAt(t *testing.T) {
+	var capturedInput service.CreateInput
+	mock := &test.MockTodoService{
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithBody(`{"title": "Test", "due_at": "2026-03-01T12:00:00Z"}`)
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, response.Status)
+	}
+	if capturedInput.DueAt == nil {
+		t.Error("expected due date to be passed to service")
+	}
+}
+
+func TestTodoController_Create_ImpossibleDueAt(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithBody(`{"title": "Test", "due_at": "2026-02-30T12:00:00Z"}`)
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
 func TestTodoController_Update_Successfully(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
 		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
 			return expectedTodo, nil
 		},
@@ -371,6 +706,9 @@ func TestTodoController_Update_Successfully(t *testing.T) {
 func TestTodoController_Update_WithStatusAndPriority(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
 		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
 			return expectedTodo, nil
 		},
@@ -387,9 +725,32 @@ func TestTodoController_Update_WithStatusAndPriority(t *testing.T) {
 	}
 }
 
+func TestTodoController_Update_IllegalTransition(t *testing.T) {
+	current := buildValidTodo()
+	current.Status = domain.StatusCompleted
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return current, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"status": "pending"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, response.Status)
+	}
+}
+
 func TestTodoController_Update_WithDescription(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
 		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
 			return expectedTodo, nil
 		},
@@ -408,6 +769,9 @@ func TestTodoController_Update_WithDescription(t *testing.T) {
 
 func TestTodoController_Update_NotFound(t *testing.T) {
 	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
 		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
 			return domain.Todo{}, domain.ErrTodoNotFound
 		},
@@ -424,37 +788,153 @@ func TestTodoController_Update_NotFound(t *testing.T) {
 	}
 }
 
-func TestTodoController_Update_DescriptionTooLong(t *testing.T) {
-	ctrl := newTestController()
+func TestTodoController_Update_PassesIfMatchVersion(t *testing.T) {
+	var capturedInput service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
 	req := test.NewMockRequest().
 		WithParam("id", validUUID).
-		WithBody(`{"description": "` + descriptionTooLong() + `"}`)
+		WithHeader("If-Match", `"3"`).
+		WithBody(`{"title": "Updated"}`)
 
 	response := ctrl.Update(req)
 
-	if response.Status != http.StatusBadRequest {
-		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if capturedInput.ExpectedVersion == nil || *capturedInput.ExpectedVersion != 3 {
+		t.Errorf("expected version 3 to be passed to service, got %v", capturedInput.ExpectedVersion)
+	}
+	if etag := response.Headers.Get("ETag"); etag != `"1"` {
+		t.Errorf("expected ETag %q, got %q", `"1"`, etag)
 	}
 }
 
-func TestTodoController_Update_InvalidPriority(t *testing.T) {
-	ctrl := newTestController()
+func TestTodoController_Update_AcceptsIfMatchWithBodyHash(t *testing.T) {
+	var capturedInput service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
 	req := test.NewMockRequest().
 		WithParam("id", validUUID).
-		WithBody(`{"priority": "` + invalidPriority + `"}`)
+		WithHeader("If-Match", `"3-9f86d081884c7d659a2feaa0c55ad015"`).
+		WithBody(`{"title": "Updated"}`)
 
-	response := ctrl.Update(req)
+	ctrl.Update(req)
 
-	if response.Status != http.StatusBadRequest {
-		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	if capturedInput.ExpectedVersion == nil || *capturedInput.ExpectedVersion != 3 {
+		t.Errorf("expected version 3 to be passed to service, got %v", capturedInput.ExpectedVersion)
 	}
 }
 
-func TestTodoController_Update_InvalidJSON(t *testing.T) {
-	ctrl := newTestController()
+func TestTodoController_Update_WildcardIfMatchIsUnconditional(t *testing.T) {
+	var capturedInput service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
 	req := test.NewMockRequest().
 		WithParam("id", validUUID).
-		WithBody("invalid json")
+		WithHeader("If-Match", "*").
+		WithBody(`{"title": "Updated"}`)
+
+	ctrl.Update(req)
+
+	if capturedInput.ExpectedVersion != nil {
+		t.Errorf("expected no version, got %d", *capturedInput.ExpectedVersion)
+	}
+}
+
+func TestTodoController_Update_StaleVersion(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrVersionMismatch
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithHeader("If-Match", `"1"`).
+		WithBody(`{"title": "Updated"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusPreconditionFailed {
+		t.Errorf("expected status %d, got %d", http.StatusPreconditionFailed, response.Status)
+	}
+}
+
+func TestTodoController_Update_InvalidIfMatch(t *testing.T) {
+	for _, value := range []string{`W/"1"`, "1", `"abc"`, `"0"`} {
+		ctrl := newTestController()
+		req := test.NewMockRequest().
+			WithParam("id", validUUID).
+			WithHeader("If-Match", value).
+			WithBody(`{"title": "Updated"}`)
+
+		response := ctrl.Update(req)
+
+		if response.Status != http.StatusBadRequest {
+			t.Errorf("If-Match %s: expected status %d, got %d", value, http.StatusBadRequest, response.Status)
+		}
+	}
+}
+
+func TestTodoController_Update_DescriptionTooLong(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"description": "` + descriptionTooLong() + `"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Update_InvalidPriority(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody(`{"priority": "` + invalidPriority + `"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Update_InvalidJSON(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithBody("invalid json")
 
 	response := ctrl.Update(req)
 
@@ -537,9 +1017,47 @@ func TestTodoController_Update_InvalidStatus(t *testing.T) {
 	}
 }
 
+func TestTodoController_Update_EmptyDueAtClearsIt(t *testing.T) {
+	var capturedInput service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID).WithBody(`{"due_at": ""}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if !capturedInput.ClearDueAt {
+		t.Error("expected due date to be cleared")
+	}
+}
+
+func TestTodoController_Update_InvalidDueAt(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithParam("id", validUUID).WithBody(`{"due_at": "tomorrow"}`)
+
+	response := ctrl.Update(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
 func TestTodoController_Delete_Successfully(t *testing.T) {
 	mock := &test.MockTodoService{
-		DeleteFn: func(ctx context.Context, id string) error {
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
 			return nil
 		},
 	}
@@ -555,7 +1073,10 @@ func TestTodoController_Delete_Successfully(t *testing.T) {
 
 func TestTodoController_Delete_NotFound(t *testing.T) {
 	mock := &test.MockTodoService{
-		DeleteFn: func(ctx context.Context, id string) error {
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
 			return domain.ErrTodoNotFound
 		},
 	}
@@ -569,6 +1090,34 @@ func TestTodoController_Delete_NotFound(t *testing.T) {
 	}
 }
 
+func TestTodoController_Delete_StaleVersion(t *testing.T) {
+	var capturedInput service.DeleteInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Version = 2
+			return todo, nil
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
+			capturedInput = input
+			return domain.ErrVersionMismatch
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().
+		WithParam("id", validUUID).
+		WithHeader("If-Match", `"2"`)
+
+	response := ctrl.Delete(req)
+
+	if response.Status != http.StatusPreconditionFailed {
+		t.Errorf("expected status %d, got %d", http.StatusPreconditionFailed, response.Status)
+	}
+	if capturedInput.ExpectedVersion == nil || *capturedInput.ExpectedVersion != 2 {
+		t.Errorf("expected version 2 to be passed to service, got %v", capturedInput.ExpectedVersion)
+	}
+}
+
 func TestTodoController_Delete_MissingParam(t *testing.T) {
 	ctrl := newTestController()
 	req := test.NewMockRequest()
@@ -591,6 +1140,130 @@ func TestTodoController_Delete_InvalidUUID(t *testing.T) {
 	}
 }
 
+func TestTodoController_Trash_ListsDeletedTodos(t *testing.T) {
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 1, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest()
+
+	response := ctrl.Trash(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if !capturedFilters.Deleted {
+		t.Error("expected the trash to be listed")
+	}
+}
+
+func TestTodoController_Trash_InvalidLimit(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithQuery("limit", "0")
+
+	response := ctrl.Trash(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Restore_Successfully(t *testing.T) {
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Restore(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if etag := response.Headers.Get("ETag"); etag != `"1"` {
+		t.Errorf("expected ETag %q, got %q", `"1"`, etag)
+	}
+}
+
+func TestTodoController_Restore_NotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Restore(req)
+
+	if response.Status != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, response.Status)
+	}
+}
+
+func TestTodoController_Restore_ParentTrashed(t *testing.T) {
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrParentTrashed
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Restore(req)
+
+	if response.Status != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, response.Status)
+	}
+}
+
+func TestTodoController_Restore_InvalidUUID(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithParam("id", invalidUUID)
+
+	response := ctrl.Restore(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Purge_ReturnsPurgedCount(t *testing.T) {
+	mock := &test.MockTodoService{
+		PurgeFn: func(ctx context.Context, deletedBefore time.Time) (service.PurgeResult, error) {
+			return service.PurgeResult{Purged: 3}, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+
+	response := ctrl.Purge(test.NewMockRequest())
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if string(response.Body) != `{"purged":3}` {
+		t.Errorf("expected purged count in body, got %s", response.Body)
+	}
+}
+
 func TestMapTodoToResponse(t *testing.T) {
 	todo := buildValidTodo()
 
@@ -620,6 +1293,73 @@ func TestMapTodoToResponse(t *testing.T) {
 	}
 }
 
+func TestMapTodoToResponse_WithSearchMatch(t *testing.T) {
+	todo := buildValidTodo()
+	todo.Match = &domain.SearchMatch{
+		Rank:           0.25,
+		TitleHighlight: "<mark>Test</mark> Todo",
+	}
+
+	result := controller.MapTodoToResponse(todo)
+
+	if result.Score == nil || *result.Score != 0.25 {
+		t.Errorf("expected score 0.25, got %v", result.Score)
+	}
+	if result.Highlights == nil || result.Highlights.Title != "<mark>Test</mark> Todo" {
+		t.Errorf("expected title highlight, got %v", result.Highlights)
+	}
+}
+
+func TestMapTodoToResponse_WithoutSearchMatch(t *testing.T) {
+	result := controller.MapTodoToResponse(buildValidTodo())
+
+	if result.Score != nil {
+		t.Errorf("expected no score, got %v", *result.Score)
+	}
+	if result.Highlights != nil {
+		t.Errorf("expected no highlights, got %v", result.Highlights)
+	}
+}
+
+func TestMapTodoToResponse_DeletedTodo(t *testing.T) {
+	todo := buildValidTodo()
+	deletedAt := fixedTime.Add(time.Hour)
+	todo.DeletedAt = &deletedAt
+
+	result := controller.MapTodoToResponse(todo)
+
+	if result.DeletedAt == nil || *result.DeletedAt != "2026-01-28T11:30:00Z" {
+		t.Errorf("expected deleted_at 2026-01-28T11:30:00Z, got %v", result.DeletedAt)
+	}
+}
+
+func TestMapTodoToResponse_OverdueTodo(t *testing.T) {
+	todo := buildValidTodo()
+	dueAt := fixedTime.Add(-time.Hour)
+	todo.DueAt = &dueAt
+
+	result := controller.MapTodoToResponse(todo)
+
+	if result.DueAt == nil || *result.DueAt != "2026-01-28T09:30:00Z" {
+		t.Errorf("expected due date %s, got %v", "2026-01-28T09:30:00Z", result.DueAt)
+	}
+	if !result.Overdue {
+		t.Error("expected todo to be overdue")
+	}
+}
+
+func TestMapTodoToResponse_CompletedTodo(t *testing.T) {
+	todo := buildValidTodo()
+	todo.Status = domain.StatusCompleted
+	todo.CompletedAt = &fixedTime
+
+	result := controller.MapTodoToResponse(todo)
+
+	if result.CompletedAt == nil || *result.CompletedAt != "2026-01-28T10:30:00Z" {
+		t.Errorf("expected completed_at %s, got %v", "2026-01-28T10:30:00Z", result.CompletedAt)
+	}
+}
+
 func TestMapTodosToResponse_Multiple(t *testing.T) {
 	todo1 := buildValidTodo()
 	todo1.ID = "1"
@@ -649,3 +1389,137 @@ func TestMapTodosToResponse_Empty(t *testing.T) {
 		t.Errorf("expected 0 responses, got %d", len(responses))
 	}
 }
+
+func TestTodoController_GetByID_IncludesChildren(t *testing.T) {
+	parent := buildValidTodo()
+	parent.Subtasks = &domain.Subtasks{Total: 1}
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return parent, nil
+		},
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 1, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("include", "children")
+
+	response := ctrl.GetByID(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	var body controller.GetByIDResponse
+	if err := json.Unmarshal(response.Body, &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Children == nil || len(*body.Children) != 1 {
+		t.Errorf("expected one embedded child, got %v", body.Children)
+	}
+}
+
+func TestTodoController_GetByID_InvalidInclude(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("include", "parent")
+
+	response := ctrl.GetByID(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Children_Successfully(t *testing.T) {
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("status", "pending")
+
+	response := ctrl.Children(req)
+
+	if response.Status != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, response.Status)
+	}
+	if capturedFilters.ParentID == nil || *capturedFilters.ParentID != validUUID {
+		t.Errorf("expected children of %s, got %v", validUUID, capturedFilters.ParentID)
+	}
+	if len(capturedFilters.Statuses) != 1 {
+		t.Errorf("expected status filter to be applied, got %v", capturedFilters.Statuses)
+	}
+}
+
+func TestTodoController_Children_InvalidUUID(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithParam("id", invalidUUID)
+
+	response := ctrl.Children(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Create_InvalidParentID(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithBody(`{"title":"Subtask","parent_id":"` + invalidUUID + `"}`)
+
+	response := ctrl.Create(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestTodoController_Delete_ConflictWhenTodoHasSubtasks(t *testing.T) {
+	parent := buildValidTodo()
+	parent.Subtasks = &domain.Subtasks{Total: 1}
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return parent, nil
+		},
+	}
+	ctrl := newTestControllerWithMock(mock)
+	req := test.NewMockRequest().WithParam("id", validUUID)
+
+	response := ctrl.Delete(req)
+
+	if response.Status != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, response.Status)
+	}
+}
+
+func TestTodoController_Delete_InvalidChildrenPolicy(t *testing.T) {
+	ctrl := newTestController()
+	req := test.NewMockRequest().WithParam("id", validUUID).WithQuery("children", "keep")
+
+	response := ctrl.Delete(req)
+
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestMapTodoToResponse_WithSubtasks(t *testing.T) {
+	todo := buildValidTodo()
+	todo.Subtasks = &domain.Subtasks{Total: 4, Completed: 3}
+
+	result := controller.MapTodoToResponse(todo)
+
+	if result.Subtasks == nil || result.Subtasks.Progress != 75 {
+		t.Errorf("expected progress 75, got %+v", result.Subtasks)
+	}
+}
@@ -0,0 +1,82 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/pkg/domain"
+)
+
+func TestAPIKey_HasScope(t *testing.T) {
+	key := domain.APIKey{Scopes: []string{domain.ScopeTodosRead}}
+
+	if !key.HasScope(domain.ScopeTodosRead) {
+		t.Error("expected the key to grant todos:read")
+	}
+	if key.HasScope(domain.ScopeTodosWrite) {
+		t.Error("expected the key not to grant todos:write")
+	}
+}
+
+func TestAPIKey_Revoked(t *testing.T) {
+	now := time.Now()
+	if (domain.APIKey{}).Revoked() {
+		t.Error("expected a key without revocation date to be live")
+	}
+	if !(domain.APIKey{RevokedAt: &now}).Revoked() {
+		t.Error("expected a key with a revocation date to be revoked")
+	}
+}
+
+func TestValidateAPIKeyName(t *testing.T) {
+	if err := domain.ValidateAPIKeyName("nightly reminders"); err != nil {
+		t.Errorf("expected a valid name, got %v", err)
+	}
+	for _, name := range []string{"", "   ", strings.Repeat("a", domain.MaxAPIKeyNameLength+1)} {
+		if err := domain.ValidateAPIKeyName(name); !errors.Is(err, domain.ErrInvalidAPIKeyName) {
+			t.Errorf("expected ErrInvalidAPIKeyName for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestValidateAPIKeyApp(t *testing.T) {
+	if err := domain.ValidateAPIKeyApp("reminders-job"); err != nil {
+		t.Errorf("expected a valid app, got %v", err)
+	}
+	for _, app := range []string{"", "reminders job"} {
+		if err := domain.ValidateAPIKeyApp(app); !errors.Is(err, domain.ErrInvalidAPIKeyApp) {
+			t.Errorf("expected ErrInvalidAPIKeyApp for %q, got %v", app, err)
+		}
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	if err := domain.ValidateScopes([]string{domain.ScopeTodosRead, domain.ScopeTodosWrite}); err != nil {
+		t.Errorf("expected valid scopes, got %v", err)
+	}
+
+	invalid := [][]string{
+		nil,
+		{"todos:delete"},
+		{domain.ScopeTodosRead, domain.ScopeTodosRead},
+	}
+	for _, scopes := range invalid {
+		if err := domain.ValidateScopes(scopes); !errors.Is(err, domain.ErrInvalidScope) {
+			t.Errorf("expected ErrInvalidScope for %v, got %v", scopes, err)
+		}
+	}
+}
+
+func TestAPIKeyFromContext(t *testing.T) {
+	if _, ok := domain.APIKeyFromContext(context.Background()); ok {
+		t.Error("expected no API key in an empty context")
+	}
+
+	ctx := domain.WithAPIKey(context.Background(), domain.APIKey{ID: "key", App: "reminders-job"})
+	if key, ok := domain.APIKeyFromContext(ctx); !ok || key.App != "reminders-job" {
+		t.Errorf("expected the key of reminders-job, got %+v %v", key, ok)
+	}
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxAttachmentSize is the largest file, in bytes, accepted when no other limit is configured.
+	DefaultMaxAttachmentSize int64 = 10 << 20
+	// MaxAttachmentNameLength is the maximum number of characters in the name of an attached file.
+	MaxAttachmentNameLength = 255
+)
+
+// DefaultAttachmentTypes lists the media types accepted when no other list is configured.
+// Types are matched against the sniffed content, parameters such as charset aside.
+var DefaultAttachmentTypes = []string{
+	"application/pdf",
+	"application/zip",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"text/plain",
+}
+
+type (
+	// Attachment describes a file attached to a todo. Its content lives in a blob store
+	// under StorageKey; ContentType is the type sniffed from the content, not the one
+	// declared by the client.
+	Attachment struct {
+		ID          string
+		TodoID      string
+		Filename    string
+		ContentType string
+		Size        int64
+		StorageKey  string
+		CreatedAt   time.Time
+	}
+)
+
+// CleanAttachmentName keeps the last element of the name a client sent for a file, which
+// may be a full path on its machine, and rejects it when empty, too long or when it holds
+// control characters.
+func CleanAttachmentName(name string) (string, error) {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == ".." || name == "/" || strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > MaxAttachmentNameLength {
+		return "", ErrInvalidAttachmentName
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", ErrInvalidAttachmentName
+	}
+	return name, nil
+}
+
+// MediaTypeAllowed reports whether contentType, parameters aside, is one of the allowed types.
+func MediaTypeAllowed(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(mediaType, a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/domain"
+)
+
+func TestCleanAttachmentName_KeepsBaseName(t *testing.T) {
+	cases := map[string]string{
+		"report.pdf":                "report.pdf",
+		"/home/alice/report.pdf":    "report.pdf",
+		`C:\Users\alice\report.pdf`: "report.pdf",
+		"../../etc/passwd":          "passwd",
+	}
+	for input, expected := range cases {
+		got, err := domain.CleanAttachmentName(input)
+		if err != nil || got != expected {
+			t.Errorf("expected %q to become %q, got %q (%v)", input, expected, got, err)
+		}
+	}
+}
+
+func TestCleanAttachmentName_RejectsInvalidNames(t *testing.T) {
+	for _, name := range []string{"", " ", "/", "..", "a\x00b", strings.Repeat("a", 256)} {
+		if _, err := domain.CleanAttachmentName(name); !errors.Is(err, domain.ErrInvalidAttachmentName) {
+			t.Errorf("expected ErrInvalidAttachmentName for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestMediaTypeAllowed_IgnoresParameters(t *testing.T) {
+	if !domain.MediaTypeAllowed("text/plain; charset=utf-8", domain.DefaultAttachmentTypes) {
+		t.Error("expected plain text to be allowed")
+	}
+	if domain.MediaTypeAllowed("text/html; charset=utf-8", domain.DefaultAttachmentTypes) {
+		t.Error("expected html to be refused")
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "context"
+
+type (
+	// Caller identifies who is acting on the API: the client application and scope it
+	// declared and, once authenticated, the user behind it.
+	Caller struct {
+		App   string
+		Scope string
+		User  string
+	}
+
+	callerKey struct{}
+)
+
+// WithCaller returns a copy of ctx carrying the caller.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller carried by ctx, or the zero Caller when there is none.
+func CallerFromContext(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
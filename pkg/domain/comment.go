@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCommentLength is the maximum number of characters in the body of a comment.
+const MaxCommentLength = 2000
+
+type (
+	// Comment is a message in the discussion thread of a todo. Author is the user who
+	// wrote it, empty when it was written without one.
+	Comment struct {
+		ID        string
+		TodoID    string
+		Body      string
+		Author    string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+)
+
+// ValidateCommentBody rejects blank bodies and bodies longer than MaxCommentLength characters.
+func ValidateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" || utf8.RuneCountInString(body) > MaxCommentLength {
+		return ErrInvalidCommentBody
+	}
+	return nil
+}
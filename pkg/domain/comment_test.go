@@ -0,0 +1,25 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/domain"
+)
+
+func TestValidateCommentBody(t *testing.T) {
+	valid := []string{"ok", strings.Repeat("é", domain.MaxCommentLength)}
+	for _, body := range valid {
+		if err := domain.ValidateCommentBody(body); err != nil {
+			t.Errorf("expected %d characters to be valid, got %v", len([]rune(body)), err)
+		}
+	}
+
+	invalid := []string{"", " \n\t", strings.Repeat("a", domain.MaxCommentLength+1)}
+	for _, body := range invalid {
+		if err := domain.ValidateCommentBody(body); !errors.Is(err, domain.ErrInvalidCommentBody) {
+			t.Errorf("expected ErrInvalidCommentBody for %d characters, got %v", len(body), err)
+		}
+	}
+}
@@ -10,4 +10,66 @@ var (
 	ErrInvalidDescription = errors.New("invalid description: must be at most 500 characters")
 	ErrInvalidID          = errors.New("invalid id: must be a valid UUID")
 	ErrEmptyUpdateRequest = errors.New("update request must contain at least one field")
+	ErrInvalidLimit       = errors.New("invalid limit: must be between 1 and 100")
+	ErrInvalidCursor      = errors.New("invalid cursor: must be a next_cursor returned by a previous page")
+	ErrInvalidSort        = errors.New("invalid sort: must be a comma separated list of created_at, updated_at, due_at, title, status, priority or relevance, optionally prefixed with '-'")
+	ErrInvalidSearchQuery = errors.New("invalid search query: must be at most 200 characters")
+	ErrRelevanceSortQuery = errors.New("invalid sort: relevance can only be used together with a search query")
+	ErrInvalidDueAt       = errors.New("invalid due_at: must be an RFC 3339 timestamp between years 1970 and 9999")
+	ErrInvalidDueRange    = errors.New("invalid due range: due_after must be before due_before")
+	ErrInvalidOverdue     = errors.New("invalid overdue: must be true or false")
+	ErrInvalidTransition  = errors.New("invalid status transition")
+	ErrVersionMismatch    = errors.New("todo has been modified: version does not match If-Match")
+	ErrInvalidIfMatch     = errors.New("invalid If-Match: must be a single strong entity tag or *")
+	ErrInvalidBatchSize   = errors.New("invalid batch: must contain between 1 and 1000 operations")
+	ErrInvalidBatchMode   = errors.New("invalid batch mode: must be atomic or partial")
+	ErrInvalidBatchOp     = errors.New("invalid batch operation: op must be create, update or delete")
+	ErrDuplicateBatchID   = errors.New("invalid batch: a todo can only be targeted by one operation")
+	ErrBatchAborted       = errors.New("operation not applied: another operation in the atomic batch failed")
+	ErrInvalidParent      = errors.New("invalid parent_id: must reference an existing todo")
+	ErrParentCycle        = errors.New("invalid parent_id: a todo cannot be nested under itself or one of its subtasks")
+	ErrTodoHasChildren    = errors.New("todo has subtasks: delete it with children=cascade or children=orphan")
+	ErrParentTrashed      = errors.New("todo cannot be restored while its parent is in the trash: restore the parent first")
+	ErrInvalidChildPolicy = errors.New("invalid children policy: must be restrict, cascade or orphan")
+	ErrInvalidInclude     = errors.New("invalid include: must be children")
+	ErrInvalidTag         = errors.New("invalid tag: must be 1 to 50 letters, digits, '.', '-' or '_' starting with a letter or a digit")
+	ErrTooManyTags        = errors.New("invalid tags: a todo can have at most 20 tags")
+	ErrConflictingTags    = errors.New("invalid tags: a tag cannot be both added and removed")
+	ErrInvalidTagMatch    = errors.New("invalid tag_match: must be any or all")
+	ErrInvalidDependency  = errors.New("invalid blocker_id: must reference an existing todo")
+	ErrDependencyCycle    = errors.New("invalid blocker_id: a todo cannot depend on itself or on a todo that depends on it")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrTodoBlocked        = errors.New("todo is blocked: complete the todos it depends on before starting it")
+	ErrInvalidBlocked     = errors.New("invalid blocked: must be true or false")
+	ErrInvalidRecurrence  = errors.New("invalid recurrence: must be daily, weekly, monthly or an RRULE with FREQ=DAILY, WEEKLY or MONTHLY and optional INTERVAL, BYDAY, BYMONTHDAY, COUNT or UNTIL")
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrInvalidCommentID   = errors.New("invalid comment id: must be a valid UUID")
+	ErrInvalidCommentBody = errors.New("invalid comment body: must be between 1 and 2000 characters")
+	ErrInvalidUserID      = errors.New("invalid user id: must be 1 to 255 printable characters without spaces")
+	ErrInvalidAssignee    = errors.New("invalid assignee: must be a user id, or me when acting as a user")
+	ErrShareNotFound      = errors.New("share not found")
+)
+
+// Attachment errors.
+var (
+	ErrAttachmentNotFound        = errors.New("attachment not found")
+	ErrInvalidAttachmentID       = errors.New("invalid attachment id: must be a valid UUID")
+	ErrMissingAttachment         = errors.New("invalid upload: the file must be sent in the file field of a multipart form")
+	ErrInvalidAttachmentName     = errors.New("invalid file name: must be between 1 and 255 characters without control characters")
+	ErrAttachmentTooLarge        = errors.New("attachment too large")
+	ErrUnsupportedAttachmentType = errors.New("unsupported attachment type")
+)
+
+// Authentication and authorization errors.
+var (
+	ErrUnauthenticated   = errors.New("authentication required: sign in or use an API key")
+	ErrInvalidAPIKey     = errors.New("invalid API key")
+	ErrInsufficientScope = errors.New("API key does not grant the scope required by this route")
+	ErrUnverifiedUser    = errors.New("the declared user is not the one the request is authenticated as")
+	ErrForbidden         = errors.New("forbidden: your role does not allow this action")
+	ErrAPIKeyNotFound    = errors.New("API key not found")
+	ErrInvalidAPIKeyID   = errors.New("invalid API key id: must be a valid UUID")
+	ErrInvalidAPIKeyName = errors.New("invalid API key name: must be between 1 and 100 characters")
+	ErrInvalidAPIKeyApp  = errors.New("invalid app: must be 1 to 255 printable characters without spaces")
+	ErrInvalidScope      = errors.New("invalid scopes: must be a non empty list of distinct todos:read, todos:write or apikeys:manage")
 )
@@ -50,6 +50,18 @@ func TestErrEmptyUpdateRequest_ErrorsIs(t *testing.T) {
 	}
 }
 
+func TestErrInvalidLimit_ErrorsIs(t *testing.T) {
+	if !errors.Is(domain.ErrInvalidLimit, domain.ErrInvalidLimit) {
+		t.Error("expected ErrInvalidLimit to match itself")
+	}
+}
+
+func TestErrInvalidCursor_ErrorsIs(t *testing.T) {
+	if !errors.Is(domain.ErrInvalidCursor, domain.ErrInvalidCursor) {
+		t.Error("expected ErrInvalidCursor to match itself")
+	}
+}
+
 func TestWrappedErrTodoNotFound_IsIdentifiable(t *testing.T) {
 	wrappedErr := fmt.Errorf("service error: %w", domain.ErrTodoNotFound)
 
@@ -147,6 +159,20 @@ func TestErrEmptyUpdateRequest_Message(t *testing.T) {
 	}
 }
 
+func TestErrInvalidLimit_Message(t *testing.T) {
+	expected := "invalid limit: must be between 1 and 100"
+	if domain.ErrInvalidLimit.Error() != expected {
+		t.Errorf("expected message %s, got %s", expected, domain.ErrInvalidLimit.Error())
+	}
+}
+
+func TestErrInvalidCursor_Message(t *testing.T) {
+	expected := "invalid cursor: must be a next_cursor returned by a previous page"
+	if domain.ErrInvalidCursor.Error() != expected {
+		t.Errorf("expected message %s, got %s", expected, domain.ErrInvalidCursor.Error())
+	}
+}
+
 func TestDifferentErrors_ShouldNotMatch(t *testing.T) {
 	if errors.Is(domain.ErrTodoNotFound, domain.ErrInvalidID) {
 		t.Error("expected ErrTodoNotFound to not match ErrInvalidID")
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"reflect"
+	"time"
+)
+
+const (
+	HistoryCreate  HistoryAction = "create"
+	HistoryUpdate  HistoryAction = "update"
+	HistoryDelete  HistoryAction = "delete"
+	HistoryRestore HistoryAction = "restore"
+)
+
+type (
+	HistoryAction string
+
+	// HistoryEntry records one change made to a todo, by whom and when.
+	HistoryEntry struct {
+		ID        string
+		TodoID    string
+		Action    HistoryAction
+		Changes   []FieldChange
+		Caller    Caller
+		CreatedAt time.Time
+	}
+
+	// FieldChange is the value of a field before and after a change. Values are nil
+	// for unset fields, strings (RFC 3339 for dates) or, for tags, lists of strings.
+	FieldChange struct {
+		Field  string
+		Before any
+		After  any
+	}
+)
+
+// DiffTodos lists the fields that differ between two states of a todo, in a stable
+// order. Diffing against the zero Todo lists every field the todo has set.
+func DiffTodos(before, after Todo) []FieldChange {
+	var changes []FieldChange
+	b, a := historyFields(before), historyFields(after)
+	for i := range b {
+		if !reflect.DeepEqual(b[i].value, a[i].value) {
+			changes = append(changes, FieldChange{Field: b[i].name, Before: b[i].value, After: a[i].value})
+		}
+	}
+	return changes
+}
+
+type historyField struct {
+	name  string
+	value any
+}
+
+// historyFields returns the fields a user can change, normalised so that unset
+// fields are nil whatever their Go zero value.
+func historyFields(t Todo) []historyField {
+	var dueAt, recurrence any
+	if t.DueAt != nil {
+		dueAt = t.DueAt.UTC().Format(time.RFC3339)
+	}
+	if t.Recurrence != nil {
+		recurrence = t.Recurrence.String()
+	}
+
+	var parentID, assigneeID any
+	if t.ParentID != nil {
+		parentID = *t.ParentID
+	}
+	if t.AssigneeID != nil {
+		assigneeID = *t.AssigneeID
+	}
+
+	var tags any
+	if len(t.Tags) > 0 {
+		tags = t.Tags
+	}
+
+	return []historyField{
+		{"title", emptyToNil(t.Title)},
+		{"description", emptyToNil(t.Description)},
+		{"status", emptyToNil(string(t.Status))},
+		{"priority", emptyToNil(string(t.Priority))},
+		{"due_at", dueAt},
+		{"recurrence", recurrence},
+		{"parent_id", parentID},
+		{"assignee_id", assigneeID},
+		{"tags", tags},
+	}
+}
+
+func emptyToNil(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package domain_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"todo-api/pkg/domain"
+)
+
+func TestDiffTodos_ListsChangedFields(t *testing.T) {
+	due := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	before := domain.Todo{Title: "Old", Status: domain.StatusPending, Priority: domain.PriorityLow, Tags: []string{"q3"}}
+	after := domain.Todo{Title: "New", Status: domain.StatusPending, Priority: domain.PriorityLow, DueAt: &due}
+
+	changes := domain.DiffTodos(before, after)
+
+	expected := []domain.FieldChange{
+		{Field: "title", Before: "Old", After: "New"},
+		{Field: "due_at", Before: nil, After: "2026-02-01T09:00:00Z"},
+		{Field: "tags", Before: []string{"q3"}, After: nil},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestDiffTodos_ListsReassignment(t *testing.T) {
+	alice, bob := "alice", "bob"
+
+	changes := domain.DiffTodos(domain.Todo{AssigneeID: &alice}, domain.Todo{AssigneeID: &bob})
+
+	expected := []domain.FieldChange{{Field: "assignee_id", Before: "alice", After: "bob"}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestDiffTodos_FromZeroListsSetFields(t *testing.T) {
+	changes := domain.DiffTodos(domain.Todo{}, domain.Todo{Title: "New", Status: domain.StatusPending, Priority: domain.PriorityMedium})
+
+	if len(changes) != 3 {
+		t.Errorf("expected title, status and priority, got %+v", changes)
+	}
+}
+
+func TestDiffTodos_NoChanges(t *testing.T) {
+	todo := domain.Todo{Title: "Same", Tags: []string{"a"}}
+
+	if changes := domain.DiffTodos(todo, todo); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestCallerFromContext(t *testing.T) {
+	if caller := domain.CallerFromContext(context.Background()); caller != (domain.Caller{}) {
+		t.Errorf("expected the zero caller, got %+v", caller)
+	}
+
+	caller := domain.Caller{App: "web", Scope: "prod", User: "alice"}
+	if got := domain.CallerFromContext(domain.WithCaller(context.Background(), caller)); got != caller {
+		t.Errorf("expected %+v, got %+v", caller, got)
+	}
+}
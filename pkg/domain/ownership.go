@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxUserIDLength is the maximum number of characters in a user id.
+	MaxUserIDLength = 255
+
+	// AssigneeMe stands for the acting user wherever an assignee is expected.
+	AssigneeMe = "me"
+)
+
+// VisibleTo reports whether the user can see the todo: they own it, are assigned to
+// it or it was shared with them. Todos without an owner predate ownership and are
+// visible to everyone, as is every todo when there is no acting user.
+func (t Todo) VisibleTo(user string) bool {
+	switch {
+	case user == "", t.OwnerID == nil:
+		return true
+	case *t.OwnerID == user:
+		return true
+	case t.AssigneeID != nil && *t.AssigneeID == user:
+		return true
+	}
+	return slices.Contains(t.SharedWith, user)
+}
+
+// ValidateUserID accepts 1 to MaxUserIDLength printable characters without spaces.
+// User ids are opaque: they come from whoever authenticated the user.
+func ValidateUserID(id string) error {
+	if id == "" || utf8.RuneCountInString(id) > MaxUserIDLength || id == AssigneeMe {
+		return ErrInvalidUserID
+	}
+	for _, r := range id {
+		if !unicode.IsPrint(r) || unicode.IsSpace(r) {
+			return ErrInvalidUserID
+		}
+	}
+	return nil
+}
+
+// ValidateAssignee accepts a user id or domain.AssigneeMe.
+func ValidateAssignee(assignee string) error {
+	if assignee != AssigneeMe && ValidateUserID(assignee) != nil {
+		return ErrInvalidAssignee
+	}
+	return nil
+}
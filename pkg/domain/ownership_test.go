@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/domain"
+)
+
+func TestTodo_VisibleTo(t *testing.T) {
+	owner, assignee := "alice", "bob"
+	todo := domain.Todo{OwnerID: &owner, AssigneeID: &assignee, SharedWith: []string{"carol"}}
+
+	for _, user := range []string{"", "alice", "bob", "carol"} {
+		if !todo.VisibleTo(user) {
+			t.Errorf("expected todo to be visible to %q", user)
+		}
+	}
+	if todo.VisibleTo("dave") {
+		t.Error("expected todo not to be visible to dave")
+	}
+}
+
+func TestTodo_VisibleTo_WithoutOwner(t *testing.T) {
+	if !(domain.Todo{}).VisibleTo("dave") {
+		t.Error("expected a todo without owner to be visible to everyone")
+	}
+}
+
+func TestValidateUserID(t *testing.T) {
+	valid := []string{"alice", "auth0|5f7c8ec7c33c6c004bbafe82", "alice@example.com", strings.Repeat("é", domain.MaxUserIDLength)}
+	for _, id := range valid {
+		if err := domain.ValidateUserID(id); err != nil {
+			t.Errorf("expected %q to be valid, got %v", id, err)
+		}
+	}
+
+	invalid := []string{"", "me", "alice smith", "alice\n", strings.Repeat("a", domain.MaxUserIDLength+1)}
+	for _, id := range invalid {
+		if err := domain.ValidateUserID(id); !errors.Is(err, domain.ErrInvalidUserID) {
+			t.Errorf("expected ErrInvalidUserID for %q, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package domain
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+
+	// MaxRecurrenceLength bounds the textual form of a rule, as stored with the todo.
+	MaxRecurrenceLength = 255
+
+	maxInterval = 999
+	untilLayout = "20060102T150405Z"
+)
+
+type (
+	Frequency string
+
+	// Recurrence is a repeat rule following a subset of RFC 5545 RRULEs: FREQ is DAILY,
+	// WEEKLY or MONTHLY, and INTERVAL, BYDAY (weekdays only, with DAILY or WEEKLY),
+	// BYMONTHDAY (with MONTHLY, negative days counting from the end of the month),
+	// COUNT and UNTIL are supported. Weeks start on Monday.
+	//
+	// Occurrences are computed from the previous one, whose time of day they keep. Count,
+	// when set, is the number of occurrences left including the current one.
+	Recurrence struct {
+		Freq       Frequency
+		Interval   int
+		ByDay      []time.Weekday
+		ByMonthDay []int
+		Count      int
+		Until      *time.Time
+	}
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRecurrence parses either one of the daily, weekly or monthly shorthands or an
+// RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", optionally prefixed with "RRULE:".
+func ParseRecurrence(s string) (Recurrence, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || len(s) > MaxRecurrenceLength {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+
+	switch strings.ToLower(s) {
+	case "daily":
+		return Recurrence{Freq: FreqDaily, Interval: 1}, nil
+	case "weekly":
+		return Recurrence{Freq: FreqWeekly, Interval: 1}, nil
+	case "monthly":
+		return Recurrence{Freq: FreqMonthly, Interval: 1}, nil
+	}
+
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+
+	r := Recurrence{Interval: 1}
+	seen := make(map[string]struct{})
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+		if _, dup := seen[name]; dup {
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+		seen[name] = struct{}{}
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq = Frequency(value)
+		case "INTERVAL":
+			r.Interval, err = parseRuleInt(value, 1, maxInterval)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		case "COUNT":
+			r.Count, err = parseRuleInt(value, 1, maxInterval)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value)
+			r.Until = &until
+		default:
+			err = ErrInvalidRecurrence
+		}
+		if err != nil {
+			return Recurrence{}, err
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return Recurrence{}, err
+	}
+
+	return r, nil
+}
+
+func (r Recurrence) validate() error {
+	switch r.Freq {
+	case FreqDaily, FreqWeekly:
+		if len(r.ByMonthDay) > 0 {
+			return ErrInvalidRecurrence
+		}
+	case FreqMonthly:
+		if len(r.ByDay) > 0 {
+			return ErrInvalidRecurrence
+		}
+	default:
+		return ErrInvalidRecurrence
+	}
+
+	// RFC 5545 forbids bounding a rule by both COUNT and UNTIL
+	if r.Count > 0 && r.Until != nil {
+		return ErrInvalidRecurrence
+	}
+
+	return nil
+}
+
+// String returns the rule as an RRULE, without the "RRULE:" prefix. Parsing it back
+// yields the same rule.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = strings.ToUpper(d.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence following the one at t. It reports false once the rule is
+// exhausted, i.e. when t is the last occurrence allowed by Count or the next one would
+// fall after Until.
+func (r Recurrence) Next(t time.Time) (time.Time, bool) {
+	if r.Count == 1 {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	var ok bool
+	switch r.Freq {
+	case FreqDaily:
+		next, ok = r.nextDaily(t)
+	case FreqWeekly:
+		next, ok = r.nextWeekly(t), true
+	case FreqMonthly:
+		next, ok = r.nextMonthly(t)
+	}
+
+	if !ok || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Advance returns the rule carried by the next occurrence, which has one occurrence
+// less left when Count is set.
+func (r Recurrence) Advance() Recurrence {
+	if r.Count > 1 {
+		r.Count--
+	}
+	return r
+}
+
+// Occurrences returns up to n occurrences following the one at t, in order.
+func (r Recurrence) Occurrences(t time.Time, n int) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+	for len(occurrences) < n {
+		next, ok := r.Next(t)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		r, t = r.Advance(), next
+	}
+	return occurrences
+}
+
+// nextDaily steps Interval days at a time until it lands on one of ByDay. Stepping
+// cycles through the weekdays within a week of steps, hence the bound.
+func (r Recurrence) nextDaily(t time.Time) (time.Time, bool) {
+	for range 7 {
+		t = t.AddDate(0, 0, r.Interval)
+		if len(r.ByDay) == 0 || slices.Contains(r.ByDay, t.Weekday()) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextWeekly picks the next of ByDay later in the week of t, or the first of ByDay in the
+// week Interval weeks later.
+func (r Recurrence) nextWeekly(t time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return t.AddDate(0, 0, 7*r.Interval)
+	}
+
+	offsets := make([]int, len(r.ByDay))
+	for i, d := range r.ByDay {
+		offsets[i] = weekOffset(d)
+	}
+	slices.Sort(offsets)
+
+	current := weekOffset(t.Weekday())
+	for _, offset := range offsets {
+		if offset > current {
+			return t.AddDate(0, 0, offset-current)
+		}
+	}
+
+	return t.AddDate(0, 0, 7*r.Interval-current+offsets[0])
+}
+
+// nextMonthly picks the next of ByMonthDay (or the day of t) later in the month of t, or
+// the first of them in the months Interval months apart. Days a month does not have are
+// skipped, as RFC 5545 requires, so a rule on the 31st only fires in long months.
+func (r Recurrence) nextMonthly(t time.Time) (time.Time, bool) {
+	days := r.ByMonthDay
+	if len(days) == 0 {
+		days = []int{t.Day()}
+	}
+
+	year, month, _ := t.Date()
+	// 48 steps reach a February 29th from any month, the rarest day a rule can target
+	for step := 0; step <= 48; step++ {
+		first := time.Date(year, month+time.Month(step*r.Interval), 1, 0, 0, 0, 0, t.Location())
+		length := first.AddDate(0, 1, -1).Day()
+
+		var candidates []int
+		for _, d := range days {
+			if d < 0 {
+				d = length + d + 1
+			}
+			if d >= 1 && d <= length {
+				candidates = append(candidates, d)
+			}
+		}
+		slices.Sort(candidates)
+
+		for _, d := range candidates {
+			next := time.Date(first.Year(), first.Month(), d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+			if next.After(t) {
+				return next, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// weekOffset returns the position of the weekday in a week starting on Monday.
+func weekOffset(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+func parseRuleInt(s string, min, max int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, ErrInvalidRecurrence
+	}
+	return n, nil
+}
+
+func parseByDay(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, code := range strings.Split(s, ",") {
+		d, ok := weekdayCodes[code]
+		if !ok {
+			return nil, ErrInvalidRecurrence
+		}
+		if !slices.Contains(days, d) {
+			days = append(days, d)
+		}
+	}
+	return days, nil
+}
+
+func parseByMonthDay(s string) ([]int, error) {
+	var days []int
+	for _, v := range strings.Split(s, ",") {
+		d, err := parseRuleInt(v, -31, 31)
+		if err != nil || d == 0 {
+			return nil, ErrInvalidRecurrence
+		}
+		if !slices.Contains(days, d) {
+			days = append(days, d)
+		}
+	}
+	return days, nil
+}
+
+// parseUntil accepts the UTC date-time and the date forms of UNTIL. A date bounds the
+// rule to the end of that day.
+func parseUntil(s string) (time.Time, error) {
+	if t, err := time.Parse(untilLayout, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", s)
+	if err != nil {
+		return time.Time{}, ErrInvalidRecurrence
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"todo-api/pkg/domain"
+)
+
+func mustParseRecurrence(t *testing.T, s string) domain.Recurrence {
+	t.Helper()
+	r, err := domain.ParseRecurrence(s)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", s, err)
+	}
+	return r
+}
+
+func TestParseRecurrence_Shorthands(t *testing.T) {
+	cases := map[string]string{
+		"daily":   "FREQ=DAILY",
+		"Weekly":  "FREQ=WEEKLY",
+		"MONTHLY": "FREQ=MONTHLY",
+	}
+	for input, expected := range cases {
+		if got := mustParseRecurrence(t, input).String(); got != expected {
+			t.Errorf("expected %q to parse as %s, got %s", input, expected, got)
+		}
+	}
+}
+
+func TestParseRecurrence_RoundTrips(t *testing.T) {
+	for _, rule := range []string{
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+		"FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=6",
+		"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20260301T000000Z",
+	} {
+		if got := mustParseRecurrence(t, "RRULE:"+rule).String(); got != rule {
+			t.Errorf("expected %s, got %s", rule, got)
+		}
+	}
+}
+
+func TestParseRecurrence_RejectsInvalidRules(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"hourly",
+		"FREQ=YEARLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260301",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;WKST=SU",
+	} {
+		if _, err := domain.ParseRecurrence(rule); !errors.Is(err, domain.ErrInvalidRecurrence) {
+			t.Errorf("expected ErrInvalidRecurrence for %q, got %v", rule, err)
+		}
+	}
+}
+
+func TestRecurrence_Occurrences(t *testing.T) {
+	// Wednesday
+	start := time.Date(2026, 1, 28, 9, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 9, 0, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		rule     string
+		expected []time.Time
+	}{
+		{"daily", []time.Time{day(1, 29), day(1, 30), day(1, 31)}},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", []time.Time{day(1, 29), day(1, 30), day(2, 2)}},
+		{"FREQ=WEEKLY;INTERVAL=2", []time.Time{day(2, 11), day(2, 25), day(3, 11)}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", []time.Time{day(1, 29), day(2, 9), day(2, 12)}},
+		{"monthly", []time.Time{day(2, 28), day(3, 28), day(4, 28)}},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", []time.Time{day(1, 31), day(2, 28), day(3, 31)}},
+		{"FREQ=MONTHLY;BYMONTHDAY=30", []time.Time{day(1, 30), day(3, 30), day(4, 30)}},
+		{"FREQ=DAILY;COUNT=3", []time.Time{day(1, 29), day(1, 30)}},
+		{"FREQ=WEEKLY;UNTIL=20260211", []time.Time{day(2, 4), day(2, 11)}},
+	}
+	for _, tc := range cases {
+		got := mustParseRecurrence(t, tc.rule).Occurrences(start, 3)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.rule, tc.expected, got)
+		}
+	}
+}
+
+func TestRecurrence_Advance_DecrementsCount(t *testing.T) {
+	r := mustParseRecurrence(t, "FREQ=DAILY;COUNT=2").Advance()
+
+	if r.Count != 1 {
+		t.Errorf("expected 1 occurrence left, got %d", r.Count)
+	}
+	if _, ok := r.Next(time.Now()); ok {
+		t.Error("expected the last occurrence to have no next one")
+	}
+}
@@ -0,0 +1,100 @@
+package domain
+
+import "strings"
+
+const (
+	SortCreatedAt SortField = "created_at"
+	SortUpdatedAt SortField = "updated_at"
+	SortDueAt     SortField = "due_at"
+	SortTitle     SortField = "title"
+	SortStatus    SortField = "status"
+	SortPriority  SortField = "priority"
+	SortRelevance SortField = "relevance"
+
+	// SortDeletedAt orders the trash. It is not a client selectable field.
+	SortDeletedAt SortField = "deleted_at"
+)
+
+type (
+	// SortField is a todo attribute listings can be ordered by.
+	SortField string
+
+	// SortOrder is a single sort key. Desc reverses the natural order of the field.
+	SortOrder struct {
+		Field SortField
+		Desc  bool
+	}
+)
+
+var (
+	// DefaultSort is applied to listings when no explicit sort is requested.
+	DefaultSort = []SortOrder{{Field: SortCreatedAt, Desc: true}}
+
+	// RelevanceSort is applied instead of DefaultSort to full-text searches.
+	RelevanceSort = []SortOrder{{Field: SortRelevance, Desc: true}}
+
+	// TrashSort lists soft-deleted todos, most recently deleted first.
+	TrashSort = []SortOrder{{Field: SortDeletedAt, Desc: true}}
+)
+
+func (f SortField) IsValid() bool {
+	switch f {
+	case SortCreatedAt, SortUpdatedAt, SortDueAt, SortTitle, SortStatus, SortPriority, SortRelevance:
+		return true
+	}
+	return false
+}
+
+// ParseSort parses a comma separated list of sort fields where a leading '-'
+// requests descending order, e.g. "-priority,updated_at".
+func ParseSort(s string) ([]SortOrder, error) {
+	parts := strings.Split(s, ",")
+	orders := make([]SortOrder, 0, len(parts))
+	seen := make(map[SortField]struct{}, len(parts))
+
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+
+		var order SortOrder
+		if strings.HasPrefix(p, "-") {
+			order.Desc = true
+			p = p[1:]
+		}
+		order.Field = SortField(p)
+
+		if !order.Field.IsValid() {
+			return nil, ErrInvalidSort
+		}
+		if _, ok := seen[order.Field]; ok {
+			return nil, ErrInvalidSort
+		}
+		seen[order.Field] = struct{}{}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// HasField reports whether the field is one of the sort keys.
+func HasField(orders []SortOrder, f SortField) bool {
+	for _, o := range orders {
+		if o.Field == f {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatSort is the inverse of ParseSort.
+func FormatSort(orders []SortOrder) string {
+	parts := make([]string, len(orders))
+	for i, o := range orders {
+		if o.Desc {
+			parts[i] = "-" + string(o.Field)
+		} else {
+			parts[i] = string(o.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,81 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"todo-api/pkg/domain"
+)
+
+func TestParseSort_SingleAscending(t *testing.T) {
+	orders, err := domain.ParseSort("title")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(orders) != 1 || orders[0].Field != domain.SortTitle || orders[0].Desc {
+		t.Errorf("expected ascending title, got %v", orders)
+	}
+}
+
+func TestParseSort_MultipleWithDirections(t *testing.T) {
+	orders, err := domain.ParseSort("-priority, updated_at")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 sort keys, got %d", len(orders))
+	}
+	if orders[0].Field != domain.SortPriority || !orders[0].Desc {
+		t.Errorf("expected descending priority, got %v", orders[0])
+	}
+	if orders[1].Field != domain.SortUpdatedAt || orders[1].Desc {
+		t.Errorf("expected ascending updated_at, got %v", orders[1])
+	}
+}
+
+func TestParseSort_UnknownField(t *testing.T) {
+	_, err := domain.ParseSort("description")
+	if !errors.Is(err, domain.ErrInvalidSort) {
+		t.Errorf("expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestParseSort_Empty(t *testing.T) {
+	_, err := domain.ParseSort("")
+	if !errors.Is(err, domain.ErrInvalidSort) {
+		t.Errorf("expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestParseSort_DuplicateField(t *testing.T) {
+	_, err := domain.ParseSort("title,-title")
+	if !errors.Is(err, domain.ErrInvalidSort) {
+		t.Errorf("expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestFormatSort_RoundTrips(t *testing.T) {
+	expected := "-priority,updated_at"
+	orders, err := domain.ParseSort(expected)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := domain.FormatSort(orders); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestPriority_Rank_IsSemantic(t *testing.T) {
+	if !(domain.PriorityHigh.Rank() > domain.PriorityMedium.Rank() && domain.PriorityMedium.Rank() > domain.PriorityLow.Rank()) {
+		t.Error("expected high > medium > low")
+	}
+}
+
+func TestStatus_Rank_FollowsLifecycle(t *testing.T) {
+	if !(domain.StatusCompleted.Rank() > domain.StatusInProgress.Rank() && domain.StatusInProgress.Rank() > domain.StatusPending.Rank()) {
+		t.Error("expected completed > in_progress > pending")
+	}
+}
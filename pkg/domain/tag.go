@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaxTags is the number of tags a single todo can carry.
+const MaxTags = 20
+
+const (
+	// TagMatchAny selects the todos carrying at least one of the requested tags.
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll selects the todos carrying every requested tag.
+	TagMatchAll TagMatch = "all"
+)
+
+type (
+	// TagMatch decides how a tag filter with several tags is combined.
+	TagMatch string
+
+	// TagUsage is a tag together with the number of live todos carrying it.
+	TagUsage struct {
+		Name  string
+		Count int
+	}
+)
+
+func (m TagMatch) IsValid() bool {
+	switch m {
+	case TagMatchAny, TagMatchAll:
+		return true
+	}
+	return false
+}
+
+var tagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,49}$`)
+
+// NormalizeTags lowercases and trims the tags, then checks that each one is 1 to 50
+// letters, digits, '.', '-' or '_' starting with a letter or a digit. The result is
+// sorted and free of duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagRegex.MatchString(tag) {
+			return nil, ErrInvalidTag
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// ApplyTags returns the sorted tags obtained by adding add to current and then removing remove.
+func ApplyTags(current, add, remove []string) []string {
+	set := make(map[string]struct{}, len(current)+len(add))
+	for _, tag := range current {
+		set[tag] = struct{}{}
+	}
+	for _, tag := range add {
+		set[tag] = struct{}{}
+	}
+	for _, tag := range remove {
+		delete(set, tag)
+	}
+
+	tags := make([]string, 0, len(set))
+	for tag := range set {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,48 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/domain"
+)
+
+func TestNormalizeTags_LowercasesSortsAndDeduplicates(t *testing.T) {
+	tags, err := domain.NormalizeTags([]string{" Backend", "q3", "backend", "billing.v2"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"backend", "billing.v2", "q3"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+}
+
+func TestNormalizeTags_RejectsInvalidTags(t *testing.T) {
+	for _, tag := range []string{"", "  ", "-backend", "back end", "bäckend", strings.Repeat("a", 51)} {
+		if _, err := domain.NormalizeTags([]string{tag}); !errors.Is(err, domain.ErrInvalidTag) {
+			t.Errorf("expected ErrInvalidTag for %q, got %v", tag, err)
+		}
+	}
+}
+
+func TestApplyTags(t *testing.T) {
+	tags := domain.ApplyTags([]string{"backend", "q3"}, []string{"billing", "backend"}, []string{"q3", "unknown"})
+
+	expected := []string{"backend", "billing"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+}
+
+func TestTagMatch_IsValid(t *testing.T) {
+	if !domain.TagMatchAny.IsValid() || !domain.TagMatchAll.IsValid() {
+		t.Error("expected any and all to be valid")
+	}
+	if domain.TagMatch("some").IsValid() {
+		t.Error("expected some to be invalid")
+	}
+}
@@ -13,6 +13,13 @@ const (
 	PriorityLow    Priority = "low"
 	PriorityMedium Priority = "medium"
 	PriorityHigh   Priority = "high"
+
+	// ChildrenRestrict refuses to delete a todo that still has subtasks.
+	ChildrenRestrict ChildPolicy = "restrict"
+	// ChildrenCascade moves every subtask, recursively, to the trash along with the todo.
+	ChildrenCascade ChildPolicy = "cascade"
+	// ChildrenOrphan detaches the subtasks, which become top-level todos.
+	ChildrenOrphan ChildPolicy = "orphan"
 )
 
 type (
@@ -20,14 +27,60 @@ type (
 
 	Priority string
 
+	// ChildPolicy decides what happens to the subtasks of a deleted todo.
+	ChildPolicy string
+
 	Todo struct {
-		ID          string
+		ID string
+		// ParentID is set when the todo is a subtask of another todo.
+		ParentID    *string
 		Title       string
 		Description string
 		Status      Status
 		Priority    Priority
-		CreatedAt   time.Time
-		UpdatedAt   time.Time
+		DueAt       *time.Time
+		// Recurrence is set when completing the todo spawns its next occurrence.
+		Recurrence *Recurrence
+		// OwnerID is the user who created the todo. It is nil for todos created
+		// before ownership existed, which everyone can see.
+		OwnerID    *string
+		AssigneeID *string
+		// SharedWith is only set when the todo was read together with its shares.
+		SharedWith  []string
+		CompletedAt *time.Time
+		// DeletedAt is set while the todo sits in the trash.
+		DeletedAt *time.Time
+		Version   int
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		// Tags is sorted and never holds duplicates.
+		Tags []string
+
+		// Match is only set when the todo was found through a full-text search.
+		Match *SearchMatch
+		// Subtasks is only set when the todo was read together with its subtask counts.
+		Subtasks *Subtasks
+		// Dependencies is only set when the todo was read together with its dependency counts.
+		Dependencies *Dependencies
+	}
+
+	// Subtasks counts the live direct children of a todo.
+	Subtasks struct {
+		Total     int
+		Completed int
+	}
+
+	// Dependencies counts the live todos a todo is blocked by. Open ones are not completed yet.
+	Dependencies struct {
+		Total int
+		Open  int
+	}
+
+	// SearchMatch describes how a todo matched a full-text search query.
+	SearchMatch struct {
+		Rank                 float64
+		TitleHighlight       string
+		DescriptionHighlight string
 	}
 )
 
@@ -47,6 +100,80 @@ func (p Priority) IsValid() bool {
 	return false
 }
 
+// Rank returns the semantic order of a status along its lifecycle (pending < in_progress < completed).
+func (s Status) Rank() int {
+	switch s {
+	case StatusPending:
+		return 1
+	case StatusInProgress:
+		return 2
+	case StatusCompleted:
+		return 3
+	}
+	return 0
+}
+
+// Rank returns the semantic order of a priority (low < medium < high).
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityLow:
+		return 1
+	case PriorityMedium:
+		return 2
+	case PriorityHigh:
+		return 3
+	}
+	return 0
+}
+
+func (p ChildPolicy) IsValid() bool {
+	switch p {
+	case ChildrenRestrict, ChildrenCascade, ChildrenOrphan:
+		return true
+	}
+	return false
+}
+
+// Progress returns the percentage of completed subtasks, rounded down. A todo
+// without subtasks has no progress to report and yields 0.
+func (s Subtasks) Progress() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Completed * 100 / s.Total
+}
+
+// Blocked reports whether some of the dependencies are not completed yet.
+func (d Dependencies) Blocked() bool {
+	return d.Open > 0
+}
+
+// IsOverdue reports whether the todo is still open past its due date.
+func (t Todo) IsOverdue(now time.Time) bool {
+	return t.DueAt != nil && t.Status != StatusCompleted && t.DueAt.Before(now)
+}
+
+var (
+	minDueAt = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxDueAt = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// ParseDueAt parses an RFC 3339 due date, rejecting calendar-impossible values
+// (e.g. February 30th) and dates outside of years 1970 to 9999. The result is in UTC.
+func ParseDueAt(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidDueAt
+	}
+
+	t = t.UTC()
+	if t.Before(minDueAt) || !t.Before(maxDueAt) {
+		return time.Time{}, ErrInvalidDueAt
+	}
+
+	return t, nil
+}
+
 var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
 
 func ValidateUUID(id string) error {
@@ -3,6 +3,7 @@ package domain_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"todo-api/pkg/domain"
 )
@@ -136,3 +137,93 @@ func TestValidateUUID_Partial(t *testing.T) {
 		t.Errorf("expected ErrInvalidID, got %v", err)
 	}
 }
+
+func TestParseDueAt_Valid(t *testing.T) {
+	dueAt, err := domain.ParseDueAt("2026-03-01T12:00:00-03:00")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)
+	if !dueAt.Equal(expected) || dueAt.Location() != time.UTC {
+		t.Errorf("expected %v in UTC, got %v", expected, dueAt)
+	}
+}
+
+func TestParseDueAt_ImpossibleDate(t *testing.T) {
+	_, err := domain.ParseDueAt("2026-02-30T12:00:00Z")
+	if !errors.Is(err, domain.ErrInvalidDueAt) {
+		t.Errorf("expected ErrInvalidDueAt, got %v", err)
+	}
+}
+
+func TestParseDueAt_NotRFC3339(t *testing.T) {
+	_, err := domain.ParseDueAt("03/01/2026")
+	if !errors.Is(err, domain.ErrInvalidDueAt) {
+		t.Errorf("expected ErrInvalidDueAt, got %v", err)
+	}
+}
+
+func TestParseDueAt_BeforeEpoch(t *testing.T) {
+	_, err := domain.ParseDueAt("1969-12-31T23:59:59Z")
+	if !errors.Is(err, domain.ErrInvalidDueAt) {
+		t.Errorf("expected ErrInvalidDueAt, got %v", err)
+	}
+}
+
+func TestTodo_IsOverdue(t *testing.T) {
+	now := time.Date(2026, 1, 28, 10, 30, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	if !(domain.Todo{Status: domain.StatusPending, DueAt: &past}).IsOverdue(now) {
+		t.Error("expected open todo past its due date to be overdue")
+	}
+	if (domain.Todo{Status: domain.StatusCompleted, DueAt: &past}).IsOverdue(now) {
+		t.Error("expected completed todo not to be overdue")
+	}
+	if (domain.Todo{Status: domain.StatusPending, DueAt: &future}).IsOverdue(now) {
+		t.Error("expected todo due in the future not to be overdue")
+	}
+	if (domain.Todo{Status: domain.StatusPending}).IsOverdue(now) {
+		t.Error("expected todo without due date not to be overdue")
+	}
+}
+
+func TestSubtasks_Progress(t *testing.T) {
+	cases := []struct {
+		subtasks domain.Subtasks
+		expected int
+	}{
+		{domain.Subtasks{}, 0},
+		{domain.Subtasks{Total: 4, Completed: 1}, 25},
+		{domain.Subtasks{Total: 3, Completed: 2}, 66},
+		{domain.Subtasks{Total: 2, Completed: 2}, 100},
+	}
+
+	for _, c := range cases {
+		if got := c.subtasks.Progress(); got != c.expected {
+			t.Errorf("expected progress %d for %+v, got %d", c.expected, c.subtasks, got)
+		}
+	}
+}
+
+func TestChildPolicy_IsValid(t *testing.T) {
+	for _, p := range []domain.ChildPolicy{domain.ChildrenRestrict, domain.ChildrenCascade, domain.ChildrenOrphan} {
+		if !p.IsValid() {
+			t.Errorf("expected %s to be valid", p)
+		}
+	}
+	if domain.ChildPolicy("delete").IsValid() {
+		t.Error("expected unknown policy to be invalid")
+	}
+}
+
+func TestDependencies_Blocked(t *testing.T) {
+	if (domain.Dependencies{Total: 2}).Blocked() {
+		t.Error("expected completed dependencies not to block")
+	}
+	if !(domain.Dependencies{Total: 2, Open: 1}).Blocked() {
+		t.Error("expected an open dependency to block")
+	}
+}
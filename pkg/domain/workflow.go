@@ -0,0 +1,65 @@
+package domain
+
+import "fmt"
+
+type (
+	// Workflow is the state machine todos follow. It lists, for every status, the
+	// statuses a todo in that status may move to. Staying in the same status is
+	// always allowed.
+	Workflow struct {
+		transitions map[Status]map[Status]struct{}
+	}
+)
+
+// DefaultWorkflow lets open todos move freely between pending and in_progress and be
+// completed from either. A completed todo can only be reopened back into in_progress.
+var DefaultWorkflow = MustNewWorkflow(map[Status][]Status{
+	StatusPending:    {StatusInProgress, StatusCompleted},
+	StatusInProgress: {StatusPending, StatusCompleted},
+	StatusCompleted:  {StatusInProgress},
+})
+
+// NewWorkflow builds a Workflow from an adjacency list of allowed transitions.
+// Every status used, either as source or target, must be valid.
+func NewWorkflow(transitions map[Status][]Status) (Workflow, error) {
+	w := Workflow{transitions: make(map[Status]map[Status]struct{}, len(transitions))}
+	for from, tos := range transitions {
+		if !from.IsValid() {
+			return Workflow{}, ErrInvalidStatus
+		}
+		w.transitions[from] = make(map[Status]struct{}, len(tos))
+		for _, to := range tos {
+			if !to.IsValid() {
+				return Workflow{}, ErrInvalidStatus
+			}
+			w.transitions[from][to] = struct{}{}
+		}
+	}
+	return w, nil
+}
+
+// MustNewWorkflow is like NewWorkflow but panics on an invalid definition.
+func MustNewWorkflow(transitions map[Status][]Status) Workflow {
+	w, err := NewWorkflow(transitions)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// CanTransition reports whether a todo may move from one status to another.
+func (w Workflow) CanTransition(from, to Status) bool {
+	if from == to {
+		return true
+	}
+	_, ok := w.transitions[from][to]
+	return ok
+}
+
+// Transition returns an error wrapping ErrInvalidTransition when the move is not allowed.
+func (w Workflow) Transition(from, to Status) error {
+	if !w.CanTransition(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
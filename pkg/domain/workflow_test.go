@@ -0,0 +1,60 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"todo-api/pkg/domain"
+)
+
+func TestDefaultWorkflow_AllowsStartingWork(t *testing.T) {
+	if !domain.DefaultWorkflow.CanTransition(domain.StatusPending, domain.StatusInProgress) {
+		t.Error("expected pending to in_progress to be allowed")
+	}
+}
+
+func TestDefaultWorkflow_AllowsCompletingFromAnyOpenStatus(t *testing.T) {
+	if !domain.DefaultWorkflow.CanTransition(domain.StatusPending, domain.StatusCompleted) {
+		t.Error("expected pending to completed to be allowed")
+	}
+	if !domain.DefaultWorkflow.CanTransition(domain.StatusInProgress, domain.StatusCompleted) {
+		t.Error("expected in_progress to completed to be allowed")
+	}
+}
+
+func TestDefaultWorkflow_AllowsReopening(t *testing.T) {
+	if !domain.DefaultWorkflow.CanTransition(domain.StatusCompleted, domain.StatusInProgress) {
+		t.Error("expected completed to in_progress to be allowed")
+	}
+}
+
+func TestDefaultWorkflow_RejectsCompletedToPending(t *testing.T) {
+	err := domain.DefaultWorkflow.Transition(domain.StatusCompleted, domain.StatusPending)
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestWorkflow_SameStatusIsAlwaysAllowed(t *testing.T) {
+	w := domain.MustNewWorkflow(map[domain.Status][]domain.Status{})
+	if !w.CanTransition(domain.StatusCompleted, domain.StatusCompleted) {
+		t.Error("expected staying in the same status to be allowed")
+	}
+}
+
+func TestNewWorkflow_RejectsUnknownStatus(t *testing.T) {
+	_, err := domain.NewWorkflow(map[domain.Status][]domain.Status{
+		domain.StatusPending: {domain.Status("archived")},
+	})
+	if !errors.Is(err, domain.ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestWorkflow_TransitionErrorNamesStatuses(t *testing.T) {
+	err := domain.DefaultWorkflow.Transition(domain.StatusCompleted, domain.StatusPending)
+	expected := "invalid status transition: completed to pending"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected message %s, got %v", expected, err)
+	}
+}
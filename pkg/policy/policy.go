@@ -0,0 +1,181 @@
+// Package policy decides what the users of the API are allowed to do, based on the role a
+// policy file assigns them.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"todo-api/pkg/domain"
+)
+
+// Actions a role can be allowed to perform.
+const (
+	ActionRead       Action = "todos:read"
+	ActionCreate     Action = "todos:create"
+	ActionUpdate     Action = "todos:update"
+	ActionDelete     Action = "todos:delete"
+	ActionPurge      Action = "todos:purge"
+	ActionShare      Action = "todos:share"
+	ActionComment    Action = "todos:comment"
+	ActionAttach     Action = "todos:attach"
+	ActionManageKeys Action = "apikeys:manage"
+)
+
+// Roles of the default policy.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// Actions lists every action a role can be allowed to perform.
+var Actions = []Action{
+	ActionRead,
+	ActionCreate,
+	ActionUpdate,
+	ActionDelete,
+	ActionPurge,
+	ActionShare,
+	ActionComment,
+	ActionAttach,
+	ActionManageKeys,
+}
+
+type (
+	Action string
+
+	// Config is the content of a policy file. Roles maps each role to the actions it
+	// allows, where "*" stands for every action and "todos:*" for every action on todos.
+	// Users maps user ids to their role; the users it does not name get DefaultRole, or
+	// are denied everything when it is empty.
+	Config struct {
+		DefaultRole string              `yaml:"default_role"`
+		Roles       map[string][]string `yaml:"roles"`
+		Users       map[string]string   `yaml:"users"`
+	}
+
+	// Policy authorizes the actions of users. A nil Policy allows everything, which is
+	// how the API behaves when no policy is configured.
+	Policy struct {
+		defaultRole string
+		roles       map[string][]string
+		users       map[string]string
+	}
+)
+
+// Default holds the roles a policy file gets when it declares none: admins can do
+// everything, members can do everything but purge the trash and manage API keys, and
+// viewers can only read. Users not named by the file are members.
+func Default() Config {
+	return Config{
+		DefaultRole: RoleMember,
+		Roles: map[string][]string{
+			RoleAdmin: {"*"},
+			RoleMember: {
+				string(ActionRead),
+				string(ActionCreate),
+				string(ActionUpdate),
+				string(ActionDelete),
+				string(ActionShare),
+				string(ActionComment),
+				string(ActionAttach),
+			},
+			RoleViewer: {string(ActionRead)},
+		},
+	}
+}
+
+// New checks that the config only refers to known actions and declared roles.
+func New(config Config) (*Policy, error) {
+	for role, actions := range config.Roles {
+		for _, action := range actions {
+			if !validPattern(action) {
+				return nil, fmt.Errorf("invalid policy: role %q allows unknown action %q", role, action)
+			}
+		}
+	}
+	if _, ok := config.Roles[config.DefaultRole]; config.DefaultRole != "" && !ok {
+		return nil, fmt.Errorf("invalid policy: default role %q is not declared", config.DefaultRole)
+	}
+	for user, role := range config.Users {
+		if _, ok := config.Roles[role]; !ok {
+			return nil, fmt.Errorf("invalid policy: user %q has undeclared role %q", user, role)
+		}
+	}
+
+	return &Policy{
+		defaultRole: config.DefaultRole,
+		roles:       config.Roles,
+		users:       config.Users,
+	}, nil
+}
+
+// Load reads a YAML policy file. A file without roles gets those of Default.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse reads a YAML policy. A policy without roles gets those of Default.
+func Parse(data []byte) (*Policy, error) {
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	if len(config.Roles) == 0 {
+		config.Roles = Default().Roles
+	}
+	return New(config)
+}
+
+// RoleOf returns the role of the user, or an empty string when it has none.
+func (p *Policy) RoleOf(user string) string {
+	if role, ok := p.users[user]; ok {
+		return role
+	}
+	return p.defaultRole
+}
+
+// Allows reports whether the role of the user allows the action.
+func (p *Policy) Allows(user string, action Action) bool {
+	return slices.ContainsFunc(p.roles[p.RoleOf(user)], func(pattern string) bool {
+		return matches(pattern, action)
+	})
+}
+
+// Authorize returns domain.ErrForbidden when the policy does not allow the user acting in
+// ctx to perform the action. Callers without a user, such as the clients authenticated by
+// an API key, are only restricted by their scopes and always pass.
+func (p *Policy) Authorize(ctx context.Context, action Action) error {
+	if p == nil {
+		return nil
+	}
+
+	user := domain.CallerFromContext(ctx).User
+	if user == "" || p.Allows(user, action) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+func matches(pattern string, action Action) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(string(action), prefix)
+	}
+	return pattern == string(action)
+}
+
+func validPattern(pattern string) bool {
+	return slices.ContainsFunc(Actions, func(action Action) bool {
+		return matches(pattern, action)
+	})
+}
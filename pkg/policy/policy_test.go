@@ -0,0 +1,137 @@
+package policy_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+)
+
+const policyFile = `
+default_role: viewer
+roles:
+  admin: ["*"]
+  member: ["todos:*"]
+  viewer: [todos:read]
+users:
+  alice: admin
+  bob: member
+`
+
+func asUser(user string) context.Context {
+	return domain.WithCaller(context.Background(), domain.Caller{User: user})
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	if err := os.WriteFile(path, []byte(policyFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := policy.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		user    string
+		action  policy.Action
+		allowed bool
+	}{
+		{"alice", policy.ActionManageKeys, true},
+		{"alice", policy.ActionPurge, true},
+		{"bob", policy.ActionPurge, true},
+		{"bob", policy.ActionManageKeys, false},
+		{"carol", policy.ActionRead, true},
+		{"carol", policy.ActionCreate, false},
+	}
+	for _, tt := range tests {
+		if got := p.Allows(tt.user, tt.action); got != tt.allowed {
+			t.Errorf("expected %s allowed to %s to be %v, got %v", tt.user, tt.action, tt.allowed, got)
+		}
+	}
+}
+
+func TestParse_DefaultRoles(t *testing.T) {
+	p, err := policy.Parse([]byte("default_role: member\nusers:\n  alice: admin\n  victor: viewer\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !p.Allows("alice", policy.ActionPurge) {
+		t.Error("expected admins to purge the trash")
+	}
+	if !p.Allows("bob", policy.ActionShare) || p.Allows("bob", policy.ActionPurge) {
+		t.Error("expected members to share but not to purge the trash")
+	}
+	if p.Allows("victor", policy.ActionUpdate) {
+		t.Error("expected viewers not to update todos")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not yaml":             "roles: [",
+		"unknown action":       "roles:\n  admin: [todos:fly]\n",
+		"unknown wildcard":     "roles:\n  admin: [\"users:*\"]\n",
+		"undeclared default":   "default_role: owner\nroles:\n  admin: [\"*\"]\n",
+		"undeclared user role": "roles:\n  admin: [\"*\"]\nusers:\n  alice: owner\n",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := policy.Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), "invalid policy") {
+				t.Errorf("expected an invalid policy error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPolicy_RoleOf(t *testing.T) {
+	p, err := policy.New(policy.Config{
+		Roles: map[string][]string{"admin": {"*"}},
+		Users: map[string]string{"alice": "admin"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if role := p.RoleOf("alice"); role != "admin" {
+		t.Errorf("expected alice to be admin, got %q", role)
+	}
+	if role := p.RoleOf("bob"); role != "" {
+		t.Errorf("expected bob to have no role, got %q", role)
+	}
+	if p.Allows("bob", policy.ActionRead) {
+		t.Error("expected users without role to be denied everything")
+	}
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	p, err := policy.Parse([]byte(policyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Authorize(asUser("carol"), policy.ActionCreate); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for a viewer, got %v", err)
+	}
+	if err := p.Authorize(asUser("bob"), policy.ActionCreate); err != nil {
+		t.Errorf("expected a member to create todos, got %v", err)
+	}
+	if err := p.Authorize(context.Background(), policy.ActionPurge); err != nil {
+		t.Errorf("expected callers without user to pass, got %v", err)
+	}
+}
+
+func TestPolicy_Authorize_NilPolicyAllowsEverything(t *testing.T) {
+	var p *policy.Policy
+	if err := p.Authorize(asUser("carol"), policy.ActionPurge); err != nil {
+		t.Errorf("expected a nil policy to allow everything, got %v", err)
+	}
+}
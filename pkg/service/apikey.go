@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+
+	"github.com/lib/pq"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/select/get_api_keys.sql
+var getAPIKeysSQL string
+
+var getAPIKeysQuery = namedQuery{file: "sql/select/get_api_keys.sql", text: getAPIKeysSQL}
+
+//go:embed sql/select/get_api_key_by_hash.sql
+var getAPIKeyByHashSQL string
+
+var getAPIKeyByHashQuery = namedQuery{file: "sql/select/get_api_key_by_hash.sql", text: getAPIKeyByHashSQL}
+
+//go:embed sql/insert/create_api_key.sql
+var createAPIKeySQL string
+
+var createAPIKeyQuery = namedQuery{file: "sql/insert/create_api_key.sql", text: createAPIKeySQL}
+
+//go:embed sql/update/revoke_api_key.sql
+var revokeAPIKeySQL string
+
+var revokeAPIKeyQuery = namedQuery{file: "sql/update/revoke_api_key.sql", text: revokeAPIKeySQL}
+
+type (
+	// APIKeyInput describes an API key to store. Hash is the hex encoded SHA-256 of the
+	// key, which is the only way to find the key back. User is empty for keys acting
+	// for no user.
+	APIKeyInput struct {
+		Name   string
+		App    string
+		Scopes []string
+		Prefix string
+		Hash   string
+		User   string
+	}
+
+	// APIKey stores the API keys of the client applications.
+	APIKey interface {
+		// List returns every key, revoked ones included, oldest first.
+		List(ctx context.Context) ([]domain.APIKey, error)
+		// GetByHash returns the live key with the hash, or domain.ErrAPIKeyNotFound.
+		GetByHash(ctx context.Context, hash string) (domain.APIKey, error)
+		Create(ctx context.Context, input APIKeyInput) (domain.APIKey, error)
+		// Revoke revokes the key, returning domain.ErrAPIKeyNotFound when there is no
+		// live key with the id.
+		Revoke(ctx context.Context, id string) error
+	}
+
+	postgresAPIKeyService struct {
+		db querier
+	}
+)
+
+func NewAPIKey(db *sql.DB) APIKey {
+	return &postgresAPIKeyService{db: newTracedQuerier(db)}
+}
+
+func (s *postgresAPIKeyService) List(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, getAPIKeysQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *postgresAPIKeyService) GetByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	key, err := scanAPIKey(s.db.QueryRowContext(ctx, getAPIKeyByHashQuery, hash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.APIKey{}, domain.ErrAPIKeyNotFound
+		}
+		return domain.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (s *postgresAPIKeyService) Create(ctx context.Context, input APIKeyInput) (domain.APIKey, error) {
+	return scanAPIKey(s.db.QueryRowContext(ctx, createAPIKeyQuery,
+		input.Name,
+		input.App,
+		pq.StringArray(input.Scopes),
+		input.Prefix,
+		input.Hash,
+		sql.NullString{String: input.User, Valid: input.User != ""},
+	))
+}
+
+func (s *postgresAPIKeyService) Revoke(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, revokeAPIKeyQuery, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (domain.APIKey, error) {
+	var key domain.APIKey
+	var revokedAt sql.NullTime
+	var user sql.NullString
+	err := row.Scan(&key.ID, &key.Name, &key.App, pq.Array(&key.Scopes), &key.Prefix, &key.CreatedAt, &revokedAt, &user)
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	key.User = user.String
+	return key, err
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+const (
+	apiKeyUUID = "523e4567-e89b-12d3-a456-426614174000"
+	apiKeyHash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+)
+
+var apiKeyColumns = []string{"id", "name", "app", "scopes", "prefix", "created_at", "revoked_at", "user_id"}
+
+func TestAPIKeyService_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(apiKeyColumns).
+		AddRow(apiKeyUUID, "reminders", "reminders-job", "{todos:read}", "tk_abcdefgh", fixedTime, nil, nil).
+		AddRow(apiKeyUUID, "old", "reminders-job", "{todos:read,todos:write}", "tk_ijklmnop", fixedTime, fixedTime, "alice")
+	mock.ExpectQuery("FROM api_keys").WillReturnRows(rows)
+	svc := service.NewAPIKey(db)
+
+	keys, err := svc.List(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(keys))
+	}
+	if keys[0].Revoked() || len(keys[0].Scopes) != 1 || keys[0].Scopes[0] != domain.ScopeTodosRead {
+		t.Errorf("expected a live key granting todos:read, got %+v", keys[0])
+	}
+	if !keys[1].Revoked() || len(keys[1].Scopes) != 2 || keys[1].User != "alice" {
+		t.Errorf("expected a revoked key of alice granting 2 scopes, got %+v", keys[1])
+	}
+}
+
+func TestAPIKeyService_GetByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(apiKeyColumns).
+		AddRow(apiKeyUUID, "reminders", "reminders-job", "{todos:read}", "tk_abcdefgh", fixedTime, nil, nil)
+	mock.ExpectQuery("revoked_at IS NULL").WithArgs(apiKeyHash).WillReturnRows(rows)
+	svc := service.NewAPIKey(db)
+
+	key, err := svc.GetByHash(context.Background(), apiKeyHash)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if key.ID != apiKeyUUID || key.App != "reminders-job" {
+		t.Errorf("expected the key of reminders-job, got %+v", key)
+	}
+}
+
+func TestAPIKeyService_GetByHash_ReturnsErrAPIKeyNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("FROM api_keys").WithArgs(apiKeyHash).WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+	svc := service.NewAPIKey(db)
+
+	_, err = svc.GetByHash(context.Background(), apiKeyHash)
+
+	if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(apiKeyColumns).
+		AddRow(apiKeyUUID, "reminders", "reminders-job", "{todos:read}", "tk_abcdefgh", fixedTime, nil, nil)
+	mock.ExpectQuery("INSERT INTO api_keys").
+		WithArgs("reminders", "reminders-job", "{\"todos:read\"}", "tk_abcdefgh", apiKeyHash, nil).
+		WillReturnRows(rows)
+	svc := service.NewAPIKey(db)
+
+	key, err := svc.Create(context.Background(), service.APIKeyInput{
+		Name:   "reminders",
+		App:    "reminders-job",
+		Scopes: []string{domain.ScopeTodosRead},
+		Prefix: "tk_abcdefgh",
+		Hash:   apiKeyHash,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if key.ID != apiKeyUUID {
+		t.Errorf("expected ID %s, got %s", apiKeyUUID, key.ID)
+	}
+}
+
+func TestAPIKeyService_Revoke_ReturnsErrAPIKeyNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("UPDATE api_keys").WithArgs(apiKeyUUID).WillReturnResult(sqlmock.NewResult(0, 0))
+	svc := service.NewAPIKey(db)
+
+	err = svc.Revoke(context.Background(), apiKeyUUID)
+
+	if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/select/get_attachments.sql
+var getAttachmentsSQL string
+
+var getAttachmentsQuery = namedQuery{file: "sql/select/get_attachments.sql", text: getAttachmentsSQL}
+
+//go:embed sql/select/get_attachment_by_id.sql
+var getAttachmentByIDSQL string
+
+var getAttachmentByIDQuery = namedQuery{file: "sql/select/get_attachment_by_id.sql", text: getAttachmentByIDSQL}
+
+//go:embed sql/insert/create_attachment.sql
+var createAttachmentSQL string
+
+var createAttachmentQuery = namedQuery{file: "sql/insert/create_attachment.sql", text: createAttachmentSQL}
+
+type (
+	// AttachmentInput describes an attachment whose content is already in the blob store.
+	AttachmentInput struct {
+		TodoID      string
+		Filename    string
+		ContentType string
+		Size        int64
+		StorageKey  string
+	}
+
+	// Attachment stores the metadata of the files attached to todos. Like comments,
+	// attachments are always addressed through their todo, which is not checked.
+	Attachment interface {
+		// List returns the attachments of the todo, oldest first.
+		List(ctx context.Context, todoID string) ([]domain.Attachment, error)
+		GetByID(ctx context.Context, todoID, id string) (domain.Attachment, error)
+		Create(ctx context.Context, input AttachmentInput) (domain.Attachment, error)
+	}
+
+	postgresAttachmentService struct {
+		db querier
+	}
+)
+
+func NewAttachment(db *sql.DB) Attachment {
+	return &postgresAttachmentService{db: newTracedQuerier(db)}
+}
+
+func (s *postgresAttachmentService) List(ctx context.Context, todoID string) ([]domain.Attachment, error) {
+	rows, err := s.db.QueryContext(ctx, getAttachmentsQuery, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (s *postgresAttachmentService) GetByID(ctx context.Context, todoID, id string) (domain.Attachment, error) {
+	attachment, err := scanAttachment(s.db.QueryRowContext(ctx, getAttachmentByIDQuery, todoID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Attachment{}, domain.ErrAttachmentNotFound
+		}
+		return domain.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (s *postgresAttachmentService) Create(ctx context.Context, input AttachmentInput) (domain.Attachment, error) {
+	return scanAttachment(s.db.QueryRowContext(
+		ctx,
+		createAttachmentQuery,
+		input.TodoID,
+		input.Filename,
+		input.ContentType,
+		input.Size,
+		input.StorageKey,
+	))
+}
+
+func scanAttachment(row rowScanner) (domain.Attachment, error) {
+	var a domain.Attachment
+	err := row.Scan(&a.ID, &a.TodoID, &a.Filename, &a.ContentType, &a.Size, &a.StorageKey, &a.CreatedAt)
+	return a, err
+}
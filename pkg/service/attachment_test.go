@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+const attachmentUUID = "423e4567-e89b-12d3-a456-426614174000"
+
+var attachmentColumns = []string{"id", "todo_id", "filename", "content_type", "size", "storage_key", "created_at"}
+
+func TestAttachmentService_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(attachmentColumns).
+		AddRow(attachmentUUID, validUUID, "report.pdf", "application/pdf", 1024, validUUID+"/key", fixedTime)
+	mock.ExpectQuery("FROM todo_attachments").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.NewAttachment(db)
+
+	attachments, err := svc.List(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attachments) != 1 || attachments[0].Filename != "report.pdf" || attachments[0].Size != 1024 {
+		t.Errorf("expected the attachment, got %+v", attachments)
+	}
+}
+
+func TestAttachmentService_GetByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("FROM todo_attachments").WithArgs(validUUID, attachmentUUID).WillReturnRows(sqlmock.NewRows(attachmentColumns))
+	svc := service.NewAttachment(db)
+
+	_, err = svc.GetByID(context.Background(), validUUID, attachmentUUID)
+
+	if !errors.Is(err, domain.ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound, got %v", err)
+	}
+}
+
+func TestAttachmentService_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(attachmentColumns).
+		AddRow(attachmentUUID, validUUID, "notes.txt", "text/plain; charset=utf-8", 5, validUUID+"/key", fixedTime)
+	mock.ExpectQuery("INSERT INTO todo_attachments").
+		WithArgs(validUUID, "notes.txt", "text/plain; charset=utf-8", int64(5), validUUID+"/key").
+		WillReturnRows(rows)
+	svc := service.NewAttachment(db)
+
+	attachment, err := svc.Create(context.Background(), service.AttachmentInput{
+		TodoID:      validUUID,
+		Filename:    "notes.txt",
+		ContentType: "text/plain; charset=utf-8",
+		Size:        5,
+		StorageKey:  validUUID + "/key",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if attachment.ID != attachmentUUID {
+		t.Errorf("expected the created attachment, got %+v", attachment)
+	}
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"time"
+
+	"github.com/lib/pq"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/insert/create_todos_batch.sql
+var createTodosBatchSQL string
+
+var createTodosBatchQuery = namedQuery{file: "sql/insert/create_todos_batch.sql", text: createTodosBatchSQL}
+
+//go:embed sql/select/get_todos_by_ids.sql
+var getTodosByIDsSQL string
+
+var getTodosByIDsQuery = namedQuery{file: "sql/select/get_todos_by_ids.sql", text: getTodosByIDsSQL}
+
+//go:embed sql/update/update_todos_batch.sql
+var updateTodosBatchSQL string
+
+var updateTodosBatchQuery = namedQuery{file: "sql/update/update_todos_batch.sql", text: updateTodosBatchSQL}
+
+//go:embed sql/delete/delete_todos_batch.sql
+var deleteTodosBatchSQL string
+
+var deleteTodosBatchQuery = namedQuery{file: "sql/delete/delete_todos_batch.sql", text: deleteTodosBatchSQL}
+
+type (
+	// BatchUpdate is one item of UpdateBatch.
+	BatchUpdate struct {
+		ID    string
+		Input UpdateInput
+	}
+
+	// BatchDelete is one item of DeleteBatch.
+	BatchDelete struct {
+		ID    string
+		Input DeleteInput
+	}
+
+	// BatchResult is the outcome of one batch item. Results are returned in the
+	// order of the items; Todo is only set for successful updates and deletes.
+	BatchResult struct {
+		Todo domain.Todo
+		Err  error
+	}
+)
+
+// CreateBatch inserts every input with a single statement and returns the created
+// todos in input order.
+func (s *postgresService) CreateBatch(ctx context.Context, inputs []CreateInput) ([]domain.Todo, error) {
+	titles := make(pq.StringArray, len(inputs))
+	descriptions := make([]sql.NullString, len(inputs))
+	statuses := make(pq.StringArray, len(inputs))
+	priorities := make(pq.StringArray, len(inputs))
+	dueAts := make([]sql.NullString, len(inputs))
+	parentIDs := make([]sql.NullString, len(inputs))
+	recurrences := make([]sql.NullString, len(inputs))
+	ownerIDs := make([]sql.NullString, len(inputs))
+	assigneeIDs := make([]sql.NullString, len(inputs))
+
+	for i, in := range inputs {
+		titles[i] = in.Title
+		if in.Description != nil {
+			descriptions[i] = sql.NullString{String: *in.Description, Valid: true}
+		}
+		statuses[i] = string(in.Status)
+		priorities[i] = string(in.Priority)
+		dueAts[i] = nullTimestamp(in.DueAt)
+		parentIDs[i] = nullString(in.ParentID)
+		recurrences[i] = nullRecurrence(in.Recurrence)
+		ownerIDs[i] = nullString(in.OwnerID)
+		assigneeIDs[i] = nullString(in.AssigneeID)
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		createTodosBatchQuery,
+		titles,
+		pq.Array(descriptions),
+		statuses,
+		priorities,
+		pq.Array(dueAts),
+		pq.Array(parentIDs),
+		pq.Array(recurrences),
+		pq.Array(ownerIDs),
+		pq.Array(assigneeIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	todos := make([]domain.Todo, 0, len(inputs))
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+// GetByIDs returns the live todos among ids, with their subtask and dependency counts and
+// their shares, in no particular order. Unknown ids are skipped.
+func (s *postgresService) GetByIDs(ctx context.Context, ids []string) ([]domain.Todo, error) {
+	rows, err := s.db.QueryContext(ctx, getTodosByIDsQuery, pq.StringArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []domain.Todo
+	for rows.Next() {
+		var subtasks domain.Subtasks
+		var dependencies domain.Dependencies
+		var sharedWith pq.StringArray
+		todo, err := scanTodo(rows, &subtasks.Total, &subtasks.Completed, &dependencies.Total, &dependencies.Open, &sharedWith)
+		if err != nil {
+			return nil, err
+		}
+		todo.Subtasks = &subtasks
+		todo.Dependencies = &dependencies
+		todo.SharedWith = sharedWith
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+// UpdateBatch applies every update with a single statement. Items that did not
+// match a live todo (or its expected version) get ErrTodoNotFound or ErrVersionMismatch.
+func (s *postgresService) UpdateBatch(ctx context.Context, items []BatchUpdate) ([]BatchResult, error) {
+	ids := make(pq.StringArray, len(items))
+	titles := make([]sql.NullString, len(items))
+	descriptions := make([]sql.NullString, len(items))
+	statuses := make([]sql.NullString, len(items))
+	priorities := make([]sql.NullString, len(items))
+	dueAts := make([]sql.NullString, len(items))
+	clearDueAts := make(pq.BoolArray, len(items))
+	versions := make([]sql.NullInt64, len(items))
+	parentIDs := make([]sql.NullString, len(items))
+	clearParents := make(pq.BoolArray, len(items))
+	recurrences := make([]sql.NullString, len(items))
+	clearRecurrences := make(pq.BoolArray, len(items))
+	assigneeIDs := make([]sql.NullString, len(items))
+	clearAssignees := make(pq.BoolArray, len(items))
+
+	for i, item := range items {
+		in := item.Input
+		ids[i] = item.ID
+		titles[i] = nullString(in.Title)
+		descriptions[i] = nullString(in.Description)
+		if in.Status != nil {
+			statuses[i] = sql.NullString{String: string(*in.Status), Valid: true}
+		}
+		if in.Priority != nil {
+			priorities[i] = sql.NullString{String: string(*in.Priority), Valid: true}
+		}
+		dueAts[i] = nullTimestamp(in.DueAt)
+		clearDueAts[i] = in.ClearDueAt
+		versions[i] = nullVersion(in.ExpectedVersion)
+		parentIDs[i] = nullString(in.ParentID)
+		clearParents[i] = in.ClearParent
+		recurrences[i] = nullRecurrence(in.Recurrence)
+		clearRecurrences[i] = in.ClearRecurrence
+		assigneeIDs[i] = nullString(in.AssigneeID)
+		clearAssignees[i] = in.ClearAssignee
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		updateTodosBatchQuery,
+		ids,
+		pq.Array(titles),
+		pq.Array(descriptions),
+		pq.Array(statuses),
+		pq.Array(priorities),
+		pq.Array(dueAts),
+		clearDueAts,
+		pq.Array(versions),
+		pq.Array(parentIDs),
+		clearParents,
+		pq.Array(recurrences),
+		clearRecurrences,
+		pq.Array(assigneeIDs),
+		clearAssignees,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updated := make(map[string]domain.Todo, len(items))
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		updated[todo.ID] = todo
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(items))
+	missing := make(map[int]*int)
+	for i, item := range items {
+		if todo, ok := updated[item.ID]; ok {
+			results[i].Todo = todo
+			continue
+		}
+		missing[i] = item.Input.ExpectedVersion
+	}
+
+	return results, s.resolveMissing(ctx, results, missing, func(i int) string { return items[i].ID })
+}
+
+// DeleteBatch moves every todo to the trash with a single statement, the trashed todo being
+// the Todo of its result. Items that did not match a live todo (or its expected version) get
+// ErrTodoNotFound or ErrVersionMismatch.
+func (s *postgresService) DeleteBatch(ctx context.Context, items []BatchDelete) ([]BatchResult, error) {
+	ids := make(pq.StringArray, len(items))
+	versions := make([]sql.NullInt64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+		versions[i] = nullVersion(item.Input.ExpectedVersion)
+	}
+
+	rows, err := s.db.QueryContext(ctx, deleteTodosBatchQuery, ids, pq.Array(versions))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deleted := make(map[string]domain.Todo, len(items))
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		deleted[todo.ID] = todo
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(items))
+	missing := make(map[int]*int)
+	for i, item := range items {
+		if todo, ok := deleted[item.ID]; ok {
+			results[i].Todo = todo
+			continue
+		}
+		missing[i] = item.Input.ExpectedVersion
+	}
+
+	return results, s.resolveMissing(ctx, results, missing, func(i int) string { return items[i].ID })
+}
+
+// resolveMissing is the batch counterpart of notFoundOrStale: it sets the error of every
+// missing result, looking up in one query which of the version guarded ones still exist.
+func (s *postgresService) resolveMissing(ctx context.Context, results []BatchResult, missing map[int]*int, idOf func(int) string) error {
+	var guarded []string
+	for i, v := range missing {
+		results[i].Err = domain.ErrTodoNotFound
+		if v != nil {
+			guarded = append(guarded, idOf(i))
+		}
+	}
+	if len(guarded) == 0 {
+		return nil
+	}
+
+	existing, err := s.GetByIDs(ctx, guarded)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]struct{}, len(existing))
+	for _, todo := range existing {
+		exists[todo.ID] = struct{}{}
+	}
+
+	for i, v := range missing {
+		if _, ok := exists[idOf(i)]; ok && v != nil {
+			results[i].Err = domain.ErrVersionMismatch
+		}
+	}
+	return nil
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// nullTimestamp formats t for a TIMESTAMP[] parameter; pq has no array form for time.Time.
+func nullTimestamp(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(time.RFC3339Nano), Valid: true}
+}
+
+func nullVersion(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+// nullRecurrence stores a rule in its RRULE form.
+func nullRecurrence(r *domain.Recurrence) sql.NullString {
+	if r == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: r.String(), Valid: true}
+}
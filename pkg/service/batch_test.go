@@ -0,0 +1,162 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+const otherUUID = "223e4567-e89b-12d3-a456-426614174000"
+
+func TestService_CreateBatch_ReturnsTodosInInputOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, "first", nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil).
+		AddRow(otherUUID, nil, "second", validDescription, domain.StatusPending, domain.PriorityHigh, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
+	mock.ExpectQuery(`INSERT INTO todos`).
+		WithArgs(`{"first","second"}`, `{NULL,"Test Description"}`, `{"pending","pending"}`, `{"medium","high"}`, `{NULL,NULL}`, `{NULL,NULL}`, `{NULL,NULL}`, `{NULL,NULL}`, `{NULL,NULL}`).
+		WillReturnRows(rows)
+	svc := service.New(db)
+	description := validDescription
+
+	result, err := svc.CreateBatch(context.Background(), []service.CreateInput{
+		{Title: "first", Status: domain.StatusPending, Priority: domain.PriorityMedium},
+		{Title: "second", Description: &description, Status: domain.StatusPending, Priority: domain.PriorityHigh},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 2 || result[0].ID != validUUID || result[1].ID != otherUUID {
+		t.Errorf("expected todos in input order, got %+v", result)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_UpdateBatch_ReportsMissingAndStaleItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	title := "Updated"
+	version := 3
+	mock.ExpectQuery(`UPDATE todos`).
+		WithArgs(`{"`+validUUID+`","`+otherUUID+`","`+nonExistentID+`"}`, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), `{f,f,f}`, `{NULL,3,3}`, `{NULL,NULL,NULL}`, `{f,f,f}`, `{NULL,NULL,NULL}`, `{f,f,f}`, `{NULL,NULL,NULL}`, `{f,f,f}`).
+		WillReturnRows(sqlmock.NewRows(todoColumns).
+			AddRow(validUUID, nil, title, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 2, fixedTime, fixedTime, nil))
+	mock.ExpectQuery(`WHERE id = ANY`).
+		WillReturnRows(sqlmock.NewRows(byIDColumns).
+			AddRow(otherUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 4, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil))
+	svc := service.New(db)
+
+	result, err := svc.UpdateBatch(context.Background(), []service.BatchUpdate{
+		{ID: validUUID, Input: service.UpdateInput{Title: &title}},
+		{ID: otherUUID, Input: service.UpdateInput{Title: &title, ExpectedVersion: &version}},
+		{ID: nonExistentID, Input: service.UpdateInput{Title: &title, ExpectedVersion: &version}},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result[0].Err != nil || result[0].Todo.Title != title {
+		t.Errorf("expected first item updated, got %+v", result[0])
+	}
+	if !errors.Is(result[1].Err, domain.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch, got %v", result[1].Err)
+	}
+	if !errors.Is(result[2].Err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", result[2].Err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_DeleteBatch_ReportsMissingItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SET\s+deleted_at = NOW\(\)`).
+		WithArgs(`{"`+validUUID+`","`+nonExistentID+`"}`, `{NULL,NULL}`).
+		WillReturnRows(todoRows(validUUID))
+	svc := service.New(db)
+
+	result, err := svc.DeleteBatch(context.Background(), []service.BatchDelete{
+		{ID: validUUID},
+		{ID: nonExistentID},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result[0].Err != nil || result[0].Todo.ID != validUUID {
+		t.Errorf("expected first item deleted, got %+v", result[0])
+	}
+	if !errors.Is(result[1].Err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", result[1].Err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_Transaction_CommitsOnSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SET\s+deleted_at = NOW\(\)`).
+		WillReturnRows(todoRows(validUUID))
+	mock.ExpectCommit()
+	svc := service.New(db)
+
+	err = svc.Transaction(context.Background(), func(tx service.Todo) error {
+		_, err := tx.DeleteBatch(context.Background(), []service.BatchDelete{{ID: validUUID}})
+		return err
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_Transaction_RollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	svc := service.New(db)
+
+	err = svc.Transaction(context.Background(), func(tx service.Todo) error {
+		return domain.ErrBatchAborted
+	})
+
+	if !errors.Is(err, domain.ErrBatchAborted) {
+		t.Errorf("expected ErrBatchAborted, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/service"
+)
+
+func TestLocalBlobStore_PutGetDelete(t *testing.T) {
+	store, err := service.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, validUUID+"/report", strings.NewReader("content")); err != nil {
+		t.Fatalf("expected no error on put, got %v", err)
+	}
+
+	r, err := store.Get(ctx, validUUID+"/report")
+	if err != nil {
+		t.Fatalf("expected no error on get, got %v", err)
+	}
+	b, _ := io.ReadAll(r)
+	_ = r.Close()
+	if string(b) != "content" {
+		t.Errorf("expected the stored content, got %q", b)
+	}
+
+	if err := store.Delete(ctx, validUUID+"/report"); err != nil {
+		t.Fatalf("expected no error on delete, got %v", err)
+	}
+	if _, err := store.Get(ctx, validUUID+"/report"); !errors.Is(err, service.ErrBlobNotFound) {
+		t.Errorf("expected ErrBlobNotFound once deleted, got %v", err)
+	}
+}
+
+func TestLocalBlobStore_RejectsEscapingKeys(t *testing.T) {
+	store, err := service.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	for _, key := range []string{"", "../outside", "/etc/passwd", `a\..\b`} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("expected key %q to be refused", key)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/select/get_comments.sql
+var getCommentsSQL string
+
+var getCommentsQuery = namedQuery{file: "sql/select/get_comments.sql", text: getCommentsSQL}
+
+//go:embed sql/select/count_comments.sql
+var countCommentsSQL string
+
+var countCommentsQuery = namedQuery{file: "sql/select/count_comments.sql", text: countCommentsSQL}
+
+//go:embed sql/select/get_comment_by_id.sql
+var getCommentByIDSQL string
+
+var getCommentByIDQuery = namedQuery{file: "sql/select/get_comment_by_id.sql", text: getCommentByIDSQL}
+
+//go:embed sql/insert/create_comment.sql
+var createCommentSQL string
+
+var createCommentQuery = namedQuery{file: "sql/insert/create_comment.sql", text: createCommentSQL}
+
+//go:embed sql/update/update_comment.sql
+var updateCommentSQL string
+
+var updateCommentQuery = namedQuery{file: "sql/update/update_comment.sql", text: updateCommentSQL}
+
+//go:embed sql/delete/delete_comment.sql
+var deleteCommentSQL string
+
+var deleteCommentQuery = namedQuery{file: "sql/delete/delete_comment.sql", text: deleteCommentSQL}
+
+type (
+	// CommentPage bounds a listing of comments. Comments are returned in creation order
+	// (with id as the tie-breaker), starting strictly after the After cursor when it is
+	// set, whose only value is the creation time of the last comment of the previous page.
+	CommentPage struct {
+		Limit int
+		After *Cursor
+	}
+
+	// Comment stores the comments of todos. Comments are always addressed through their
+	// todo: one that belongs to another todo is reported as domain.ErrCommentNotFound.
+	// The todo itself is not checked.
+	Comment interface {
+		List(ctx context.Context, todoID string, page CommentPage) ([]domain.Comment, error)
+		Count(ctx context.Context, todoID string) (int, error)
+		GetByID(ctx context.Context, todoID, id string) (domain.Comment, error)
+		// Create stores a comment written by author, which is left empty when there is none.
+		Create(ctx context.Context, todoID, body, author string) (domain.Comment, error)
+		Update(ctx context.Context, todoID, id, body string) (domain.Comment, error)
+		Delete(ctx context.Context, todoID, id string) error
+	}
+
+	postgresCommentService struct {
+		db querier
+	}
+)
+
+func NewComment(db *sql.DB) Comment {
+	return &postgresCommentService{db: newTracedQuerier(db)}
+}
+
+func (s *postgresCommentService) List(ctx context.Context, todoID string, page CommentPage) ([]domain.Comment, error) {
+	var afterCreatedAt, afterID any
+	if page.After != nil {
+		if len(page.After.Values) != 1 || parseTime(page.After.Values[0]) != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		afterCreatedAt, afterID = page.After.Values[0], page.After.ID
+	}
+
+	rows, err := s.db.QueryContext(ctx, getCommentsQuery, todoID, afterCreatedAt, afterID, page.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []domain.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+func (s *postgresCommentService) Count(ctx context.Context, todoID string) (int, error) {
+	var total int
+	if err := s.db.QueryRowContext(ctx, countCommentsQuery, todoID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (s *postgresCommentService) GetByID(ctx context.Context, todoID, id string) (domain.Comment, error) {
+	comment, err := scanComment(s.db.QueryRowContext(ctx, getCommentByIDQuery, todoID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Comment{}, domain.ErrCommentNotFound
+		}
+		return domain.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+func (s *postgresCommentService) Create(ctx context.Context, todoID, body, author string) (domain.Comment, error) {
+	return scanComment(s.db.QueryRowContext(ctx, createCommentQuery, todoID, body, sql.NullString{String: author, Valid: author != ""}))
+}
+
+func (s *postgresCommentService) Update(ctx context.Context, todoID, id, body string) (domain.Comment, error) {
+	comment, err := scanComment(s.db.QueryRowContext(ctx, updateCommentQuery, todoID, id, body))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Comment{}, domain.ErrCommentNotFound
+		}
+		return domain.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+func (s *postgresCommentService) Delete(ctx context.Context, todoID, id string) error {
+	result, err := s.db.ExecContext(ctx, deleteCommentQuery, todoID, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func scanComment(row rowScanner) (domain.Comment, error) {
+	var comment domain.Comment
+	var author sql.NullString
+	err := row.Scan(&comment.ID, &comment.TodoID, &comment.Body, &author, &comment.CreatedAt, &comment.UpdatedAt)
+	comment.Author = author.String
+	return comment, err
+}
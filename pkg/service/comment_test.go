@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+const commentUUID = "323e4567-e89b-12d3-a456-426614174000"
+
+var commentColumns = []string{"id", "todo_id", "body", "author", "created_at", "updated_at"}
+
+func TestCommentService_List_FirstPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(commentColumns).
+		AddRow(commentUUID, validUUID, "First!", nil, fixedTime, fixedTime)
+	mock.ExpectQuery("FROM todo_comments").WithArgs(validUUID, nil, nil, 11).WillReturnRows(rows)
+	svc := service.NewComment(db)
+
+	comments, err := svc.List(context.Background(), validUUID, service.CommentPage{Limit: 11})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(comments) != 1 || comments[0].Body != "First!" || comments[0].TodoID != validUUID {
+		t.Errorf("expected the comment, got %+v", comments)
+	}
+}
+
+func TestCommentService_List_AfterCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	after := fixedTime.Format(time.RFC3339Nano)
+	mock.ExpectQuery(`\(created_at, id\) > \(\$2::TIMESTAMP, \$3::UUID\)`).
+		WithArgs(validUUID, after, commentUUID, 11).
+		WillReturnRows(sqlmock.NewRows(commentColumns))
+	svc := service.NewComment(db)
+
+	_, err = svc.List(context.Background(), validUUID, service.CommentPage{
+		Limit: 11,
+		After: &service.Cursor{Values: []string{after}, ID: commentUUID},
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCommentService_List_RejectsMalformedCursor(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	svc := service.NewComment(db)
+
+	_, err = svc.List(context.Background(), validUUID, service.CommentPage{
+		Limit: 11,
+		After: &service.Cursor{Values: []string{"yesterday"}, ID: commentUUID},
+	})
+
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestCommentService_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(commentColumns).
+		AddRow(commentUUID, validUUID, "Looks good", "alice", fixedTime, fixedTime)
+	mock.ExpectQuery("INSERT INTO todo_comments").WithArgs(validUUID, "Looks good", "alice").WillReturnRows(rows)
+	svc := service.NewComment(db)
+
+	comment, err := svc.Create(context.Background(), validUUID, "Looks good", "alice")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if comment.ID != commentUUID || comment.Author != "alice" {
+		t.Errorf("expected comment %s by alice, got %+v", commentUUID, comment)
+	}
+}
+
+func TestCommentService_Create_WithoutAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(commentColumns).
+		AddRow(commentUUID, validUUID, "Looks good", nil, fixedTime, fixedTime)
+	mock.ExpectQuery("INSERT INTO todo_comments").WithArgs(validUUID, "Looks good", nil).WillReturnRows(rows)
+	svc := service.NewComment(db)
+
+	comment, err := svc.Create(context.Background(), validUUID, "Looks good", "")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if comment.Author != "" {
+		t.Errorf("expected no author, got %q", comment.Author)
+	}
+}
+
+func TestCommentService_GetByID_ReturnsErrCommentNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("FROM todo_comments").WithArgs(validUUID, commentUUID).
+		WillReturnRows(sqlmock.NewRows(commentColumns))
+	svc := service.NewComment(db)
+
+	_, err = svc.GetByID(context.Background(), validUUID, commentUUID)
+
+	if !errors.Is(err, domain.ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestCommentService_Update_ReturnsErrCommentNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("UPDATE todo_comments").WithArgs(validUUID, commentUUID, "Edited").
+		WillReturnRows(sqlmock.NewRows(commentColumns))
+	svc := service.NewComment(db)
+
+	_, err = svc.Update(context.Background(), validUUID, commentUUID, "Edited")
+
+	if !errors.Is(err, domain.ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestCommentService_Delete_ReturnsErrCommentNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("DELETE FROM todo_comments").WithArgs(validUUID, commentUUID).WillReturnResult(sqlmock.NewResult(0, 0))
+	svc := service.NewComment(db)
+
+	err = svc.Delete(context.Background(), validUUID, commentUUID)
+
+	if !errors.Is(err, domain.ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestCommentService_Count(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("SELECT COUNT").WithArgs(validUUID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	svc := service.NewComment(db)
+
+	total, err := svc.Count(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if total != 4 {
+		t.Errorf("expected 4 comments, got %d", total)
+	}
+}
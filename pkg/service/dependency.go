@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/lib/pq"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/select/get_blocker_ids.sql
+var getBlockerIDsSQL string
+
+var getBlockerIDsQuery = namedQuery{file: "sql/select/get_blocker_ids.sql", text: getBlockerIDsSQL}
+
+//go:embed sql/select/get_blockers.sql
+var getBlockersSQL string
+
+var getBlockersQuery = namedQuery{file: "sql/select/get_blockers.sql", text: getBlockersSQL}
+
+//go:embed sql/insert/add_dependency.sql
+var addDependencySQL string
+
+var addDependencyQuery = namedQuery{file: "sql/insert/add_dependency.sql", text: addDependencySQL}
+
+//go:embed sql/delete/remove_dependency.sql
+var removeDependencySQL string
+
+var removeDependencyQuery = namedQuery{file: "sql/delete/remove_dependency.sql", text: removeDependencySQL}
+
+func (s *postgresService) GetBlockerIDs(ctx context.Context, id string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, getBlockerIDsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var blocker string
+		if err := rows.Scan(&blocker); err != nil {
+			return nil, err
+		}
+		ids = append(ids, blocker)
+	}
+
+	return ids, rows.Err()
+}
+
+func (s *postgresService) GetBlockers(ctx context.Context, id string) ([]domain.Todo, error) {
+	rows, err := s.db.QueryContext(ctx, getBlockersQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []domain.Todo
+	for rows.Next() {
+		var sharedWith pq.StringArray
+		todo, err := scanTodo(rows, &sharedWith)
+		if err != nil {
+			return nil, err
+		}
+		todo.SharedWith = sharedWith
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+// AddDependency records that the todo is blocked by blockerID. Adding an existing
+// dependency again is a no-op.
+func (s *postgresService) AddDependency(ctx context.Context, id, blockerID string) error {
+	_, err := s.db.ExecContext(ctx, addDependencyQuery, id, blockerID)
+	return err
+}
+
+// RemoveDependency deletes the dependency of the todo on blockerID, returning
+// domain.ErrDependencyNotFound when there is none.
+func (s *postgresService) RemoveDependency(ctx context.Context, id, blockerID string) error {
+	removed, err := s.exec(ctx, removeDependencyQuery, id, blockerID)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return domain.ErrDependencyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+func TestService_GetByID_ReturnsDependencies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 3, 1, nil)
+	mock.ExpectQuery("FROM todo_dependencies").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.GetByID(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Dependencies == nil || result.Dependencies.Total != 3 || result.Dependencies.Open != 1 {
+		t.Errorf("expected 1 of 3 dependencies open, got %+v", result.Dependencies)
+	}
+}
+
+func TestService_Get_WithBlockedFilter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	blocked := true
+	mock.ExpectQuery(`\$11::BOOLEAN IS NULL OR \$11 = EXISTS`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, true, nil, nil, 10).
+		WillReturnRows(sqlmock.NewRows(listColumns))
+	svc := service.New(db)
+
+	_, err = svc.Get(context.Background(), service.Filters{Blocked: &blocked}, service.Page{Limit: 10})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_GetBlockerIDs_ReturnsTransitiveBlockers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("WITH RECURSIVE blockers").WithArgs(validUUID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(validUUID).AddRow(otherUUID))
+	svc := service.New(db)
+
+	ids, err := svc.GetBlockerIDs(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(ids) != 2 || ids[1] != otherUUID {
+		t.Errorf("expected the todo and its blocker, got %v", ids)
+	}
+}
+
+func TestService_GetBlockers_ReturnsTodos(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(append(todoColumns, "shared_with")).
+		AddRow(otherUUID, nil, validTitle, nil, domain.StatusInProgress, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, nil)
+	mock.ExpectQuery("JOIN todos b ON b.id = d.blocker_id").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+
+	blockers, err := svc.GetBlockers(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != otherUUID {
+		t.Errorf("expected blocker %s, got %+v", otherUUID, blockers)
+	}
+}
+
+func TestService_AddDependency(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("INSERT INTO todo_dependencies").WithArgs(validUUID, otherUUID).WillReturnResult(sqlmock.NewResult(0, 1))
+	svc := service.New(db)
+
+	err = svc.AddDependency(context.Background(), validUUID, otherUUID)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_RemoveDependency_ReturnsErrDependencyNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("DELETE FROM todo_dependencies").WithArgs(validUUID, otherUUID).WillReturnResult(sqlmock.NewResult(0, 0))
+	svc := service.New(db)
+
+	err = svc.RemoveDependency(context.Background(), validUUID, otherUUID)
+
+	if !errors.Is(err, domain.ErrDependencyNotFound) {
+		t.Errorf("expected ErrDependencyNotFound, got %v", err)
+	}
+}
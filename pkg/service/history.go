@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+
+	"github.com/lib/pq"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/insert/add_history.sql
+var addHistorySQL string
+
+var addHistoryQuery = namedQuery{file: "sql/insert/add_history.sql", text: addHistorySQL}
+
+//go:embed sql/select/get_history.sql
+var getHistorySQL string
+
+var getHistoryQuery = namedQuery{file: "sql/select/get_history.sql", text: getHistorySQL}
+
+//go:embed sql/select/count_history.sql
+var countHistorySQL string
+
+var countHistoryQuery = namedQuery{file: "sql/select/count_history.sql", text: countHistorySQL}
+
+type (
+	// HistoryPage bounds a listing of history entries. Entries are returned newest first
+	// (with id as the tie-breaker), starting strictly after the After cursor when it is set,
+	// whose only value is the creation time of the last entry of the previous page.
+	HistoryPage struct {
+		Limit int
+		After *Cursor
+	}
+
+	// historyChange is the stored form of a domain.FieldChange.
+	historyChange struct {
+		Field  string `json:"field"`
+		Before any    `json:"before"`
+		After  any    `json:"after"`
+	}
+)
+
+func (s *postgresService) AddHistory(ctx context.Context, entries []domain.HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	todoIDs := make(pq.StringArray, len(entries))
+	actions := make(pq.StringArray, len(entries))
+	changes := make(pq.StringArray, len(entries))
+	apps := make(pq.StringArray, len(entries))
+	scopes := make(pq.StringArray, len(entries))
+	users := make(pq.StringArray, len(entries))
+
+	for i, entry := range entries {
+		stored := make([]historyChange, len(entry.Changes))
+		for j, c := range entry.Changes {
+			stored[j] = historyChange{Field: c.Field, Before: c.Before, After: c.After}
+		}
+		b, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+
+		todoIDs[i] = entry.TodoID
+		actions[i] = string(entry.Action)
+		changes[i] = string(b)
+		apps[i] = entry.Caller.App
+		scopes[i] = entry.Caller.Scope
+		users[i] = entry.Caller.User
+	}
+
+	_, err := s.db.ExecContext(ctx, addHistoryQuery, todoIDs, actions, changes, apps, scopes, users)
+	return err
+}
+
+func (s *postgresService) GetHistory(ctx context.Context, todoID string, page HistoryPage) ([]domain.HistoryEntry, error) {
+	var afterCreatedAt, afterID any
+	if page.After != nil {
+		if len(page.After.Values) != 1 || parseTime(page.After.Values[0]) != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		afterCreatedAt, afterID = page.After.Values[0], page.After.ID
+	}
+
+	rows, err := s.db.QueryContext(ctx, getHistoryQuery, todoID, afterCreatedAt, afterID, page.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.HistoryEntry
+	for rows.Next() {
+		entry, err := scanHistoryEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (s *postgresService) CountHistory(ctx context.Context, todoID string) (int, error) {
+	var total int
+	if err := s.db.QueryRowContext(ctx, countHistoryQuery, todoID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func scanHistoryEntry(row rowScanner) (domain.HistoryEntry, error) {
+	var entry domain.HistoryEntry
+	var action string
+	var changes []byte
+	err := row.Scan(
+		&entry.ID,
+		&entry.TodoID,
+		&action,
+		&changes,
+		&entry.Caller.App,
+		&entry.Caller.Scope,
+		&entry.Caller.User,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	entry.Action = domain.HistoryAction(action)
+
+	var stored []historyChange
+	if err := json.Unmarshal(changes, &stored); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	for _, c := range stored {
+		entry.Changes = append(entry.Changes, domain.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+
+	return entry, nil
+}
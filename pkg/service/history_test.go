@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+var historyColumns = []string{"id", "todo_id", "action", "changes", "caller_app", "caller_scope", "caller_user", "created_at"}
+
+func TestService_AddHistory_InsertsEveryEntryAtOnce(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("INSERT INTO todo_history").
+		WithArgs(
+			pq.StringArray{validUUID, otherUUID},
+			pq.StringArray{"update", "delete"},
+			pq.StringArray{`[{"field":"title","before":"Old","after":"New"}]`, `[]`},
+			pq.StringArray{"web", "web"},
+			pq.StringArray{"prod", "prod"},
+			pq.StringArray{"", ""},
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	svc := service.New(db)
+	caller := domain.Caller{App: "web", Scope: "prod"}
+
+	err = svc.AddHistory(context.Background(), []domain.HistoryEntry{
+		{TodoID: validUUID, Action: domain.HistoryUpdate, Changes: []domain.FieldChange{{Field: "title", Before: "Old", After: "New"}}, Caller: caller},
+		{TodoID: otherUUID, Action: domain.HistoryDelete, Caller: caller},
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_AddHistory_NoEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	svc := service.New(db)
+
+	if err := svc.AddHistory(context.Background(), nil); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected no statement, got %v", err)
+	}
+}
+
+func TestService_GetHistory_DecodesChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(historyColumns).
+		AddRow(otherUUID, validUUID, "update", []byte(`[{"field":"tags","before":null,"after":["q3"]}]`), "web", "prod", "alice", fixedTime)
+	mock.ExpectQuery("FROM todo_history").WithArgs(validUUID, nil, nil, 11).WillReturnRows(rows)
+	svc := service.New(db)
+
+	entries, err := svc.GetHistory(context.Background(), validUUID, service.HistoryPage{Limit: 11})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != domain.HistoryUpdate || entries[0].Caller.User != "alice" {
+		t.Fatalf("expected the entry, got %+v", entries)
+	}
+	if c := entries[0].Changes; len(c) != 1 || c[0].Field != "tags" || c[0].Before != nil {
+		t.Errorf("expected the tags change, got %+v", c)
+	}
+}
+
+func TestService_GetHistory_AfterCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	after := fixedTime.Format(time.RFC3339Nano)
+	mock.ExpectQuery(`\(created_at, id\) < \(\$2::TIMESTAMP, \$3::UUID\)`).
+		WithArgs(validUUID, after, otherUUID, 11).
+		WillReturnRows(sqlmock.NewRows(historyColumns))
+	svc := service.New(db)
+
+	_, err = svc.GetHistory(context.Background(), validUUID, service.HistoryPage{
+		Limit: 11,
+		After: &service.Cursor{Values: []string{after}, ID: otherUUID},
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestService_GetHistory_RejectsMalformedCursor(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	svc := service.New(db)
+
+	_, err = svc.GetHistory(context.Background(), validUUID, service.HistoryPage{
+		Limit: 11,
+		After: &service.Cursor{Values: []string{"yesterday"}, ID: otherUUID},
+	})
+
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestService_CountHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("FROM todo_history").WithArgs(validUUID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	svc := service.New(db)
+
+	total, err := svc.CountHistory(context.Background(), validUUID)
+
+	if err != nil || total != 4 {
+		t.Errorf("expected 4 entries, got %d (%v)", total, err)
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo-api/pkg/domain"
+)
+
+// filterArgCount is the number of positional arguments consumed by the WHERE
+// clause shared by get_todos.sql and count_todos.sql.
+const filterArgCount = 13
+
+type (
+	// sortColumn describes how a whitelisted sort field is ordered in SQL and how
+	// its value is captured into (and validated from) a keyset cursor.
+	sortColumn struct {
+		expr  string
+		cast  string
+		value func(domain.Todo) string
+		parse func(string) error
+	}
+)
+
+var sortColumns = map[domain.SortField]sortColumn{
+	domain.SortCreatedAt: {
+		expr:  "created_at",
+		cast:  "TIMESTAMP",
+		value: func(t domain.Todo) string { return t.CreatedAt.Format(time.RFC3339Nano) },
+		parse: parseTime,
+	},
+	domain.SortUpdatedAt: {
+		expr:  "updated_at",
+		cast:  "TIMESTAMP",
+		value: func(t domain.Todo) string { return t.UpdatedAt.Format(time.RFC3339Nano) },
+		parse: parseTime,
+	},
+	domain.SortDueAt: {
+		// todos without a due date sort after every dated one
+		expr:  "COALESCE(due_at, 'infinity'::TIMESTAMP)",
+		cast:  "TIMESTAMP",
+		value: dueAtValue,
+		parse: parseDueAt,
+	},
+	domain.SortTitle: {
+		expr:  "title",
+		cast:  "VARCHAR",
+		value: func(t domain.Todo) string { return t.Title },
+		parse: func(string) error { return nil },
+	},
+	domain.SortStatus: {
+		expr:  "CASE status WHEN 'pending' THEN 1 WHEN 'in_progress' THEN 2 WHEN 'completed' THEN 3 END",
+		cast:  "INT",
+		value: func(t domain.Todo) string { return strconv.Itoa(t.Status.Rank()) },
+		parse: parseInt,
+	},
+	domain.SortPriority: {
+		expr:  "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END",
+		cast:  "INT",
+		value: func(t domain.Todo) string { return strconv.Itoa(t.Priority.Rank()) },
+		parse: parseInt,
+	},
+	domain.SortDeletedAt: {
+		expr:  "deleted_at",
+		cast:  "TIMESTAMP",
+		value: deletedAtValue,
+		parse: parseTime,
+	},
+	domain.SortRelevance: {
+		expr:  "ts_rank(search_vector, websearch_to_tsquery('english', $3))",
+		cast:  "REAL",
+		value: func(t domain.Todo) string { return strconv.FormatFloat(relevance(t), 'g', -1, 64) },
+		parse: parseFloat,
+	},
+}
+
+// NewCursor captures the keyset position of a todo under the given sort.
+func NewCursor(todo domain.Todo, sort []domain.SortOrder) Cursor {
+	values := make([]string, len(sort))
+	for i, o := range sort {
+		values[i] = sortColumns[o.Field].value(todo)
+	}
+	return Cursor{Values: values, ID: todo.ID}
+}
+
+// buildGetTodosQuery completes the embedded list query with the keyset predicate (when
+// paging after a cursor), the ORDER BY clause and the LIMIT. The id column is always
+// appended as the last sort key so that the ordering is total.
+func buildGetTodosQuery(sort []domain.SortOrder, after bool) namedQuery {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(getTodosQuery.text, "; \n"))
+
+	next := filterArgCount + 1
+	if after {
+		b.WriteString("\n  AND ")
+		b.WriteString(keysetPredicate(sort, next))
+		next += len(sort) + 1
+	}
+
+	keys := make([]string, 0, len(sort)+1)
+	for _, o := range sort {
+		keys = append(keys, sortColumns[o.Field].expr+direction(o.Desc))
+	}
+	keys = append(keys, "id"+direction(lastDesc(sort)))
+
+	b.WriteString("\nORDER BY ")
+	b.WriteString(strings.Join(keys, ", "))
+	fmt.Fprintf(&b, "\nLIMIT $%d;", next)
+
+	return getTodosQuery.completed(b.String())
+}
+
+// keysetPredicate builds the "rows strictly after the cursor" condition. When every key
+// shares the same direction a row comparison is used so Postgres can walk a matching
+// index, otherwise it expands into the equivalent disjunction.
+func keysetPredicate(sort []domain.SortOrder, first int) string {
+	exprs := make([]string, 0, len(sort)+1)
+	params := make([]string, 0, len(sort)+1)
+	descs := make([]bool, 0, len(sort)+1)
+	for i, o := range sort {
+		c := sortColumns[o.Field]
+		exprs = append(exprs, c.expr)
+		params = append(params, fmt.Sprintf("$%d::%s", first+i, c.cast))
+		descs = append(descs, o.Desc)
+	}
+	exprs = append(exprs, "id")
+	params = append(params, fmt.Sprintf("$%d::UUID", first+len(sort)))
+	descs = append(descs, lastDesc(sort))
+
+	uniform := true
+	for _, d := range descs {
+		uniform = uniform && d == descs[0]
+	}
+	if uniform {
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), comparator(descs[0]), strings.Join(params, ", "))
+	}
+
+	terms := make([]string, len(exprs))
+	for i := range exprs {
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, fmt.Sprintf("%s = %s", exprs[j], params[j]))
+		}
+		conds = append(conds, fmt.Sprintf("%s %s %s", exprs[i], comparator(descs[i]), params[i]))
+		terms[i] = "(" + strings.Join(conds, " AND ") + ")"
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+func validateCursor(c Cursor, sort []domain.SortOrder) error {
+	if len(c.Values) != len(sort) || domain.ValidateUUID(c.ID) != nil {
+		return domain.ErrInvalidCursor
+	}
+	for i, o := range sort {
+		if err := sortColumns[o.Field].parse(c.Values[i]); err != nil {
+			return domain.ErrInvalidCursor
+		}
+	}
+	return nil
+}
+
+func lastDesc(sort []domain.SortOrder) bool {
+	if len(sort) == 0 {
+		return false
+	}
+	return sort[len(sort)-1].Desc
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return " ASC"
+}
+
+func comparator(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
+
+func parseTime(s string) error {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err
+}
+
+func dueAtValue(t domain.Todo) string {
+	if t.DueAt == nil {
+		return "infinity"
+	}
+	return t.DueAt.Format(time.RFC3339Nano)
+}
+
+func deletedAtValue(t domain.Todo) string {
+	if t.DeletedAt == nil {
+		return ""
+	}
+	return t.DeletedAt.Format(time.RFC3339Nano)
+}
+
+func parseDueAt(s string) error {
+	if s == "infinity" {
+		return nil
+	}
+	return parseTime(s)
+}
+
+func parseFloat(s string) error {
+	_, err := strconv.ParseFloat(s, 64)
+	return err
+}
+
+func relevance(t domain.Todo) float64 {
+	if t.Match == nil {
+		return 0
+	}
+	return t.Match.Rank
+}
+
+func parseInt(s string) error {
+	_, err := strconv.Atoi(s)
+	return err
+}
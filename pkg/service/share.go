@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/insert/add_share.sql
+var addShareSQL string
+
+var addShareQuery = namedQuery{file: "sql/insert/add_share.sql", text: addShareSQL}
+
+//go:embed sql/delete/remove_share.sql
+var removeShareSQL string
+
+var removeShareQuery = namedQuery{file: "sql/delete/remove_share.sql", text: removeShareSQL}
+
+// ShareTodo lets the user see the todo. Sharing it again with the same user is a no-op.
+func (s *postgresService) ShareTodo(ctx context.Context, id, userID string) error {
+	_, err := s.db.ExecContext(ctx, addShareQuery, id, userID)
+	return err
+}
+
+// UnshareTodo removes the share of the todo with the user, returning
+// domain.ErrShareNotFound when there is none.
+func (s *postgresService) UnshareTodo(ctx context.Context, id, userID string) error {
+	removed, err := s.exec(ctx, removeShareQuery, id, userID)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return domain.ErrShareNotFound
+	}
+	return nil
+}
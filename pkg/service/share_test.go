@@ -0,0 +1,150 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+func TestService_GetByID_ReturnsOwnershipAndShares(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, "alice", "bob", nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, `{carol,dave}`)
+	mock.ExpectQuery("FROM todo_shares").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.GetByID(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.OwnerID == nil || *result.OwnerID != "alice" {
+		t.Errorf("expected owner alice, got %v", result.OwnerID)
+	}
+	if result.AssigneeID == nil || *result.AssigneeID != "bob" {
+		t.Errorf("expected assignee bob, got %v", result.AssigneeID)
+	}
+	if !reflect.DeepEqual(result.SharedWith, []string{"carol", "dave"}) {
+		t.Errorf("expected shares with carol and dave, got %v", result.SharedWith)
+	}
+}
+
+func TestService_Get_WithAssigneeAndVisibilityFilters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	assignee, user := "bob", "alice"
+	mock.ExpectQuery(`FROM todo_shares s`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, &assignee, &user, 10).
+		WillReturnRows(sqlmock.NewRows(listColumns))
+	svc := service.New(db)
+
+	_, err = svc.Get(context.Background(), service.Filters{AssigneeID: &assignee, VisibleTo: &user}, service.Page{Limit: 10})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_Create_WithOwnerAndAssignee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	owner, assignee := "alice", "bob"
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, owner, assignee, nil, nil, 1, fixedTime, fixedTime, nil)
+	mock.ExpectQuery("INSERT INTO todos").
+		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium, nil, nil, nil, &owner, &assignee).
+		WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Create(context.Background(), service.CreateInput{
+		Title:      validTitle,
+		Status:     domain.StatusPending,
+		Priority:   domain.PriorityMedium,
+		OwnerID:    &owner,
+		AssigneeID: &assignee,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.OwnerID == nil || *result.OwnerID != owner || result.AssigneeID == nil || *result.AssigneeID != assignee {
+		t.Errorf("expected owner %s and assignee %s, got %v and %v", owner, assignee, result.OwnerID, result.AssigneeID)
+	}
+}
+
+func TestService_Update_ClearAssignee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, "alice", nil, nil, nil, 2, fixedTime, fixedTime, nil)
+	mock.ExpectQuery(`assignee_id = CASE WHEN \$14::BOOLEAN`).
+		WithArgs(validUUID, nil, nil, nil, nil, nil, false, nil, nil, false, nil, false, nil, true).
+		WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Update(context.Background(), validUUID, service.UpdateInput{ClearAssignee: true})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.AssigneeID != nil {
+		t.Errorf("expected no assignee, got %v", *result.AssigneeID)
+	}
+}
+
+func TestService_ShareTodo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("INSERT INTO todo_shares").WithArgs(validUUID, "carol").WillReturnResult(sqlmock.NewResult(0, 1))
+	svc := service.New(db)
+
+	err = svc.ShareTodo(context.Background(), validUUID, "carol")
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_UnshareTodo_ReturnsErrShareNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("DELETE FROM todo_shares").WithArgs(validUUID, "carol").WillReturnResult(sqlmock.NewResult(0, 0))
+	svc := service.New(db)
+
+	err = svc.UnshareTodo(context.Background(), validUUID, "carol")
+
+	if !errors.Is(err, domain.ErrShareNotFound) {
+		t.Errorf("expected ErrShareNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+DELETE FROM todo_comments
+WHERE id = $2
+  AND todo_id = $1;
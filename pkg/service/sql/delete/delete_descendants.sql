@@ -0,0 +1,21 @@
+WITH RECURSIVE descendants AS (
+    SELECT id
+    FROM todos
+    WHERE parent_id = $1
+      AND deleted_at IS NULL
+    UNION
+    SELECT t.id
+    FROM todos t
+    JOIN descendants d ON t.parent_id = d.id
+    WHERE t.deleted_at IS NULL
+)
+UPDATE todos
+SET
+    deleted_at = NOW(),
+    version = version + 1
+WHERE id IN (SELECT id FROM descendants)
+RETURNING id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+    ARRAY(
+        SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+        WHERE tt.todo_id = todos.id ORDER BY g.name
+    ) AS tags;
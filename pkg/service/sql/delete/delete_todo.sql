@@ -1 +1,7 @@
-DELETE FROM todos WHERE id = $1;
+UPDATE todos
+SET
+    deleted_at = NOW(),
+    version = version + 1
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND ($2::INT IS NULL OR version = $2);
@@ -0,0 +1,13 @@
+UPDATE todos AS t
+SET
+    deleted_at = NOW(),
+    version = t.version + 1
+FROM unnest($1::UUID[], $2::INT[]) AS d(id, expected_version)
+WHERE t.id = d.id
+  AND t.deleted_at IS NULL
+  AND (d.expected_version IS NULL OR t.version = d.expected_version)
+RETURNING t.id, t.parent_id, t.title, t.description, t.status, t.priority, t.due_at, t.recurrence, t.owner_id, t.assignee_id, t.completed_at, t.deleted_at, t.version, t.created_at, t.updated_at,
+    ARRAY(
+        SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+        WHERE tt.todo_id = t.id ORDER BY g.name
+    ) AS tags;
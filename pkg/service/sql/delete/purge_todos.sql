@@ -0,0 +1,12 @@
+WITH purged AS (
+    DELETE FROM todos
+    WHERE deleted_at IS NOT NULL
+      AND deleted_at < $1
+    RETURNING id
+)
+SELECT p.id,
+    ARRAY(
+        SELECT a.storage_key FROM todo_attachments a
+        WHERE a.todo_id = p.id ORDER BY a.storage_key
+    ) AS storage_keys
+FROM purged p;
@@ -0,0 +1,3 @@
+DELETE FROM todo_dependencies
+WHERE todo_id = $1
+  AND blocker_id = $2;
@@ -0,0 +1,3 @@
+DELETE FROM todo_shares
+WHERE todo_id = $1
+  AND user_id = $2;
@@ -0,0 +1,5 @@
+DELETE FROM todo_tags AS tt
+USING tags g, unnest($1::UUID[], $2::VARCHAR[]) AS l(todo_id, name)
+WHERE tt.tag_id = g.id
+  AND tt.todo_id = l.todo_id
+  AND g.name = l.name;
@@ -0,0 +1,3 @@
+INSERT INTO todo_dependencies (todo_id, blocker_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+INSERT INTO todo_history (todo_id, action, changes, caller_app, caller_scope, caller_user)
+SELECT h.todo_id, h.action, h.changes::JSONB, h.caller_app, h.caller_scope, h.caller_user
+FROM unnest($1::UUID[], $2::VARCHAR[], $3::TEXT[], $4::VARCHAR[], $5::VARCHAR[], $6::VARCHAR[])
+    AS h(todo_id, action, changes, caller_app, caller_scope, caller_user);
@@ -0,0 +1,3 @@
+INSERT INTO todo_shares (todo_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,16 @@
+WITH links AS (
+    SELECT *
+    FROM unnest($1::UUID[], $2::VARCHAR[]) AS l(todo_id, name)
+),
+upserted AS (
+    INSERT INTO tags (name)
+    SELECT DISTINCT name
+    FROM links
+    ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+    RETURNING id, name
+)
+INSERT INTO todo_tags (todo_id, tag_id)
+SELECT l.todo_id, u.id
+FROM links l
+JOIN upserted u ON u.name = l.name
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+INSERT INTO api_keys (name, app, scopes, prefix, key_hash, user_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, name, app, scopes, prefix, created_at, revoked_at, user_id;
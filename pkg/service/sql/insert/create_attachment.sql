@@ -0,0 +1,3 @@
+INSERT INTO todo_attachments (todo_id, filename, content_type, size, storage_key)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, todo_id, filename, content_type, size, storage_key, created_at;
@@ -0,0 +1,3 @@
+INSERT INTO todo_comments (todo_id, body, author)
+VALUES ($1, $2, $3)
+RETURNING id, todo_id, body, author, created_at, updated_at;
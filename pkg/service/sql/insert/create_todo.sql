@@ -1,3 +1,4 @@
-INSERT INTO todos (title, description, status, priority)
-VALUES ($1, $2, $3, $4)
-RETURNING id, title, description, status, priority, created_at, updated_at;
+INSERT INTO todos (title, description, status, priority, due_at, completed_at, parent_id, recurrence, owner_id, assignee_id)
+VALUES ($1, $2, $3, $4, $5, CASE WHEN $3 = 'completed' THEN NOW() END, $6, $7, $8, $9)
+RETURNING id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+    '{}'::VARCHAR[] AS tags;
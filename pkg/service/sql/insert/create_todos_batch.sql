@@ -0,0 +1,8 @@
+INSERT INTO todos (title, description, status, priority, due_at, completed_at, parent_id, recurrence, owner_id, assignee_id)
+SELECT i.title, i.description, i.status, i.priority, i.due_at, CASE WHEN i.status = 'completed' THEN NOW() END, i.parent_id, i.recurrence,
+    i.owner_id, i.assignee_id
+FROM unnest($1::VARCHAR[], $2::VARCHAR[], $3::VARCHAR[], $4::VARCHAR[], $5::TIMESTAMP[], $6::UUID[], $7::VARCHAR[], $8::VARCHAR[], $9::VARCHAR[])
+    WITH ORDINALITY AS i(title, description, status, priority, due_at, parent_id, recurrence, owner_id, assignee_id, ord)
+ORDER BY i.ord
+RETURNING id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+    '{}'::VARCHAR[] AS tags;
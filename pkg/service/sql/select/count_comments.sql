@@ -0,0 +1,3 @@
+SELECT COUNT(*)
+FROM todo_comments
+WHERE todo_id = $1;
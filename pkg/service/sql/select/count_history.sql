@@ -0,0 +1,3 @@
+SELECT COUNT(*)
+FROM todo_history
+WHERE todo_id = $1;
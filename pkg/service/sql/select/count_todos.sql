@@ -0,0 +1,32 @@
+SELECT COUNT(*)
+FROM todos
+WHERE ($1::VARCHAR[] IS NULL OR status = ANY($1))
+  AND ($2::VARCHAR[] IS NULL OR priority = ANY($2))
+  AND ($3::TEXT IS NULL OR search_vector @@ websearch_to_tsquery('english', $3))
+  AND ($4::TIMESTAMP IS NULL OR due_at < $4)
+  AND ($5::TIMESTAMP IS NULL OR due_at >= $5)
+  AND ($6::BOOLEAN IS NULL OR $6 = (due_at IS NOT NULL AND due_at < NOW() AND status <> 'completed'))
+  AND (deleted_at IS NOT NULL) = $7::BOOLEAN
+  AND ($8::UUID IS NULL OR parent_id = $8)
+  AND ($9::VARCHAR[] IS NULL OR (
+      SELECT COUNT(*)
+      FROM todo_tags tt
+      JOIN tags g ON g.id = tt.tag_id
+      WHERE tt.todo_id = todos.id
+        AND g.name = ANY($9)
+  ) >= CASE WHEN $10::BOOLEAN THEN cardinality($9) ELSE 1 END)
+  AND ($11::BOOLEAN IS NULL OR $11 = EXISTS (
+      SELECT 1
+      FROM todo_dependencies d
+      JOIN todos b ON b.id = d.blocker_id
+      WHERE d.todo_id = todos.id
+        AND b.deleted_at IS NULL
+        AND b.status <> 'completed'
+  ))
+  AND ($12::VARCHAR IS NULL OR assignee_id = $12)
+  AND ($13::VARCHAR IS NULL OR owner_id IS NULL OR owner_id = $13 OR assignee_id = $13 OR EXISTS (
+      SELECT 1
+      FROM todo_shares s
+      WHERE s.todo_id = todos.id
+        AND s.user_id = $13
+  ));
@@ -0,0 +1,11 @@
+WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id
+    FROM todos
+    WHERE id = $1
+    UNION
+    SELECT t.id, t.parent_id
+    FROM todos t
+    JOIN ancestors a ON t.id = a.parent_id
+)
+SELECT id
+FROM ancestors;
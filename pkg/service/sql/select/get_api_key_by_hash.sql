@@ -0,0 +1,4 @@
+SELECT id, name, app, scopes, prefix, created_at, revoked_at, user_id
+FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL;
@@ -0,0 +1,3 @@
+SELECT id, name, app, scopes, prefix, created_at, revoked_at, user_id
+FROM api_keys
+ORDER BY created_at, id;
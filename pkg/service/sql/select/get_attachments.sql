@@ -0,0 +1,4 @@
+SELECT id, todo_id, filename, content_type, size, storage_key, created_at
+FROM todo_attachments
+WHERE todo_id = $1
+ORDER BY created_at, id;
@@ -0,0 +1,9 @@
+WITH RECURSIVE blockers AS (
+    SELECT $1::UUID AS id
+    UNION
+    SELECT d.blocker_id
+    FROM todo_dependencies d
+    JOIN blockers b ON d.todo_id = b.id
+)
+SELECT id
+FROM blockers;
@@ -0,0 +1,11 @@
+SELECT b.id, b.parent_id, b.title, b.description, b.status, b.priority, b.due_at, b.recurrence, b.owner_id, b.assignee_id, b.completed_at, b.deleted_at, b.version, b.created_at, b.updated_at,
+       ARRAY(
+           SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+           WHERE tt.todo_id = b.id ORDER BY g.name
+       ) AS tags,
+       ARRAY(SELECT s.user_id FROM todo_shares s WHERE s.todo_id = b.id ORDER BY s.user_id) AS shared_with
+FROM todo_dependencies d
+JOIN todos b ON b.id = d.blocker_id
+WHERE d.todo_id = $1
+  AND b.deleted_at IS NULL
+ORDER BY d.created_at, b.id;
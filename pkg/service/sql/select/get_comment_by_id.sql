@@ -0,0 +1,4 @@
+SELECT id, todo_id, body, author, created_at, updated_at
+FROM todo_comments
+WHERE todo_id = $1
+  AND id = $2;
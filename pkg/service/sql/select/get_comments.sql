@@ -0,0 +1,6 @@
+SELECT id, todo_id, body, author, created_at, updated_at
+FROM todo_comments
+WHERE todo_id = $1
+  AND ($2::TIMESTAMP IS NULL OR (created_at, id) > ($2::TIMESTAMP, $3::UUID))
+ORDER BY created_at, id
+LIMIT $4;
@@ -0,0 +1,6 @@
+SELECT id, todo_id, action, changes, caller_app, caller_scope, caller_user, created_at
+FROM todo_history
+WHERE todo_id = $1
+  AND ($2::TIMESTAMP IS NULL OR (created_at, id) < ($2::TIMESTAMP, $3::UUID))
+ORDER BY created_at DESC, id DESC
+LIMIT $4;
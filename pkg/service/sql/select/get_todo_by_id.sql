@@ -1,3 +1,26 @@
-SELECT id, title, description, status, priority, created_at, updated_at
+SELECT id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+       ARRAY(
+           SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+           WHERE tt.todo_id = todos.id ORDER BY g.name
+       ) AS tags,
+       subtasks.total AS subtask_count,
+       subtasks.completed AS completed_subtask_count,
+       dependencies.total AS dependency_count,
+       dependencies.open AS open_dependency_count,
+       ARRAY(SELECT s.user_id FROM todo_shares s WHERE s.todo_id = todos.id ORDER BY s.user_id) AS shared_with
 FROM todos
-WHERE id = $1;
+LEFT JOIN LATERAL (
+    SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE c.status = 'completed') AS completed
+    FROM todos c
+    WHERE c.parent_id = todos.id
+      AND c.deleted_at IS NULL
+) subtasks ON TRUE
+LEFT JOIN LATERAL (
+    SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE b.status <> 'completed') AS open
+    FROM todo_dependencies d
+    JOIN todos b ON b.id = d.blocker_id
+    WHERE d.todo_id = todos.id
+      AND b.deleted_at IS NULL
+) dependencies ON TRUE
+WHERE id = $1
+  AND deleted_at IS NULL;
@@ -1,5 +1,56 @@
-SELECT id, title, description, status, priority, created_at, updated_at
+SELECT id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+       ARRAY(
+           SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+           WHERE tt.todo_id = todos.id ORDER BY g.name
+       ) AS tags,
+       subtasks.total AS subtask_count,
+       subtasks.completed AS completed_subtask_count,
+       dependencies.total AS dependency_count,
+       dependencies.open AS open_dependency_count,
+       ts_rank(search_vector, websearch_to_tsquery('english', $3)) AS rank,
+       ts_headline('english', title, websearch_to_tsquery('english', $3), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=TRUE') AS title_highlight,
+       ts_headline('english', description, websearch_to_tsquery('english', $3), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS description_highlight
 FROM todos
-WHERE ($1::VARCHAR IS NULL OR status = $1)
-  AND ($2::VARCHAR IS NULL OR priority = $2)
-ORDER BY created_at DESC;
+LEFT JOIN LATERAL (
+    SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE c.status = 'completed') AS completed
+    FROM todos c
+    WHERE c.parent_id = todos.id
+      AND c.deleted_at IS NULL
+) subtasks ON TRUE
+LEFT JOIN LATERAL (
+    SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE b.status <> 'completed') AS open
+    FROM todo_dependencies d
+    JOIN todos b ON b.id = d.blocker_id
+    WHERE d.todo_id = todos.id
+      AND b.deleted_at IS NULL
+) dependencies ON TRUE
+WHERE ($1::VARCHAR[] IS NULL OR status = ANY($1))
+  AND ($2::VARCHAR[] IS NULL OR priority = ANY($2))
+  AND ($3::TEXT IS NULL OR search_vector @@ websearch_to_tsquery('english', $3))
+  AND ($4::TIMESTAMP IS NULL OR due_at < $4)
+  AND ($5::TIMESTAMP IS NULL OR due_at >= $5)
+  AND ($6::BOOLEAN IS NULL OR $6 = (due_at IS NOT NULL AND due_at < NOW() AND status <> 'completed'))
+  AND (deleted_at IS NOT NULL) = $7::BOOLEAN
+  AND ($8::UUID IS NULL OR parent_id = $8)
+  AND ($9::VARCHAR[] IS NULL OR (
+      SELECT COUNT(*)
+      FROM todo_tags tt
+      JOIN tags g ON g.id = tt.tag_id
+      WHERE tt.todo_id = todos.id
+        AND g.name = ANY($9)
+  ) >= CASE WHEN $10::BOOLEAN THEN cardinality($9) ELSE 1 END)
+  AND ($11::BOOLEAN IS NULL OR $11 = EXISTS (
+      SELECT 1
+      FROM todo_dependencies d
+      JOIN todos b ON b.id = d.blocker_id
+      WHERE d.todo_id = todos.id
+        AND b.deleted_at IS NULL
+        AND b.status <> 'completed'
+  ))
+  AND ($12::VARCHAR IS NULL OR assignee_id = $12)
+  AND ($13::VARCHAR IS NULL OR owner_id IS NULL OR owner_id = $13 OR assignee_id = $13 OR EXISTS (
+      SELECT 1
+      FROM todo_shares s
+      WHERE s.todo_id = todos.id
+        AND s.user_id = $13
+  ))
@@ -0,0 +1,8 @@
+SELECT EXISTS (
+    SELECT 1
+    FROM todos t
+    JOIN todos p ON p.id = t.parent_id
+    WHERE t.id = $1
+      AND t.deleted_at IS NOT NULL
+      AND p.deleted_at IS NOT NULL
+);
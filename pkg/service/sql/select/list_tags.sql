@@ -0,0 +1,13 @@
+SELECT g.name, COUNT(*) AS usage_count
+FROM tags g
+JOIN todo_tags tt ON tt.tag_id = g.id
+JOIN todos t ON t.id = tt.todo_id
+WHERE t.deleted_at IS NULL
+  AND ($1::VARCHAR IS NULL OR t.owner_id IS NULL OR t.owner_id = $1 OR t.assignee_id = $1 OR EXISTS (
+      SELECT 1
+      FROM todo_shares s
+      WHERE s.todo_id = t.id
+        AND s.user_id = $1
+  ))
+GROUP BY g.name
+ORDER BY usage_count DESC, g.name;
@@ -0,0 +1,6 @@
+SELECT id
+FROM todos
+WHERE id = ANY($1::UUID[])
+  AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE;
@@ -0,0 +1,11 @@
+UPDATE todos
+SET
+    parent_id = NULL,
+    version = version + 1,
+    updated_at = NOW()
+WHERE parent_id = $1
+RETURNING id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+    ARRAY(
+        SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+        WHERE tt.todo_id = todos.id ORDER BY g.name
+    ) AS tags;
@@ -0,0 +1,27 @@
+WITH RECURSIVE trashed AS (
+    SELECT deleted_at
+    FROM todos
+    WHERE id = $1
+      AND deleted_at IS NOT NULL
+), descendants AS (
+    SELECT t.id
+    FROM todos t
+    JOIN trashed ON t.deleted_at = trashed.deleted_at
+    WHERE t.parent_id = $1
+    UNION
+    SELECT t.id
+    FROM todos t
+    JOIN descendants d ON t.parent_id = d.id
+    JOIN trashed ON t.deleted_at = trashed.deleted_at
+)
+UPDATE todos
+SET
+    deleted_at = NULL,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id IN (SELECT id FROM descendants)
+RETURNING id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+    ARRAY(
+        SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+        WHERE tt.todo_id = todos.id ORDER BY g.name
+    ) AS tags;
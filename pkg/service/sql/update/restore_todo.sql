@@ -0,0 +1,17 @@
+UPDATE todos
+SET
+    deleted_at = NULL,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM todos p
+      WHERE p.id = todos.parent_id
+        AND p.deleted_at IS NOT NULL
+  )
+RETURNING id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+    ARRAY(
+        SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+        WHERE tt.todo_id = todos.id ORDER BY g.name
+    ) AS tags;
@@ -0,0 +1,7 @@
+UPDATE todo_comments
+SET
+    body = $3,
+    updated_at = NOW()
+WHERE id = $2
+  AND todo_id = $1
+RETURNING id, todo_id, body, author, created_at, updated_at;
@@ -4,6 +4,22 @@ SET
     description = COALESCE($3, description),
     status = COALESCE($4, status),
     priority = COALESCE($5, priority),
+    due_at = CASE WHEN $7::BOOLEAN THEN NULL ELSE COALESCE($6, due_at) END,
+    completed_at = CASE
+        WHEN COALESCE($4, status) <> 'completed' THEN NULL
+        WHEN status <> 'completed' THEN NOW()
+        ELSE completed_at
+    END,
+    parent_id = CASE WHEN $10::BOOLEAN THEN NULL ELSE COALESCE($9::UUID, parent_id) END,
+    recurrence = CASE WHEN $12::BOOLEAN THEN NULL ELSE COALESCE($11, recurrence) END,
+    assignee_id = CASE WHEN $14::BOOLEAN THEN NULL ELSE COALESCE($13, assignee_id) END,
+    version = version + 1,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, description, status, priority, created_at, updated_at;
+  AND deleted_at IS NULL
+  AND ($8::INT IS NULL OR version = $8)
+RETURNING id, parent_id, title, description, status, priority, due_at, recurrence, owner_id, assignee_id, completed_at, deleted_at, version, created_at, updated_at,
+    ARRAY(
+        SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+        WHERE tt.todo_id = todos.id ORDER BY g.name
+    ) AS tags;
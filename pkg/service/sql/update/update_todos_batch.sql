@@ -0,0 +1,29 @@
+UPDATE todos AS t
+SET
+    title = COALESCE(u.title, t.title),
+    description = COALESCE(u.description, t.description),
+    status = COALESCE(u.status, t.status),
+    priority = COALESCE(u.priority, t.priority),
+    due_at = CASE WHEN u.clear_due_at THEN NULL ELSE COALESCE(u.due_at, t.due_at) END,
+    completed_at = CASE
+        WHEN COALESCE(u.status, t.status) <> 'completed' THEN NULL
+        WHEN t.status <> 'completed' THEN NOW()
+        ELSE t.completed_at
+    END,
+    parent_id = CASE WHEN u.clear_parent THEN NULL ELSE COALESCE(u.parent_id, t.parent_id) END,
+    recurrence = CASE WHEN u.clear_recurrence THEN NULL ELSE COALESCE(u.recurrence, t.recurrence) END,
+    assignee_id = CASE WHEN u.clear_assignee THEN NULL ELSE COALESCE(u.assignee_id, t.assignee_id) END,
+    version = t.version + 1,
+    updated_at = NOW()
+FROM unnest($1::UUID[], $2::VARCHAR[], $3::VARCHAR[], $4::VARCHAR[], $5::VARCHAR[], $6::TIMESTAMP[], $7::BOOLEAN[], $8::INT[], $9::UUID[], $10::BOOLEAN[],
+        $11::VARCHAR[], $12::BOOLEAN[], $13::VARCHAR[], $14::BOOLEAN[])
+    AS u(id, title, description, status, priority, due_at, clear_due_at, expected_version, parent_id, clear_parent, recurrence, clear_recurrence,
+        assignee_id, clear_assignee)
+WHERE t.id = u.id
+  AND t.deleted_at IS NULL
+  AND (u.expected_version IS NULL OR t.version = u.expected_version)
+RETURNING t.id, t.parent_id, t.title, t.description, t.status, t.priority, t.due_at, t.recurrence, t.owner_id, t.assignee_id, t.completed_at, t.deleted_at, t.version, t.created_at, t.updated_at,
+    ARRAY(
+        SELECT g.name FROM todo_tags tt JOIN tags g ON g.id = tt.tag_id
+        WHERE tt.todo_id = t.id ORDER BY g.name
+    ) AS tags;
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/lib/pq"
+
+	"todo-api/pkg/domain"
+)
+
+//go:embed sql/insert/add_todo_tags.sql
+var addTodoTagsSQL string
+
+var addTodoTagsQuery = namedQuery{file: "sql/insert/add_todo_tags.sql", text: addTodoTagsSQL}
+
+//go:embed sql/delete/remove_todo_tags.sql
+var removeTodoTagsSQL string
+
+var removeTodoTagsQuery = namedQuery{file: "sql/delete/remove_todo_tags.sql", text: removeTodoTagsSQL}
+
+//go:embed sql/select/list_tags.sql
+var listTagsSQL string
+
+var listTagsQuery = namedQuery{file: "sql/select/list_tags.sql", text: listTagsSQL}
+
+type (
+	// TodoTags names tags of one todo for AddTags and RemoveTags.
+	TodoTags struct {
+		TodoID string
+		Names  []string
+	}
+)
+
+// AddTags attaches the tags to their todos with a single statement, creating the
+// tags that do not exist yet. Tags a todo already carries are left as they are.
+func (s *postgresService) AddTags(ctx context.Context, items []TodoTags) error {
+	todoIDs, names := flattenTodoTags(items)
+	if len(todoIDs) == 0 {
+		return nil
+	}
+
+	_, err := s.db.ExecContext(ctx, addTodoTagsQuery, todoIDs, names)
+	return err
+}
+
+// RemoveTags detaches the tags from their todos with a single statement. Tags a todo
+// does not carry are ignored.
+func (s *postgresService) RemoveTags(ctx context.Context, items []TodoTags) error {
+	todoIDs, names := flattenTodoTags(items)
+	if len(todoIDs) == 0 {
+		return nil
+	}
+
+	_, err := s.db.ExecContext(ctx, removeTodoTagsQuery, todoIDs, names)
+	return err
+}
+
+// ListTags returns the tags carried by at least one live todo, most used first. When
+// visibleTo is set only the todos that user can see are counted (see domain.Todo.VisibleTo).
+func (s *postgresService) ListTags(ctx context.Context, visibleTo *string) ([]domain.TagUsage, error) {
+	rows, err := s.db.QueryContext(ctx, listTagsQuery, visibleTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []domain.TagUsage
+	for rows.Next() {
+		var tag domain.TagUsage
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// flattenTodoTags turns the items into the parallel (todo_id, name) arrays unnested by the queries.
+func flattenTodoTags(items []TodoTags) (pq.StringArray, pq.StringArray) {
+	var todoIDs, names pq.StringArray
+	for _, item := range items {
+		for _, name := range item.Names {
+			todoIDs = append(todoIDs, item.TodoID)
+			names = append(names, name)
+		}
+	}
+	return todoIDs, names
+}
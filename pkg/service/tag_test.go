@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+func TestService_GetByID_ReturnsTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, "{backend,q3}", 0, 0, 0, 0, nil)
+	mock.ExpectQuery("FROM todo_tags").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.GetByID(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"backend", "q3"}
+	if !reflect.DeepEqual(result.Tags, expected) {
+		t.Errorf("expected tags %v, got %v", expected, result.Tags)
+	}
+}
+
+func TestService_Get_WithTagFilter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`g.name = ANY\(\$9\)`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, `{"backend","q3"}`, true, nil, nil, nil, 10).
+		WillReturnRows(sqlmock.NewRows(listColumns))
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, `{"backend","q3"}`, true, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	svc := service.New(db)
+	filters := service.Filters{Tags: []string{"backend", "q3"}, MatchAllTags: true}
+
+	if _, err := svc.Get(context.Background(), filters, service.Page{Limit: 10}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if _, err := svc.Count(context.Background(), filters); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_AddTags_FlattensItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("INSERT INTO todo_tags").
+		WithArgs(`{"`+validUUID+`","`+validUUID+`","`+otherUUID+`"}`, `{"backend","q3","backend"}`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	svc := service.New(db)
+
+	err = svc.AddTags(context.Background(), []service.TodoTags{
+		{TodoID: validUUID, Names: []string{"backend", "q3"}},
+		{TodoID: otherUUID, Names: []string{"backend"}},
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_RemoveTags_SkipsEmptyItems(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	svc := service.New(db)
+
+	err = svc.RemoveTags(context.Background(), []service.TodoTags{{TodoID: validUUID}})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestService_RemoveTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("DELETE FROM todo_tags").
+		WithArgs(`{"`+validUUID+`"}`, `{"q3"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	svc := service.New(db)
+
+	err = svc.RemoveTags(context.Background(), []service.TodoTags{{TodoID: validUUID, Names: []string{"q3"}}})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_ListTags_ReturnsUsageCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("FROM tags").WithArgs(nil).WillReturnRows(sqlmock.NewRows([]string{"name", "usage_count"}).
+		AddRow("backend", 5).
+		AddRow("q3", 2))
+	svc := service.New(db)
+
+	result, err := svc.ListTags(context.Background(), nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []domain.TagUsage{{Name: "backend", Count: 5}, {Name: "q3", Count: 2}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestService_ListTags_CountsOnlyTheTodosVisibleToTheUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`owner_id IS NULL OR t.owner_id = \$1 OR t.assignee_id = \$1`).WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "usage_count"}).AddRow("backend", 1))
+	svc := service.New(db)
+	user := "alice"
+
+	result, err := svc.ListTags(context.Background(), &user)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 1 || result[0].Count != 1 {
+		t.Errorf("expected backend used once, got %v", result)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
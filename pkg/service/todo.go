@@ -4,33 +4,131 @@ import (
 	"context"
 	"database/sql"
 	_ "embed"
+	"time"
+
+	"github.com/lib/pq"
 
 	"todo-api/pkg/domain"
 )
 
 //go:embed sql/select/get_todos.sql
-var getTodosQuery string
+var getTodosSQL string
+
+var getTodosQuery = namedQuery{file: "sql/select/get_todos.sql", text: getTodosSQL}
+
+//go:embed sql/select/count_todos.sql
+var countTodosSQL string
+
+var countTodosQuery = namedQuery{file: "sql/select/count_todos.sql", text: countTodosSQL}
 
 //go:embed sql/select/get_todo_by_id.sql
-var getTodoByIDQuery string
+var getTodoByIDSQL string
+
+var getTodoByIDQuery = namedQuery{file: "sql/select/get_todo_by_id.sql", text: getTodoByIDSQL}
+
+//go:embed sql/select/lock_todos.sql
+var lockTodosSQL string
+
+var lockTodosQuery = namedQuery{file: "sql/select/lock_todos.sql", text: lockTodosSQL}
 
 //go:embed sql/insert/create_todo.sql
-var createTodoQuery string
+var createTodoSQL string
+
+var createTodoQuery = namedQuery{file: "sql/insert/create_todo.sql", text: createTodoSQL}
 
 //go:embed sql/update/update_todo.sql
-var updateTodoQuery string
+var updateTodoSQL string
+
+var updateTodoQuery = namedQuery{file: "sql/update/update_todo.sql", text: updateTodoSQL}
 
 //go:embed sql/delete/delete_todo.sql
-var deleteTodoQuery string
+var deleteTodoSQL string
+
+var deleteTodoQuery = namedQuery{file: "sql/delete/delete_todo.sql", text: deleteTodoSQL}
+
+//go:embed sql/update/restore_todo.sql
+var restoreTodoSQL string
+
+var restoreTodoQuery = namedQuery{file: "sql/update/restore_todo.sql", text: restoreTodoSQL}
+
+//go:embed sql/update/restore_descendants.sql
+var restoreDescendantsSQL string
+
+var restoreDescendantsQuery = namedQuery{file: "sql/update/restore_descendants.sql", text: restoreDescendantsSQL}
+
+//go:embed sql/select/is_parent_trashed.sql
+var isParentTrashedSQL string
+
+var isParentTrashedQuery = namedQuery{file: "sql/select/is_parent_trashed.sql", text: isParentTrashedSQL}
+
+//go:embed sql/delete/purge_todos.sql
+var purgeTodosSQL string
+
+var purgeTodosQuery = namedQuery{file: "sql/delete/purge_todos.sql", text: purgeTodosSQL}
+
+//go:embed sql/select/get_ancestor_ids.sql
+var getAncestorIDsSQL string
+
+var getAncestorIDsQuery = namedQuery{file: "sql/select/get_ancestor_ids.sql", text: getAncestorIDsSQL}
+
+//go:embed sql/delete/delete_descendants.sql
+var deleteDescendantsSQL string
+
+var deleteDescendantsQuery = namedQuery{file: "sql/delete/delete_descendants.sql", text: deleteDescendantsSQL}
+
+//go:embed sql/update/orphan_children.sql
+var orphanChildrenSQL string
+
+var orphanChildrenQuery = namedQuery{file: "sql/update/orphan_children.sql", text: orphanChildrenSQL}
 
 type (
+	// Filters narrows a listing. Empty slices match every value and an empty
+	// Query disables full-text search. Deleted lists the trash instead of live todos
+	// and ParentID restricts the listing to the direct subtasks of a todo. Tags keeps
+	// the todos carrying any of the tags, or all of them when MatchAllTags is set.
+	// Blocked keeps the todos that do (or do not) depend on a todo not completed yet.
+	// AssigneeID keeps the todos assigned to that user and VisibleTo the todos the
+	// user can see (see domain.Todo.VisibleTo).
 	Filters struct {
-		Status   *domain.Status
-		Priority *domain.Priority
+		Statuses     []domain.Status
+		Priorities   []domain.Priority
+		Query        string
+		DueBefore    *time.Time
+		DueAfter     *time.Time
+		Overdue      *bool
+		Deleted      bool
+		ParentID     *string
+		Tags         []string
+		MatchAllTags bool
+		Blocked      *bool
+		AssigneeID   *string
+		VisibleTo    *string
+	}
+
+	// Page bounds a listing. Rows are returned ordered by Sort (with id as the final
+	// tie-breaker), starting strictly after the After cursor when it is set.
+	Page struct {
+		Limit int
+		Sort  []domain.SortOrder
+		After *Cursor
+	}
+
+	// Cursor is the keyset position of the last row of a previous page: the
+	// value of every sort key (see NewCursor) plus the row id.
+	Cursor struct {
+		Values []string
+		ID     string
+	}
+
+	// querier runs the embedded queries; tracedQuerier implements it over *sql.DB or *sql.Tx.
+	querier interface {
+		ExecContext(ctx context.Context, query namedQuery, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query namedQuery, args ...any) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query namedQuery, args ...any) *sql.Row
 	}
 
 	postgresService struct {
-		db *sql.DB
+		db querier
 	}
 
 	CreateInput struct {
@@ -38,41 +136,156 @@ type (
 		Description *string
 		Status      domain.Status
 		Priority    domain.Priority
+		DueAt       *time.Time
+		ParentID    *string
+		Recurrence  *domain.Recurrence
+		OwnerID     *string
+		AssigneeID  *string
 	}
 
+	// UpdateInput holds the fields to change; nil fields are left untouched.
+	// ClearDueAt removes the due date and takes precedence over DueAt, and
+	// ClearParent likewise turns the todo back into a top-level one, as
+	// ClearRecurrence stops it from recurring and ClearAssignee unassigns it.
+	// When ExpectedVersion is set the update only applies to that version of the todo.
 	UpdateInput struct {
-		Title       *string
-		Description *string
-		Status      *domain.Status
-		Priority    *domain.Priority
+		Title           *string
+		Description     *string
+		Status          *domain.Status
+		Priority        *domain.Priority
+		DueAt           *time.Time
+		ClearDueAt      bool
+		ParentID        *string
+		ClearParent     bool
+		Recurrence      *domain.Recurrence
+		ClearRecurrence bool
+		AssigneeID      *string
+		ClearAssignee   bool
+		ExpectedVersion *int
+	}
+
+	// PurgeResult counts the todos Purge removed and lists the storage keys of their
+	// attachments, whose blobs are left in the blob store for the caller to delete.
+	PurgeResult struct {
+		Purged      int
+		StorageKeys []string
+	}
+
+	// DeleteInput guards a delete; when ExpectedVersion is set only that version is moved to the trash.
+	DeleteInput struct {
+		ExpectedVersion *int
 	}
 
 	Todo interface {
-		Get(ctx context.Context, filters Filters) ([]domain.Todo, error)
+		Get(ctx context.Context, filters Filters, page Page) ([]domain.Todo, error)
+		Count(ctx context.Context, filters Filters) (int, error)
 		GetByID(ctx context.Context, id string) (domain.Todo, error)
+		// Lock locks the rows of the live todos until the end of the transaction, so that
+		// they can be read and updated without racing concurrent writes. Missing todos
+		// are ignored. Outside of a transaction the locks are released at once.
+		Lock(ctx context.Context, ids []string) error
 		Create(ctx context.Context, input CreateInput) (domain.Todo, error)
 		Update(ctx context.Context, id string, input UpdateInput) (domain.Todo, error)
-		Delete(ctx context.Context, id string) error
+		Delete(ctx context.Context, id string, input DeleteInput) error
+		// Restore takes the todo out of the trash, unless its parent is still in it, which
+		// fails with domain.ErrParentTrashed.
+		Restore(ctx context.Context, id string) (domain.Todo, error)
+		Purge(ctx context.Context, deletedBefore time.Time) (PurgeResult, error)
+
+		// GetAncestorIDs returns the id of the todo followed by the ids of its parent chain,
+		// including trashed todos, in no particular order.
+		GetAncestorIDs(ctx context.Context, id string) ([]string, error)
+		// DeleteDescendants moves every live subtask of the todo, recursively, to the trash
+		// and returns them.
+		DeleteDescendants(ctx context.Context, id string) ([]domain.Todo, error)
+		// RestoreDescendants takes the subtasks cascaded into the trash with the todo back
+		// out of it, recursively, and returns them restored. It must run before the todo is
+		// restored: the cascaded subtasks are told apart by sharing the deleted_at of the
+		// todo, as they were trashed in the same transaction, so subtasks trashed on their
+		// own stay in the trash.
+		RestoreDescendants(ctx context.Context, id string) ([]domain.Todo, error)
+		// OrphanChildren detaches the direct subtasks of the todo, trashed ones included,
+		// and returns them detached.
+		OrphanChildren(ctx context.Context, id string) ([]domain.Todo, error)
+
+		// GetBlockerIDs returns the id of the todo followed by the ids of every todo it
+		// depends on, directly or not, including trashed todos, in no particular order.
+		GetBlockerIDs(ctx context.Context, id string) ([]string, error)
+		// GetBlockers returns the live todos the todo directly depends on, with their shares,
+		// oldest dependency first.
+		GetBlockers(ctx context.Context, id string) ([]domain.Todo, error)
+		AddDependency(ctx context.Context, id, blockerID string) error
+		RemoveDependency(ctx context.Context, id, blockerID string) error
+
+		ShareTodo(ctx context.Context, id, userID string) error
+		UnshareTodo(ctx context.Context, id, userID string) error
+
+		AddTags(ctx context.Context, items []TodoTags) error
+		RemoveTags(ctx context.Context, items []TodoTags) error
+		ListTags(ctx context.Context, visibleTo *string) ([]domain.TagUsage, error)
+
+		// AddHistory records the entries with a single statement; their ID and CreatedAt are ignored.
+		AddHistory(ctx context.Context, entries []domain.HistoryEntry) error
+		// GetHistory returns a page of the history of the todo, newest entry first.
+		GetHistory(ctx context.Context, todoID string, page HistoryPage) ([]domain.HistoryEntry, error)
+		CountHistory(ctx context.Context, todoID string) (int, error)
+
+		GetByIDs(ctx context.Context, ids []string) ([]domain.Todo, error)
+		CreateBatch(ctx context.Context, inputs []CreateInput) ([]domain.Todo, error)
+		UpdateBatch(ctx context.Context, items []BatchUpdate) ([]BatchResult, error)
+		DeleteBatch(ctx context.Context, items []BatchDelete) ([]BatchResult, error)
+
+		// Transaction runs fn with a Todo bound to a single database transaction, committing
+		// when fn returns nil and rolling back otherwise. Nested calls reuse the outer transaction.
+		Transaction(ctx context.Context, fn func(Todo) error) error
 	}
 )
 
 func New(db *sql.DB) Todo {
-	return &postgresService{db: db}
+	return &postgresService{db: newTracedQuerier(db)}
 }
 
-func (s *postgresService) Get(ctx context.Context, filters Filters) ([]domain.Todo, error) {
-	var statusFilter, priorityFilter *string
+func (s *postgresService) Transaction(ctx context.Context, fn func(Todo) error) error {
+	db, ok := unwrapDB(s.db)
+	if !ok {
+		return fn(s)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&postgresService{db: &tracedQuerier{db: tx}}); err != nil {
+		_ = tx.Rollback() // safe mute, the error from fn is the one worth reporting
+		return err
+	}
+
+	return tx.Commit()
+}
 
-	if filters.Status != nil {
-		v := string(*filters.Status)
-		statusFilter = &v
+func (s *postgresService) Get(ctx context.Context, filters Filters, page Page) ([]domain.Todo, error) {
+	sort := page.Sort
+	if len(sort) == 0 {
+		sort = domain.DefaultSort
+		if filters.Query != "" {
+			sort = domain.RelevanceSort
+		}
 	}
-	if filters.Priority != nil {
-		v := string(*filters.Priority)
-		priorityFilter = &v
+
+	args := filterArgs(filters)
+	if page.After != nil {
+		if err := validateCursor(*page.After, sort); err != nil {
+			return nil, err
+		}
+		for _, v := range page.After.Values {
+			args = append(args, v)
+		}
+		args = append(args, page.After.ID)
 	}
+	args = append(args, page.Limit)
 
-	rows, err := s.db.QueryContext(ctx, getTodosQuery, statusFilter, priorityFilter)
+	rows, err := s.db.QueryContext(ctx, buildGetTodosQuery(sort, page.After != nil), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,24 +293,24 @@ func (s *postgresService) Get(ctx context.Context, filters Filters) ([]domain.To
 
 	var todos []domain.Todo
 	for rows.Next() {
-		var todo domain.Todo
-		var description sql.NullString
-
-		err := rows.Scan(
-			&todo.ID,
-			&todo.Title,
-			&description,
-			&todo.Status,
-			&todo.Priority,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
+		var subtasks domain.Subtasks
+		var dependencies domain.Dependencies
+		var titleHighlight, descriptionHighlight sql.NullString
+		var rank sql.NullFloat64
+
+		todo, err := scanTodo(rows, &subtasks.Total, &subtasks.Completed, &dependencies.Total, &dependencies.Open, &rank, &titleHighlight, &descriptionHighlight)
 		if err != nil {
 			return nil, err
 		}
-
-		if description.Valid {
-			todo.Description = description.String
+		todo.Subtasks = &subtasks
+		todo.Dependencies = &dependencies
+
+		if rank.Valid {
+			todo.Match = &domain.SearchMatch{
+				Rank:                 rank.Float64,
+				TitleHighlight:       titleHighlight.String,
+				DescriptionHighlight: descriptionHighlight.String,
+			}
 		}
 
 		todos = append(todos, todo)
@@ -106,42 +319,51 @@ func (s *postgresService) Get(ctx context.Context, filters Filters) ([]domain.To
 	return todos, rows.Err()
 }
 
+func (s *postgresService) Count(ctx context.Context, filters Filters) (int, error) {
+	var total int
+	err := s.db.QueryRowContext(ctx, countTodosQuery, filterArgs(filters)...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
 func (s *postgresService) GetByID(ctx context.Context, id string) (domain.Todo, error) {
 	row := s.db.QueryRowContext(ctx, getTodoByIDQuery, id)
 
-	var todo domain.Todo
-	var description sql.NullString
-
-	err := row.Scan(
-		&todo.ID,
-		&todo.Title,
-		&description,
-		&todo.Status,
-		&todo.Priority,
-		&todo.CreatedAt,
-		&todo.UpdatedAt,
-	)
+	var subtasks domain.Subtasks
+	var dependencies domain.Dependencies
+	var sharedWith pq.StringArray
+	todo, err := scanTodo(row, &subtasks.Total, &subtasks.Completed, &dependencies.Total, &dependencies.Open, &sharedWith)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Todo{}, domain.ErrTodoNotFound
 		}
 		return domain.Todo{}, err
 	}
-
-	if description.Valid {
-		todo.Description = description.String
-	}
+	todo.Subtasks = &subtasks
+	todo.Dependencies = &dependencies
+	todo.SharedWith = sharedWith
 
 	return todo, nil
 }
 
+func (s *postgresService) Lock(ctx context.Context, ids []string) error {
+	_, err := s.db.ExecContext(ctx, lockTodosQuery, pq.Array(ids))
+	return err
+}
+
 func (s *postgresService) Create(ctx context.Context, input CreateInput) (domain.Todo, error) {
-	var todo domain.Todo
 	var description sql.NullString
+	var dueAt sql.NullTime
 
 	if input.Description != nil {
 		description = sql.NullString{String: *input.Description, Valid: true}
 	}
+	if input.DueAt != nil {
+		dueAt = sql.NullTime{Time: *input.DueAt, Valid: true}
+	}
 
 	row := s.db.QueryRowContext(
 		ctx,
@@ -150,27 +372,14 @@ func (s *postgresService) Create(ctx context.Context, input CreateInput) (domain
 		description,
 		input.Status,
 		input.Priority,
+		dueAt,
+		input.ParentID,
+		nullRecurrence(input.Recurrence),
+		input.OwnerID,
+		input.AssigneeID,
 	)
 
-	var descResult sql.NullString
-	err := row.Scan(
-		&todo.ID,
-		&todo.Title,
-		&descResult,
-		&todo.Status,
-		&todo.Priority,
-		&todo.CreatedAt,
-		&todo.UpdatedAt,
-	)
-	if err != nil {
-		return domain.Todo{}, err
-	}
-
-	if descResult.Valid {
-		todo.Description = descResult.String
-	}
-
-	return todo, nil
+	return scanTodo(row)
 }
 
 func (s *postgresService) Update(ctx context.Context, id string, input UpdateInput) (domain.Todo, error) {
@@ -191,6 +400,11 @@ func (s *postgresService) Update(ctx context.Context, id string, input UpdateInp
 		priority = &v
 	}
 
+	var dueAt sql.NullTime
+	if input.DueAt != nil {
+		dueAt = sql.NullTime{Time: *input.DueAt, Valid: true}
+	}
+
 	row := s.db.QueryRowContext(
 		ctx,
 		updateTodoQuery,
@@ -199,36 +413,30 @@ func (s *postgresService) Update(ctx context.Context, id string, input UpdateInp
 		description,
 		status,
 		priority,
+		dueAt,
+		input.ClearDueAt,
+		input.ExpectedVersion,
+		input.ParentID,
+		input.ClearParent,
+		nullRecurrence(input.Recurrence),
+		input.ClearRecurrence,
+		input.AssigneeID,
+		input.ClearAssignee,
 	)
 
-	var todo domain.Todo
-	var descResult sql.NullString
-
-	err := row.Scan(
-		&todo.ID,
-		&todo.Title,
-		&descResult,
-		&todo.Status,
-		&todo.Priority,
-		&todo.CreatedAt,
-		&todo.UpdatedAt,
-	)
+	todo, err := scanTodo(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return domain.Todo{}, domain.ErrTodoNotFound
+			return domain.Todo{}, s.notFoundOrStale(ctx, id, input.ExpectedVersion)
 		}
 		return domain.Todo{}, err
 	}
 
-	if descResult.Valid {
-		todo.Description = descResult.String
-	}
-
 	return todo, nil
 }
 
-func (s *postgresService) Delete(ctx context.Context, id string) error {
-	result, err := s.db.ExecContext(ctx, deleteTodoQuery, id)
+func (s *postgresService) Delete(ctx context.Context, id string, input DeleteInput) error {
+	result, err := s.db.ExecContext(ctx, deleteTodoQuery, id, input.ExpectedVersion)
 	if err != nil {
 		return err
 	}
@@ -239,8 +447,242 @@ func (s *postgresService) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return domain.ErrTodoNotFound
+		return s.notFoundOrStale(ctx, id, input.ExpectedVersion)
 	}
 
 	return nil
 }
+
+func (s *postgresService) Restore(ctx context.Context, id string) (domain.Todo, error) {
+	row := s.db.QueryRowContext(ctx, restoreTodoQuery, id)
+
+	todo, err := scanTodo(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Todo{}, s.notFoundOrParentTrashed(ctx, id)
+		}
+		return domain.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+// Purge permanently removes the todos that were moved to the trash before deletedBefore
+// along with their attachments. The storage keys of the attachments are read in the same
+// statement, before the delete cascades to them.
+func (s *postgresService) Purge(ctx context.Context, deletedBefore time.Time) (PurgeResult, error) {
+	rows, err := s.db.QueryContext(ctx, purgeTodosQuery, deletedBefore)
+	if err != nil {
+		return PurgeResult{}, err
+	}
+	defer rows.Close()
+
+	var result PurgeResult
+	for rows.Next() {
+		var id string
+		var keys []string
+		if err := rows.Scan(&id, pq.Array(&keys)); err != nil {
+			return PurgeResult{}, err
+		}
+		result.Purged++
+		result.StorageKeys = append(result.StorageKeys, keys...)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *postgresService) GetAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	return s.queryIDs(ctx, getAncestorIDsQuery, id)
+}
+
+func (s *postgresService) DeleteDescendants(ctx context.Context, id string) ([]domain.Todo, error) {
+	return s.queryTodos(ctx, deleteDescendantsQuery, id)
+}
+
+func (s *postgresService) RestoreDescendants(ctx context.Context, id string) ([]domain.Todo, error) {
+	return s.queryTodos(ctx, restoreDescendantsQuery, id)
+}
+
+func (s *postgresService) OrphanChildren(ctx context.Context, id string) ([]domain.Todo, error) {
+	return s.queryTodos(ctx, orphanChildrenQuery, id)
+}
+
+// queryTodos runs a query returning the columns scanned by scanTodo.
+func (s *postgresService) queryTodos(ctx context.Context, query namedQuery, args ...any) ([]domain.Todo, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []domain.Todo
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+// queryIDs runs a query returning a single column of ids.
+func (s *postgresService) queryIDs(ctx context.Context, query namedQuery, args ...any) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// exec runs a statement and returns the number of affected rows.
+func (s *postgresService) exec(ctx context.Context, query namedQuery, args ...any) (int, error) {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+// notFoundOrStale tells a missing todo apart from a version mismatch once a
+// version guarded write has matched no rows.
+func (s *postgresService) notFoundOrStale(ctx context.Context, id string, expectedVersion *int) error {
+	if expectedVersion == nil {
+		return domain.ErrTodoNotFound
+	}
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return domain.ErrVersionMismatch
+}
+
+// notFoundOrParentTrashed tells why a trashed todo could not be restored.
+func (s *postgresService) notFoundOrParentTrashed(ctx context.Context, id string) error {
+	var trashed bool
+	if err := s.db.QueryRowContext(ctx, isParentTrashedQuery, id).Scan(&trashed); err != nil {
+		return err
+	}
+	if trashed {
+		return domain.ErrParentTrashed
+	}
+	return domain.ErrTodoNotFound
+}
+
+func filterArgs(filters Filters) []any {
+	var statuses, priorities pq.StringArray
+
+	for _, v := range filters.Statuses {
+		statuses = append(statuses, string(v))
+	}
+	for _, v := range filters.Priorities {
+		priorities = append(priorities, string(v))
+	}
+
+	var query *string
+	if filters.Query != "" {
+		query = &filters.Query
+	}
+
+	var dueBefore, dueAfter sql.NullTime
+	if filters.DueBefore != nil {
+		dueBefore = sql.NullTime{Time: *filters.DueBefore, Valid: true}
+	}
+	if filters.DueAfter != nil {
+		dueAfter = sql.NullTime{Time: *filters.DueAfter, Valid: true}
+	}
+
+	var tags pq.StringArray
+	if len(filters.Tags) > 0 {
+		tags = filters.Tags
+	}
+
+	return []any{statuses, priorities, query, dueBefore, dueAfter, filters.Overdue, filters.Deleted, filters.ParentID, tags, filters.MatchAllTags, filters.Blocked, filters.AssigneeID, filters.VisibleTo}
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTodo reads the todo columns shared by every query (see the RETURNING and SELECT
+// lists under sql/) followed by any query specific extra columns.
+func scanTodo(row rowScanner, extra ...any) (domain.Todo, error) {
+	var todo domain.Todo
+	var description sql.NullString
+	var parentID sql.NullString
+	var dueAt, completedAt, deletedAt sql.NullTime
+	var recurrence sql.NullString
+	var ownerID, assigneeID sql.NullString
+
+	dest := append([]any{
+		&todo.ID,
+		&parentID,
+		&todo.Title,
+		&description,
+		&todo.Status,
+		&todo.Priority,
+		&dueAt,
+		&recurrence,
+		&ownerID,
+		&assigneeID,
+		&completedAt,
+		&deletedAt,
+		&todo.Version,
+		&todo.CreatedAt,
+		&todo.UpdatedAt,
+		pq.Array(&todo.Tags),
+	}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return domain.Todo{}, err
+	}
+
+	if parentID.Valid {
+		todo.ParentID = &parentID.String
+	}
+	if description.Valid {
+		todo.Description = description.String
+	}
+	if dueAt.Valid {
+		todo.DueAt = &dueAt.Time
+	}
+	if recurrence.Valid {
+		r, err := domain.ParseRecurrence(recurrence.String)
+		if err != nil {
+			return domain.Todo{}, err
+		}
+		todo.Recurrence = &r
+	}
+	if ownerID.Valid {
+		todo.OwnerID = &ownerID.String
+	}
+	if assigneeID.Valid {
+		todo.AssigneeID = &assigneeID.String
+	}
+	if completedAt.Valid {
+		todo.CompletedAt = &completedAt.Time
+	}
+	if deletedAt.Valid {
+		todo.DeletedAt = &deletedAt.Time
+	}
+
+	return todo, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 
 	"todo-api/pkg/domain"
 	"todo-api/pkg/service"
@@ -22,18 +23,40 @@ const (
 
 var fixedTime = time.Date(2026, 1, 28, 10, 30, 0, 0, time.UTC)
 
+var todoColumns = []string{"id", "parent_id", "title", "description", "status", "priority", "due_at", "recurrence", "owner_id", "assignee_id", "completed_at", "deleted_at", "version", "created_at", "updated_at", "tags"}
+
+var byIDColumns = []string{
+	"id", "parent_id", "title", "description", "status", "priority", "due_at", "recurrence", "owner_id", "assignee_id", "completed_at", "deleted_at", "version", "created_at", "updated_at", "tags",
+	"subtask_count", "completed_subtask_count", "dependency_count", "open_dependency_count", "shared_with",
+}
+
+var listColumns = []string{
+	"id", "parent_id", "title", "description", "status", "priority", "due_at", "recurrence", "owner_id", "assignee_id", "completed_at", "deleted_at", "version", "created_at", "updated_at", "tags",
+	"subtask_count", "completed_subtask_count", "dependency_count", "open_dependency_count",
+	"rank", "title_highlight", "description_highlight",
+}
+
+// todoRows returns a pending todo row per id, as scanned by scanTodo.
+func todoRows(ids ...string) *sqlmock.Rows {
+	rows := sqlmock.NewRows(todoColumns)
+	for _, id := range ids {
+		rows.AddRow(id, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
+	}
+	return rows
+}
+
 func TestService_Get_ReturnsListSuccessfully(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
-	mock.ExpectQuery("SELECT").WithArgs(nil, nil).WillReturnRows(rows)
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
+	mock.ExpectQuery("SELECT").WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
 	svc := service.New(db)
 
-	result, err := svc.Get(context.Background(), service.Filters{})
+	result, err := svc.Get(context.Background(), service.Filters{}, service.Page{Limit: 10})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -52,13 +75,13 @@ func TestService_Get_WithStatusFilter(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, domain.StatusCompleted, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusCompleted, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
 	status := domain.StatusCompleted
-	mock.ExpectQuery("SELECT").WithArgs(string(status), nil).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT").WithArgs(`{"completed"}`, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
 	svc := service.New(db)
 
-	result, err := svc.Get(context.Background(), service.Filters{Status: &status})
+	result, err := svc.Get(context.Background(), service.Filters{Statuses: []domain.Status{status}}, service.Page{Limit: 10})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -74,13 +97,13 @@ func TestService_Get_WithPriorityFilter(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, domain.StatusPending, domain.PriorityHigh, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityHigh, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
 	priority := domain.PriorityHigh
-	mock.ExpectQuery("SELECT").WithArgs(nil, string(priority)).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT").WithArgs(nil, `{"high"}`, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
 	svc := service.New(db)
 
-	result, err := svc.Get(context.Background(), service.Filters{Priority: &priority})
+	result, err := svc.Get(context.Background(), service.Filters{Priorities: []domain.Priority{priority}}, service.Page{Limit: 10})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -96,11 +119,11 @@ func TestService_Get_ReturnsEmptyList(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"})
-	mock.ExpectQuery("SELECT").WithArgs(nil, nil).WillReturnRows(rows)
+	rows := sqlmock.NewRows(listColumns)
+	mock.ExpectQuery("SELECT").WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
 	svc := service.New(db)
 
-	result, err := svc.Get(context.Background(), service.Filters{})
+	result, err := svc.Get(context.Background(), service.Filters{}, service.Page{Limit: 10})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -116,12 +139,12 @@ func TestService_Get_WithNullDescription(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, nil, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
-	mock.ExpectQuery("SELECT").WithArgs(nil, nil).WillReturnRows(rows)
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
+	mock.ExpectQuery("SELECT").WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
 	svc := service.New(db)
 
-	result, err := svc.Get(context.Background(), service.Filters{})
+	result, err := svc.Get(context.Background(), service.Filters{}, service.Page{Limit: 10})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -140,14 +163,14 @@ func TestService_Get_WithBothFilters(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, domain.StatusCompleted, domain.PriorityHigh, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusCompleted, domain.PriorityHigh, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
 	status := domain.StatusCompleted
 	priority := domain.PriorityHigh
-	mock.ExpectQuery("SELECT").WithArgs(string(status), string(priority)).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT").WithArgs(`{"completed"}`, `{"high"}`, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
 	svc := service.New(db)
 
-	result, err := svc.Get(context.Background(), service.Filters{Status: &status, Priority: &priority})
+	result, err := svc.Get(context.Background(), service.Filters{Statuses: []domain.Status{status}, Priorities: []domain.Priority{priority}}, service.Page{Limit: 10})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -164,10 +187,288 @@ func TestService_Get_ReturnsErrorOnQueryFailure(t *testing.T) {
 	}
 	defer db.Close()
 	expectedErr := errors.New("database error")
-	mock.ExpectQuery("SELECT").WithArgs(nil, nil).WillReturnError(expectedErr)
+	mock.ExpectQuery("SELECT").WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnError(expectedErr)
 	svc := service.New(db)
 
-	_, err = svc.Get(context.Background(), service.Filters{})
+	_, err = svc.Get(context.Background(), service.Filters{}, service.Page{Limit: 10})
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestService_Get_WithCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(nonExistentID, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
+	cursorTime := fixedTime.Format(time.RFC3339Nano)
+	mock.ExpectQuery(`\(created_at, id\) < \(\$14::TIMESTAMP, \$15::UUID\)`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, cursorTime, validUUID, 10).WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Get(context.Background(), service.Filters{}, service.Page{
+		Limit: 10,
+		After: &service.Cursor{Values: []string{cursorTime}, ID: validUUID},
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 1 {
+		t.Errorf("expected 1 todo, got %d", len(result))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestService_Get_WithMultipleStatuses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns)
+	mock.ExpectQuery("SELECT").WithArgs(`{"pending","in_progress"}`, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
+	svc := service.New(db)
+
+	_, err = svc.Get(context.Background(), service.Filters{
+		Statuses: []domain.Status{domain.StatusPending, domain.StatusInProgress},
+	}, service.Page{Limit: 10})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestService_Get_OrdersByPrioritySemantically(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns)
+	mock.ExpectQuery(`ORDER BY CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END DESC, updated_at ASC, id ASC`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
+	svc := service.New(db)
+
+	_, err = svc.Get(context.Background(), service.Filters{}, service.Page{
+		Limit: 10,
+		Sort: []domain.SortOrder{
+			{Field: domain.SortPriority, Desc: true},
+			{Field: domain.SortUpdatedAt},
+		},
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestService_Get_WithMixedDirectionCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns)
+	mock.ExpectQuery(`\(CASE priority .* END < \$14::INT\) OR \(CASE priority .* END = \$14::INT AND title > \$15::VARCHAR\)`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, "3", validTitle, validUUID, 10).WillReturnRows(rows)
+	svc := service.New(db)
+	todo := domain.Todo{ID: validUUID, Title: validTitle, Priority: domain.PriorityHigh, CreatedAt: fixedTime}
+	sort := []domain.SortOrder{{Field: domain.SortPriority, Desc: true}, {Field: domain.SortTitle}}
+	cursor := service.NewCursor(todo, sort)
+
+	_, err = svc.Get(context.Background(), service.Filters{}, service.Page{Limit: 10, Sort: sort, After: &cursor})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestService_Get_RejectsMalformedCursor(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	svc := service.New(db)
+
+	_, err = svc.Get(context.Background(), service.Filters{}, service.Page{
+		Limit: 10,
+		After: &service.Cursor{Values: []string{"yesterday"}, ID: validUUID},
+	})
+
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestService_Get_WithSearchQuery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0,
+			0.5, "<mark>Test</mark> Todo", "<mark>Test</mark> Description")
+	mock.ExpectQuery(`ORDER BY ts_rank\(search_vector, websearch_to_tsquery\('english', \$3\)\) DESC, id DESC`).
+		WithArgs(nil, nil, "test", nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Get(context.Background(), service.Filters{Query: "test"}, service.Page{Limit: 10})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 1 || result[0].Match == nil {
+		t.Fatal("expected a todo with search match")
+	}
+	if result[0].Match.Rank != 0.5 {
+		t.Errorf("expected rank 0.5, got %v", result[0].Match.Rank)
+	}
+	if result[0].Match.TitleHighlight != "<mark>Test</mark> Todo" {
+		t.Errorf("expected highlighted title, got %s", result[0].Match.TitleHighlight)
+	}
+}
+
+func TestService_Get_WithoutSearchQueryHasNoMatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
+	mock.ExpectQuery("SELECT").WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Get(context.Background(), service.Filters{}, service.Page{Limit: 10})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result[0].Match != nil {
+		t.Error("expected no search match")
+	}
+}
+
+func TestService_Get_WithDueFilters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns)
+	before := fixedTime.Add(24 * time.Hour)
+	overdue := true
+	mock.ExpectQuery("SELECT").WithArgs(nil, nil, nil, before, fixedTime, true, false, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
+	svc := service.New(db)
+
+	_, err = svc.Get(context.Background(), service.Filters{
+		DueBefore: &before,
+		DueAfter:  &fixedTime,
+		Overdue:   &overdue,
+	}, service.Page{Limit: 10})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestService_Get_ListsTrashByDeletionTime(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, fixedTime, 2, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil, nil, nil)
+	mock.ExpectQuery(`ORDER BY deleted_at DESC, id DESC`).
+		WithArgs(nil, nil, nil, nil, nil, nil, true, nil, nil, false, nil, nil, nil, 10).WillReturnRows(rows)
+	svc := service.New(db)
+
+	todos, err := svc.Get(context.Background(), service.Filters{Deleted: true}, service.Page{Limit: 10, Sort: domain.TrashSort})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(todos) != 1 || todos[0].DeletedAt == nil || !todos[0].DeletedAt.Equal(fixedTime) {
+		t.Errorf("expected one todo deleted at %v, got %+v", fixedTime, todos)
+	}
+}
+
+func TestService_Get_WithDueAtCursorForUndatedTodo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(listColumns)
+	mock.ExpectQuery(`\(COALESCE\(due_at, 'infinity'::TIMESTAMP\), id\) > \(\$14::TIMESTAMP, \$15::UUID\)`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, "infinity", validUUID, 10).WillReturnRows(rows)
+	svc := service.New(db)
+	sort := []domain.SortOrder{{Field: domain.SortDueAt}}
+	cursor := service.NewCursor(domain.Todo{ID: validUUID}, sort)
+
+	_, err = svc.Get(context.Background(), service.Filters{}, service.Page{Limit: 10, Sort: sort, After: &cursor})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestService_Count_ReturnsTotal(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	status := domain.StatusPending
+	mock.ExpectQuery("SELECT COUNT").WithArgs(`{"pending"}`, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	svc := service.New(db)
+
+	total, err := svc.Count(context.Background(), service.Filters{Statuses: []domain.Status{status}})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if total != 7 {
+		t.Errorf("expected total 7, got %d", total)
+	}
+}
+
+func TestService_Count_ReturnsErrorOnQueryFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("SELECT COUNT").WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil).WillReturnError(errors.New("database error"))
+	svc := service.New(db)
+
+	_, err = svc.Count(context.Background(), service.Filters{})
 
 	if err == nil {
 		t.Error("expected error, got nil")
@@ -180,8 +481,8 @@ func TestService_GetByID_ReturnsTodoSuccessfully(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil)
 	mock.ExpectQuery("SELECT").WithArgs(validUUID).WillReturnRows(rows)
 	svc := service.New(db)
 
@@ -204,8 +505,8 @@ func TestService_GetByID_WithNullDescription(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, nil, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil)
 	mock.ExpectQuery("SELECT").WithArgs(validUUID).WillReturnRows(rows)
 	svc := service.New(db)
 
@@ -253,17 +554,36 @@ func TestService_GetByID_ReturnsErrorOnQueryFailure(t *testing.T) {
 	}
 }
 
+func TestService_Lock_LocksTheRowsInOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`ORDER BY id\s+FOR UPDATE`).WithArgs(pq.StringArray{validUUID}).WillReturnResult(sqlmock.NewResult(0, 1))
+	svc := service.New(db)
+
+	err = svc.Lock(context.Background(), []string{validUUID})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
 func TestService_Create_ReturnsTodoSuccessfully(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
 	desc := validDescription
 	mock.ExpectQuery("INSERT").
-		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium).
+		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil).
 		WillReturnRows(rows)
 	svc := service.New(db)
 	input := service.CreateInput{
@@ -289,10 +609,10 @@ func TestService_Create_WithoutDescription(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, nil, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
 	mock.ExpectQuery("INSERT").
-		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium).
+		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil).
 		WillReturnRows(rows)
 	svc := service.New(db)
 	input := service.CreateInput{
@@ -319,7 +639,7 @@ func TestService_Create_ReturnsErrorOnQueryFailure(t *testing.T) {
 	defer db.Close()
 	expectedErr := errors.New("database error")
 	mock.ExpectQuery("INSERT").
-		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium).
+		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil).
 		WillReturnError(expectedErr)
 	svc := service.New(db)
 	input := service.CreateInput{
@@ -335,6 +655,35 @@ func TestService_Create_ReturnsErrorOnQueryFailure(t *testing.T) {
 	}
 }
 
+func TestService_Create_WithDueAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	dueAt := fixedTime.Add(48 * time.Hour)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, dueAt, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
+	mock.ExpectQuery("INSERT").
+		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium, dueAt, nil, nil, nil, nil).
+		WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Create(context.Background(), service.CreateInput{
+		Title:    validTitle,
+		Status:   domain.StatusPending,
+		Priority: domain.PriorityMedium,
+		DueAt:    &dueAt,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.DueAt == nil || !result.DueAt.Equal(dueAt) {
+		t.Errorf("expected due date %v, got %v", dueAt, result.DueAt)
+	}
+}
+
 func TestService_Update_ReturnsTodoSuccessfully(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -342,11 +691,11 @@ func TestService_Update_ReturnsTodoSuccessfully(t *testing.T) {
 	}
 	defer db.Close()
 	updatedTitle := "Updated Title"
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, updatedTitle, validDescription, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, updatedTitle, validDescription, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
 
 	mock.ExpectQuery("UPDATE").
-		WithArgs(validUUID, updatedTitle, nil, nil, nil).
+		WithArgs(validUUID, updatedTitle, nil, nil, nil, nil, false, nil, nil, false, nil, false, nil, false).
 		WillReturnRows(rows)
 	svc := service.New(db)
 	input := service.UpdateInput{Title: &updatedTitle}
@@ -368,11 +717,11 @@ func TestService_Update_WithDescription(t *testing.T) {
 	}
 	defer db.Close()
 	updatedDesc := "Updated Description"
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, updatedDesc, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, updatedDesc, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
 
 	mock.ExpectQuery("UPDATE").
-		WithArgs(validUUID, nil, updatedDesc, nil, nil).
+		WithArgs(validUUID, nil, updatedDesc, nil, nil, nil, false, nil, nil, false, nil, false, nil, false).
 		WillReturnRows(rows)
 	svc := service.New(db)
 	input := service.UpdateInput{Description: &updatedDesc}
@@ -394,11 +743,11 @@ func TestService_Update_WithStatus(t *testing.T) {
 	}
 	defer db.Close()
 	status := domain.StatusCompleted
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, status, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, status, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
 
 	mock.ExpectQuery("UPDATE").
-		WithArgs(validUUID, nil, nil, string(status), nil).
+		WithArgs(validUUID, nil, nil, string(status), nil, nil, false, nil, nil, false, nil, false, nil, false).
 		WillReturnRows(rows)
 	svc := service.New(db)
 	input := service.UpdateInput{Status: &status}
@@ -420,11 +769,11 @@ func TestService_Update_WithPriority(t *testing.T) {
 	}
 	defer db.Close()
 	priority := domain.PriorityHigh
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, validTitle, validDescription, domain.StatusPending, priority, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, validDescription, domain.StatusPending, priority, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
 
 	mock.ExpectQuery("UPDATE").
-		WithArgs(validUUID, nil, nil, nil, string(priority)).
+		WithArgs(validUUID, nil, nil, nil, string(priority), nil, false, nil, nil, false, nil, false, nil, false).
 		WillReturnRows(rows)
 	svc := service.New(db)
 	input := service.UpdateInput{Priority: &priority}
@@ -446,11 +795,11 @@ func TestService_Update_WithNullDescription(t *testing.T) {
 	}
 	defer db.Close()
 	updatedTitle := "Updated Title"
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "priority", "created_at", "updated_at"}).
-		AddRow(validUUID, updatedTitle, nil, domain.StatusPending, domain.PriorityMedium, fixedTime, fixedTime)
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, updatedTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
 
 	mock.ExpectQuery("UPDATE").
-		WithArgs(validUUID, updatedTitle, nil, nil, nil).
+		WithArgs(validUUID, updatedTitle, nil, nil, nil, nil, false, nil, nil, false, nil, false, nil, false).
 		WillReturnRows(rows)
 	svc := service.New(db)
 	input := service.UpdateInput{Title: &updatedTitle}
@@ -473,7 +822,7 @@ func TestService_Update_ReturnsErrTodoNotFound(t *testing.T) {
 	defer db.Close()
 	updatedTitle := "Updated Title"
 	mock.ExpectQuery("UPDATE").
-		WithArgs(nonExistentID, updatedTitle, nil, nil, nil).
+		WithArgs(nonExistentID, updatedTitle, nil, nil, nil, nil, false, nil, nil, false, nil, false, nil, false).
 		WillReturnError(sql.ErrNoRows)
 	svc := service.New(db)
 	input := service.UpdateInput{Title: &updatedTitle}
@@ -485,6 +834,52 @@ func TestService_Update_ReturnsErrTodoNotFound(t *testing.T) {
 	}
 }
 
+func TestService_Update_ReturnsErrVersionMismatchOnStaleVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	updatedTitle := "Updated Title"
+	version := 1
+	mock.ExpectQuery("UPDATE").
+		WithArgs(validUUID, updatedTitle, nil, nil, nil, nil, false, version, nil, false, nil, false, nil, false).
+		WillReturnError(sql.ErrNoRows)
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 2, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil)
+	mock.ExpectQuery("SELECT").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+	input := service.UpdateInput{Title: &updatedTitle, ExpectedVersion: &version}
+
+	_, err = svc.Update(context.Background(), validUUID, input)
+
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch, got %v", err)
+	}
+}
+
+func TestService_Update_WithExpectedVersion_ReturnsErrTodoNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	updatedTitle := "Updated Title"
+	version := 1
+	mock.ExpectQuery("UPDATE").
+		WithArgs(nonExistentID, updatedTitle, nil, nil, nil, nil, false, version, nil, false, nil, false, nil, false).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT").WithArgs(nonExistentID).WillReturnError(sql.ErrNoRows)
+	svc := service.New(db)
+	input := service.UpdateInput{Title: &updatedTitle, ExpectedVersion: &version}
+
+	_, err = svc.Update(context.Background(), nonExistentID, input)
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
 func TestService_Update_ReturnsErrorOnQueryFailure(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -494,7 +889,7 @@ func TestService_Update_ReturnsErrorOnQueryFailure(t *testing.T) {
 	expectedErr := errors.New("database error")
 	updatedTitle := "Updated Title"
 	mock.ExpectQuery("UPDATE").
-		WithArgs(validUUID, updatedTitle, nil, nil, nil).
+		WithArgs(validUUID, updatedTitle, nil, nil, nil, nil, false, nil, nil, false, nil, false, nil, false).
 		WillReturnError(expectedErr)
 	svc := service.New(db)
 	input := service.UpdateInput{Title: &updatedTitle}
@@ -506,16 +901,63 @@ func TestService_Update_ReturnsErrorOnQueryFailure(t *testing.T) {
 	}
 }
 
+func TestService_Update_ClearsDueAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
+	mock.ExpectQuery("UPDATE").
+		WithArgs(validUUID, nil, nil, nil, nil, nil, true, nil, nil, false, nil, false, nil, false).
+		WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Update(context.Background(), validUUID, service.UpdateInput{ClearDueAt: true})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.DueAt != nil {
+		t.Errorf("expected no due date, got %v", result.DueAt)
+	}
+}
+
+func TestService_Update_ReturnsCompletedAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	status := domain.StatusCompleted
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, status, domain.PriorityMedium, nil, nil, nil, nil, fixedTime, nil, 1, fixedTime, fixedTime, nil)
+	mock.ExpectQuery("completed_at = CASE").
+		WithArgs(validUUID, nil, nil, string(status), nil, nil, false, nil, nil, false, nil, false, nil, false).
+		WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Update(context.Background(), validUUID, service.UpdateInput{Status: &status})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.CompletedAt == nil || !result.CompletedAt.Equal(fixedTime) {
+		t.Errorf("expected completed_at %v, got %v", fixedTime, result.CompletedAt)
+	}
+}
+
 func TestService_Delete_Successfully(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	mock.ExpectExec("DELETE").WithArgs(validUUID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SET\s+deleted_at = NOW\(\)`).WithArgs(validUUID, nil).WillReturnResult(sqlmock.NewResult(0, 1))
 	svc := service.New(db)
 
-	err = svc.Delete(context.Background(), validUUID)
+	err = svc.Delete(context.Background(), validUUID, service.DeleteInput{})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -528,16 +970,36 @@ func TestService_Delete_ReturnsErrTodoNotFound(t *testing.T) {
 		t.Fatalf("failed to create mock: %v", err)
 	}
 	defer db.Close()
-	mock.ExpectExec("DELETE").WithArgs(nonExistentID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SET\s+deleted_at = NOW\(\)`).WithArgs(nonExistentID, nil).WillReturnResult(sqlmock.NewResult(0, 0))
 	svc := service.New(db)
 
-	err = svc.Delete(context.Background(), nonExistentID)
+	err = svc.Delete(context.Background(), nonExistentID, service.DeleteInput{})
 
 	if !errors.Is(err, domain.ErrTodoNotFound) {
 		t.Errorf("expected ErrTodoNotFound, got %v", err)
 	}
 }
 
+func TestService_Delete_ReturnsErrVersionMismatchOnStaleVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	version := 1
+	mock.ExpectExec(`SET\s+deleted_at = NOW\(\)`).WithArgs(validUUID, version).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 2, fixedTime, fixedTime, nil, 0, 0, 0, 0, nil)
+	mock.ExpectQuery("SELECT").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+
+	err = svc.Delete(context.Background(), validUUID, service.DeleteInput{ExpectedVersion: &version})
+
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch, got %v", err)
+	}
+}
+
 func TestService_Delete_ReturnsErrorOnExecFailure(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -545,12 +1007,309 @@ func TestService_Delete_ReturnsErrorOnExecFailure(t *testing.T) {
 	}
 	defer db.Close()
 	expectedErr := errors.New("database error")
-	mock.ExpectExec("DELETE").WithArgs(validUUID).WillReturnError(expectedErr)
+	mock.ExpectExec(`SET\s+deleted_at = NOW\(\)`).WithArgs(validUUID, nil).WillReturnError(expectedErr)
 	svc := service.New(db)
 
-	err = svc.Delete(context.Background(), validUUID)
+	err = svc.Delete(context.Background(), validUUID, service.DeleteInput{})
 
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 }
+
+func TestService_Restore_Successfully(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 3, fixedTime, fixedTime, nil)
+	mock.ExpectQuery(`SET\s+deleted_at = NULL`).WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+
+	todo, err := svc.Restore(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if todo.DeletedAt != nil {
+		t.Errorf("expected restored todo to have no deleted_at, got %v", todo.DeletedAt)
+	}
+	if todo.Version != 3 {
+		t.Errorf("expected version 3, got %d", todo.Version)
+	}
+}
+
+func TestService_Restore_ReturnsErrTodoNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SET\s+deleted_at = NULL`).WithArgs(nonExistentID).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(nonExistentID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	svc := service.New(db)
+
+	_, err = svc.Restore(context.Background(), nonExistentID)
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+func TestService_Restore_ReturnsErrParentTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SET\s+deleted_at = NULL`).WithArgs(validUUID).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(validUUID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	svc := service.New(db)
+
+	_, err = svc.Restore(context.Background(), validUUID)
+
+	if !errors.Is(err, domain.ErrParentTrashed) {
+		t.Errorf("expected ErrParentTrashed, got %v", err)
+	}
+}
+
+func TestService_Purge_ReturnsRemovedCountAndStorageKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`DELETE FROM todos\s+WHERE deleted_at IS NOT NULL`).WithArgs(fixedTime).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "storage_keys"}).
+			AddRow(validUUID, `{`+validUUID+`/a,`+validUUID+`/b}`).
+			AddRow(otherUUID, `{}`))
+	svc := service.New(db)
+
+	result, err := svc.Purge(context.Background(), fixedTime)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Purged != 2 {
+		t.Errorf("expected 2 purged todos, got %d", result.Purged)
+	}
+	if len(result.StorageKeys) != 2 || result.StorageKeys[0] != validUUID+"/a" || result.StorageKeys[1] != validUUID+"/b" {
+		t.Errorf("expected the storage keys of the attachments, got %v", result.StorageKeys)
+	}
+}
+
+func TestService_GetByID_ReturnsParentAndSubtasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(byIDColumns).
+		AddRow(validUUID, nonExistentID, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil, 4, 1, 0, 0, nil)
+	mock.ExpectQuery("LEFT JOIN LATERAL").WithArgs(validUUID).WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.GetByID(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ParentID == nil || *result.ParentID != nonExistentID {
+		t.Errorf("expected parent %s, got %v", nonExistentID, result.ParentID)
+	}
+	if result.Subtasks == nil || result.Subtasks.Total != 4 || result.Subtasks.Completed != 1 {
+		t.Errorf("expected 1 of 4 subtasks completed, got %+v", result.Subtasks)
+	}
+}
+
+func TestService_Get_WithParentFilter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	parentID := validUUID
+	mock.ExpectQuery(`parent_id = \$8`).
+		WithArgs(nil, nil, nil, nil, nil, nil, false, &parentID, nil, false, nil, nil, nil, 10).
+		WillReturnRows(sqlmock.NewRows(listColumns))
+	svc := service.New(db)
+
+	_, err = svc.Get(context.Background(), service.Filters{ParentID: &parentID}, service.Page{Limit: 10})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_Create_WithParent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	parentID := nonExistentID
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, parentID, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
+	mock.ExpectQuery("INSERT INTO todos").
+		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium, nil, &parentID, nil, nil, nil).
+		WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Create(context.Background(), service.CreateInput{
+		Title:    validTitle,
+		Status:   domain.StatusPending,
+		Priority: domain.PriorityMedium,
+		ParentID: &parentID,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.ParentID == nil || *result.ParentID != parentID {
+		t.Errorf("expected parent %s, got %v", parentID, result.ParentID)
+	}
+}
+
+func TestService_GetAncestorIDs_ReturnsChain(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("WITH RECURSIVE ancestors").WithArgs(validUUID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(validUUID).AddRow(nonExistentID))
+	svc := service.New(db)
+
+	result, err := svc.GetAncestorIDs(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 2 || result[1] != nonExistentID {
+		t.Errorf("expected the todo and its parent, got %v", result)
+	}
+}
+
+func TestService_DeleteDescendants_ReturnsTrashedTodos(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("WITH RECURSIVE descendants").WithArgs(validUUID).
+		WillReturnRows(todoRows(nonExistentID, otherUUID, commentUUID))
+	svc := service.New(db)
+
+	result, err := svc.DeleteDescendants(context.Background(), validUUID)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 3 || result[0].Title != validTitle {
+		t.Errorf("expected 3 trashed subtasks, got %v", result)
+	}
+}
+
+func TestService_RestoreDescendants_RestoresTheSubtasksTrashedWithTheTodo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`JOIN trashed ON t\.deleted_at = trashed\.deleted_at(.|\n)+SET\s+deleted_at = NULL`).
+		WithArgs(validUUID).
+		WillReturnRows(todoRows(otherUUID, commentUUID))
+	svc := service.New(db)
+
+	todos, err := svc.RestoreDescendants(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(todos) != 2 || todos[0].ID != otherUUID || todos[1].ID != commentUUID {
+		t.Errorf("expected the child and the grandchild, got %v", todos)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestService_OrphanChildren_ReturnsDetachedTodos(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SET\s+parent_id = NULL`).WithArgs(validUUID).
+		WillReturnRows(todoRows(nonExistentID, otherUUID))
+	svc := service.New(db)
+
+	result, err := svc.OrphanChildren(context.Background(), validUUID)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 2 || result[0].ID != nonExistentID || result[0].ParentID != nil {
+		t.Errorf("expected 2 detached subtasks, got %v", result)
+	}
+}
+
+func TestService_Create_WithRecurrence(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rule := "FREQ=WEEKLY;BYDAY=MO,TH"
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, fixedTime, rule, nil, nil, nil, nil, 1, fixedTime, fixedTime, nil)
+	mock.ExpectQuery("INSERT INTO todos").
+		WithArgs(validTitle, sqlmock.AnyArg(), domain.StatusPending, domain.PriorityMedium, fixedTime, nil, rule, nil, nil).
+		WillReturnRows(rows)
+	svc := service.New(db)
+	recurrence, _ := domain.ParseRecurrence("RRULE:freq=weekly;byday=MO,TH")
+	dueAt := fixedTime
+
+	result, err := svc.Create(context.Background(), service.CreateInput{
+		Title:      validTitle,
+		Status:     domain.StatusPending,
+		Priority:   domain.PriorityMedium,
+		DueAt:      &dueAt,
+		Recurrence: &recurrence,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Recurrence == nil || result.Recurrence.String() != rule {
+		t.Errorf("expected recurrence %s, got %v", rule, result.Recurrence)
+	}
+}
+
+func TestService_Update_ClearRecurrence(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows(todoColumns).
+		AddRow(validUUID, nil, validTitle, nil, domain.StatusPending, domain.PriorityMedium, nil, nil, nil, nil, nil, nil, 2, fixedTime, fixedTime, nil)
+	mock.ExpectQuery(`recurrence = CASE WHEN \$12::BOOLEAN`).
+		WithArgs(validUUID, nil, nil, nil, nil, nil, false, nil, nil, false, nil, true, nil, false).
+		WillReturnRows(rows)
+	svc := service.New(db)
+
+	result, err := svc.Update(context.Background(), validUUID, service.UpdateInput{ClearRecurrence: true})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Recurrence != nil {
+		t.Errorf("expected no recurrence, got %v", result.Recurrence)
+	}
+}
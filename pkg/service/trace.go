@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var tracer = otel.Tracer("todo-api/pkg/service")
+
+type (
+	// namedQuery is an embedded query along with its file, e.g. sql/select/get_todos.sql,
+	// which names the span of the statement.
+	namedQuery struct {
+		file string
+		text string
+	}
+
+	// sqlQuerier is satisfied by both *sql.DB and *sql.Tx.
+	sqlQuerier interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	}
+
+	// tracedQuerier starts a span for every statement run by the services, named after
+	// the file of the query. A statement only gets a span when its context carries one,
+	// that is, while serving a traced request.
+	tracedQuerier struct {
+		db sqlQuerier
+	}
+)
+
+// name is the file name of the query without its extension, e.g. get_todos.
+func (q namedQuery) name() string {
+	return strings.TrimSuffix(path.Base(q.file), ".sql")
+}
+
+// operation is the directory of the query, e.g. select.
+func (q namedQuery) operation() string {
+	return path.Base(path.Dir(q.file))
+}
+
+// completed returns the query with another text, e.g. the embedded one completed with
+// more clauses.
+func (q namedQuery) completed(text string) namedQuery {
+	q.text = text
+	return q
+}
+
+func newTracedQuerier(db *sql.DB) querier {
+	return &tracedQuerier{db: db}
+}
+
+// unwrapDB returns the database under the querier, unless it is a transaction.
+func unwrapDB(q querier) (*sql.DB, bool) {
+	t, ok := q.(*tracedQuerier)
+	if !ok {
+		return nil, false
+	}
+	db, ok := t.db.(*sql.DB)
+	return db, ok
+}
+
+func (q *tracedQuerier) ExecContext(ctx context.Context, query namedQuery, args ...any) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	result, err := q.db.ExecContext(ctx, query.text, args...)
+	recordError(span, err)
+	return result, err
+}
+
+// QueryContext traces the statement up to its first row; reading the rows is left
+// out of the span.
+func (q *tracedQuerier) QueryContext(ctx context.Context, query namedQuery, args ...any) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	rows, err := q.db.QueryContext(ctx, query.text, args...)
+	recordError(span, err)
+	return rows, err
+}
+
+func (q *tracedQuerier) QueryRowContext(ctx context.Context, query namedQuery, args ...any) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	row := q.db.QueryRowContext(ctx, query.text, args...)
+	recordError(span, row.Err())
+	return row
+}
+
+// startQuerySpan starts the span of a statement, or a no-op span when the context is
+// not traced.
+func startQuerySpan(ctx context.Context, query namedQuery) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, noop.Span{}
+	}
+
+	return tracer.Start(ctx, query.name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(query.operation()),
+			attribute.String("db.query.file", query.file),
+		),
+	)
+}
+
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+// spanRecorder sets up the global provider once, as the tracer of the services is bound
+// to the first one set.
+var spanRecorder = sync.OnceValue(func() *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	return sr
+})
+
+// tracedContext returns a context holding the span of a new trace.
+func tracedContext() context.Context {
+	spanRecorder()
+	ctx, _ := otel.Tracer("test").Start(context.Background(), "test")
+	return ctx
+}
+
+// endedSpans returns the spans of the trace of the context ended so far.
+func endedSpans(ctx context.Context) []sdktrace.ReadOnlySpan {
+	traceID := trace.SpanContextFromContext(ctx).TraceID()
+	var spans []sdktrace.ReadOnlySpan
+	for _, span := range spanRecorder().Ended() {
+		if span.SpanContext().TraceID() == traceID {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestService_Trace_NamesSpansAfterQueryFiles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("SELECT").WithArgs(nil, nil, nil, nil, nil, nil, false, nil, nil, false, nil, nil, nil, 10).
+		WillReturnRows(sqlmock.NewRows(listColumns))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	svc := service.New(db)
+	ctx := tracedContext()
+
+	if _, err := svc.Get(ctx, service.Filters{}, service.Page{Limit: 10}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := svc.Count(ctx, service.Filters{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spans := endedSpans(ctx)
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	// the list query is the embedded one completed with the keyset predicate and ordering
+	if spans[0].Name() != "get_todos" || spanAttribute(spans[0], "db.query.file") != "sql/select/get_todos.sql" {
+		t.Errorf("expected a get_todos span, got %s %v", spans[0].Name(), spans[0].Attributes())
+	}
+	if spans[1].Name() != "count_todos" || spanAttribute(spans[1], "db.operation.name") != "select" || spans[1].SpanKind() != trace.SpanKindClient {
+		t.Errorf("expected a count_todos span, got %s %v", spans[1].Name(), spans[1].Attributes())
+	}
+}
+
+func TestService_Trace_RecordsErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SET\s+deleted_at = NOW\(\)`).WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
+	svc := service.New(db)
+	ctx := tracedContext()
+
+	err = svc.Transaction(ctx, func(tx service.Todo) error {
+		_, err := tx.DeleteBatch(ctx, []service.BatchDelete{{ID: validUUID}})
+		return err
+	})
+
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	spans := endedSpans(ctx)
+	if len(spans) != 1 || spans[0].Name() != "delete_todos_batch" || spans[0].Status().Code != codes.Error {
+		t.Errorf("expected a failed delete_todos_batch span within the transaction, got %+v", spans)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestService_Trace_UntracedContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery("SELECT").WillReturnError(domain.ErrTodoNotFound)
+	svc := service.New(db)
+
+	// without a span in the context, statements run as before
+	if _, err := svc.Count(context.Background(), service.Filters{}); !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected the error of the query, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+const (
+	// apiKeyPrefix starts every API key, which makes leaked keys easy to spot.
+	apiKeyPrefix = "tk_"
+	// apiKeyDisplayLength is how many leading characters of a key are kept to recognize it.
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+)
+
+type (
+	// IssueAPIKeyInput describes the key to issue. The key acts for the acting user unless
+	// User names another one, which only an admin may do.
+	IssueAPIKeyInput struct {
+		Name   string
+		App    string
+		Scopes []string
+		User   string
+	}
+
+	// IssueAPIKeyOutput holds the issued key along with its secret, which is only ever
+	// known at this point.
+	IssueAPIKeyOutput struct {
+		Key    domain.APIKey
+		Secret string
+	}
+
+	ListAPIKeysOutput struct {
+		Keys []domain.APIKey
+	}
+
+	// APIKey issues and verifies the API keys client applications authenticate with.
+	APIKey struct {
+		keys   service.APIKey
+		policy *policy.Policy
+	}
+
+	APIKeyOption func(*APIKey)
+)
+
+// WithAPIKeyPolicy checks the management of keys against the policy. Authentication is
+// not subject to it.
+func WithAPIKeyPolicy(p *policy.Policy) APIKeyOption {
+	return func(u *APIKey) {
+		u.policy = p
+	}
+}
+
+func NewAPIKey(keys service.APIKey, opts ...APIKeyOption) *APIKey {
+	u := &APIKey{keys: keys}
+	for _, o := range opts {
+		o(u)
+	}
+	return u
+}
+
+func (u *APIKey) List(ctx context.Context) (ListAPIKeysOutput, error) {
+	if err := u.authorize(ctx); err != nil {
+		return ListAPIKeysOutput{}, err
+	}
+
+	keys, err := u.keys.List(ctx)
+	if err != nil {
+		return ListAPIKeysOutput{}, err
+	}
+
+	return ListAPIKeysOutput{Keys: keys}, nil
+}
+
+// Issue creates a key for the app granting the scopes. The secret is random and only its
+// hash is stored, so it must be handed to the client right away.
+func (u *APIKey) Issue(ctx context.Context, input IssueAPIKeyInput) (IssueAPIKeyOutput, error) {
+	if err := u.authorize(ctx); err != nil {
+		return IssueAPIKeyOutput{}, err
+	}
+
+	user := actingUser(ctx)
+	if input.User != "" && input.User != user {
+		if !isAdmin(u.policy, user) {
+			return IssueAPIKeyOutput{}, domain.ErrForbidden
+		}
+		user = input.User
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return IssueAPIKeyOutput{}, err
+	}
+	secret := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	key, err := u.keys.Create(ctx, service.APIKeyInput{
+		Name:   input.Name,
+		App:    input.App,
+		Scopes: input.Scopes,
+		Prefix: secret[:apiKeyDisplayLength],
+		Hash:   hashAPIKey(secret),
+		User:   user,
+	})
+	if err != nil {
+		return IssueAPIKeyOutput{}, err
+	}
+
+	return IssueAPIKeyOutput{Key: key, Secret: secret}, nil
+}
+
+func (u *APIKey) Revoke(ctx context.Context, id string) error {
+	if err := u.authorize(ctx); err != nil {
+		return err
+	}
+
+	return u.keys.Revoke(ctx, id)
+}
+
+// Authenticate returns the live key whose secret is given, or domain.ErrInvalidAPIKey.
+func (u *APIKey) Authenticate(ctx context.Context, secret string) (domain.APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeyPrefix) {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+
+	key, err := u.keys.GetByHash(ctx, hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return domain.APIKey{}, domain.ErrInvalidAPIKey
+		}
+		return domain.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// authorize lets the caller manage keys. Keys are never managed anonymously: a caller with
+// neither a user nor an API key gets domain.ErrUnauthenticated, which matters when the
+// authentication of users is disabled.
+func (u *APIKey) authorize(ctx context.Context) error {
+	if _, ok := domain.APIKeyFromContext(ctx); !ok && actingUser(ctx) == "" {
+		return domain.ErrUnauthenticated
+	}
+	return u.policy.Authorize(ctx, policy.ActionManageKeys)
+}
+
+// hashAPIKey needs no salt nor stretching: keys are 256 random bits, out of reach of
+// dictionaries and brute force alike.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
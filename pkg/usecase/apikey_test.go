@@ -0,0 +1,157 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+const apiKeyUUID = "623e4567-e89b-12d3-a456-426614174000"
+
+// memoryAPIKeyService keeps issued keys by hash, like the api_keys table does.
+func memoryAPIKeyService() *test.MockAPIKeyService {
+	keys := map[string]domain.APIKey{}
+	return &test.MockAPIKeyService{
+		CreateFn: func(ctx context.Context, input service.APIKeyInput) (domain.APIKey, error) {
+			key := domain.APIKey{ID: apiKeyUUID, Name: input.Name, App: input.App, Scopes: input.Scopes, Prefix: input.Prefix, CreatedAt: fixedTime}
+			keys[input.Hash] = key
+			return key, nil
+		},
+		GetByHashFn: func(ctx context.Context, hash string) (domain.APIKey, error) {
+			key, ok := keys[hash]
+			if !ok {
+				return domain.APIKey{}, domain.ErrAPIKeyNotFound
+			}
+			return key, nil
+		},
+	}
+}
+
+func TestAPIKey_Issue_ThenAuthenticate(t *testing.T) {
+	uc := usecase.NewAPIKey(memoryAPIKeyService())
+
+	issued, err := uc.Issue(asUser("alice"), usecase.IssueAPIKeyInput{
+		Name:   "reminders",
+		App:    "reminders-job",
+		Scopes: []string{domain.ScopeTodosRead},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(issued.Secret, issued.Key.Prefix) || len(issued.Key.Prefix) >= len(issued.Secret) {
+		t.Errorf("expected the prefix to be the start of the secret, got %q and %q", issued.Key.Prefix, issued.Secret)
+	}
+
+	key, err := uc.Authenticate(context.Background(), issued.Secret)
+	if err != nil {
+		t.Fatalf("expected the secret to authenticate, got %v", err)
+	}
+	if key.App != "reminders-job" {
+		t.Errorf("expected the key of reminders-job, got %+v", key)
+	}
+}
+
+func TestAPIKey_Issue_GeneratesDistinctSecrets(t *testing.T) {
+	uc := usecase.NewAPIKey(memoryAPIKeyService())
+	input := usecase.IssueAPIKeyInput{Name: "reminders", App: "reminders-job", Scopes: []string{domain.ScopeTodosRead}}
+
+	first, _ := uc.Issue(asUser("alice"), input)
+	second, _ := uc.Issue(asUser("alice"), input)
+
+	if first.Secret == second.Secret {
+		t.Error("expected two issued keys to have different secrets")
+	}
+}
+
+func TestAPIKey_Issue_BindsTheKeyToTheActingUser(t *testing.T) {
+	var created service.APIKeyInput
+	mock := &test.MockAPIKeyService{
+		CreateFn: func(ctx context.Context, input service.APIKeyInput) (domain.APIKey, error) {
+			created = input
+			return domain.APIKey{ID: apiKeyUUID, User: input.User}, nil
+		},
+	}
+	// bob manages keys without being an admin
+	p, err := policy.New(policy.Config{
+		Roles: map[string][]string{policy.RoleAdmin: {"*"}, "integrator": {string(policy.ActionManageKeys)}},
+		Users: map[string]string{"alice": policy.RoleAdmin, "bob": "integrator"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.NewAPIKey(mock, usecase.WithAPIKeyPolicy(p))
+	input := usecase.IssueAPIKeyInput{Name: "assistant", App: "assistant", Scopes: []string{domain.ScopeTodosRead}}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		user string
+		want string
+		err  error
+	}{
+		{name: "own key", ctx: asUser("bob"), want: "bob"},
+		{name: "naming oneself", ctx: asUser("bob"), user: "bob", want: "bob"},
+		{name: "non admin naming another user", ctx: asUser("bob"), user: "carol", err: domain.ErrForbidden},
+		{name: "admin naming another user", ctx: asUser("alice"), user: "carol", want: "carol"},
+		{name: "key without user naming a user", ctx: domain.WithAPIKey(context.Background(), domain.APIKey{App: "provisioner"}), user: "carol", err: domain.ErrForbidden},
+		{name: "anonymous", ctx: context.Background(), err: domain.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = service.APIKeyInput{}
+			in := input
+			in.User = tt.user
+
+			_, err := uc.Issue(tt.ctx, in)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if tt.err == nil && created.User != tt.want {
+				t.Errorf("expected the key to act for %q, got %q", tt.want, created.User)
+			}
+		})
+	}
+}
+
+func TestAPIKey_RequiresAnAuthenticatedCaller(t *testing.T) {
+	// the service is never reached: any call would panic on the nil functions
+	uc := usecase.NewAPIKey(&test.MockAPIKeyService{})
+
+	if _, err := uc.List(context.Background()); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Errorf("expected ErrUnauthenticated on list, got %v", err)
+	}
+	if err := uc.Revoke(context.Background(), apiKeyUUID); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Errorf("expected ErrUnauthenticated on revoke, got %v", err)
+	}
+}
+
+func TestAPIKey_Authenticate_RejectsUnknownSecrets(t *testing.T) {
+	uc := usecase.NewAPIKey(memoryAPIKeyService())
+
+	for _, secret := range []string{"", "not-a-key", "tk_unknown"} {
+		if _, err := uc.Authenticate(context.Background(), secret); !errors.Is(err, domain.ErrInvalidAPIKey) {
+			t.Errorf("expected ErrInvalidAPIKey for %q, got %v", secret, err)
+		}
+	}
+}
+
+func TestAPIKey_Authenticate_PropagatesServiceErrors(t *testing.T) {
+	boom := errors.New("connection refused")
+	uc := usecase.NewAPIKey(&test.MockAPIKeyService{
+		GetByHashFn: func(ctx context.Context, hash string) (domain.APIKey, error) {
+			return domain.APIKey{}, boom
+		},
+	})
+
+	if _, err := uc.Authenticate(context.Background(), "tk_whatever"); !errors.Is(err, boom) {
+		t.Errorf("expected the service error, got %v", err)
+	}
+}
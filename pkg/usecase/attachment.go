@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+// sniffLength is how many leading bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+type (
+	// UploadInput is a file to attach. Size is the size announced for the content, which
+	// is checked again while the content is stored.
+	UploadInput struct {
+		Filename string
+		Size     int64
+		Content  io.Reader
+	}
+
+	AttachmentOutput struct {
+		Attachment domain.Attachment
+	}
+
+	ListAttachmentsOutput struct {
+		Attachments []domain.Attachment
+	}
+
+	// DownloadOutput holds an attachment and its content, which the caller must close.
+	DownloadOutput struct {
+		Attachment domain.Attachment
+		Content    io.ReadCloser
+	}
+
+	// Attachment manages the files attached to todos: their metadata is kept by the
+	// attachment service and their content by the blob store. Like comments, attachments
+	// are only reachable while their todo is live.
+	Attachment struct {
+		attachments service.Attachment
+		blobs       service.BlobStore
+		todos       service.Todo
+		maxSize     int64
+		types       []string
+		policy      *policy.Policy
+	}
+
+	AttachmentOption func(*Attachment)
+
+	// sizeLimitReader counts what is read through it and fails with domain.ErrAttachmentTooLarge
+	// as soon as more than limit bytes come through.
+	sizeLimitReader struct {
+		r     io.Reader
+		limit int64
+		read  int64
+	}
+)
+
+// WithMaxAttachmentSize replaces domain.DefaultMaxAttachmentSize as the largest accepted file, in bytes.
+func WithMaxAttachmentSize(n int64) AttachmentOption {
+	return func(u *Attachment) {
+		u.maxSize = n
+	}
+}
+
+// WithAttachmentTypes replaces domain.DefaultAttachmentTypes as the accepted media types.
+func WithAttachmentTypes(types ...string) AttachmentOption {
+	return func(u *Attachment) {
+		u.types = types
+	}
+}
+
+// WithAttachmentPolicy checks every action against the policy before performing it.
+func WithAttachmentPolicy(p *policy.Policy) AttachmentOption {
+	return func(u *Attachment) {
+		u.policy = p
+	}
+}
+
+func NewAttachment(attachments service.Attachment, blobs service.BlobStore, todos service.Todo, opts ...AttachmentOption) *Attachment {
+	u := &Attachment{
+		attachments: attachments,
+		blobs:       blobs,
+		todos:       todos,
+		maxSize:     domain.DefaultMaxAttachmentSize,
+		types:       domain.DefaultAttachmentTypes,
+	}
+	for _, o := range opts {
+		o(u)
+	}
+	return u
+}
+
+// MaxSize is the largest file, in bytes, Upload accepts.
+func (u *Attachment) MaxSize() int64 {
+	return u.maxSize
+}
+
+func (u *Attachment) List(ctx context.Context, todoID string) (ListAttachmentsOutput, error) {
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return ListAttachmentsOutput{}, err
+	}
+
+	if _, err := getTodo(ctx, u.todos, todoID); err != nil {
+		return ListAttachmentsOutput{}, err
+	}
+
+	attachments, err := u.attachments.List(ctx, todoID)
+	if err != nil {
+		return ListAttachmentsOutput{}, err
+	}
+
+	return ListAttachmentsOutput{Attachments: attachments}, nil
+}
+
+// Upload stores the file and attaches it to the todo. The content type is sniffed from
+// the first bytes of the content, whatever the client declared, and must be one of the
+// accepted types. The content is stored before its metadata, and removed again when the
+// metadata cannot be saved.
+func (u *Attachment) Upload(ctx context.Context, todoID string, input UploadInput) (AttachmentOutput, error) {
+	if err := u.policy.Authorize(ctx, policy.ActionAttach); err != nil {
+		return AttachmentOutput{}, err
+	}
+
+	if _, err := getTodo(ctx, u.todos, todoID); err != nil {
+		return AttachmentOutput{}, err
+	}
+
+	filename, err := domain.CleanAttachmentName(input.Filename)
+	if err != nil {
+		return AttachmentOutput{}, err
+	}
+	if input.Size > u.maxSize {
+		return AttachmentOutput{}, domain.ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(input.Content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return AttachmentOutput{}, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if !domain.MediaTypeAllowed(contentType, u.types) {
+		return AttachmentOutput{}, domain.ErrUnsupportedAttachmentType
+	}
+
+	key, err := newStorageKey(todoID)
+	if err != nil {
+		return AttachmentOutput{}, err
+	}
+
+	content := &sizeLimitReader{r: io.MultiReader(bytes.NewReader(head), input.Content), limit: u.maxSize}
+	if err := u.blobs.Put(ctx, key, content); err != nil {
+		return AttachmentOutput{}, err
+	}
+
+	attachment, err := u.attachments.Create(ctx, service.AttachmentInput{
+		TodoID:      todoID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        content.read,
+		StorageKey:  key,
+	})
+	if err != nil {
+		_ = u.blobs.Delete(ctx, key) // safe mute, an orphan blob is harmless and the create error matters more
+		return AttachmentOutput{}, err
+	}
+
+	return AttachmentOutput{Attachment: attachment}, nil
+}
+
+func (u *Attachment) Download(ctx context.Context, todoID, id string) (DownloadOutput, error) {
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return DownloadOutput{}, err
+	}
+
+	if _, err := getTodo(ctx, u.todos, todoID); err != nil {
+		return DownloadOutput{}, err
+	}
+
+	attachment, err := u.attachments.GetByID(ctx, todoID, id)
+	if err != nil {
+		return DownloadOutput{}, err
+	}
+
+	content, err := u.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, service.ErrBlobNotFound) {
+			return DownloadOutput{}, domain.ErrAttachmentNotFound
+		}
+		return DownloadOutput{}, err
+	}
+
+	return DownloadOutput{Attachment: attachment, Content: content}, nil
+}
+
+// newStorageKey picks a random blob key grouped under the todo.
+func newStorageKey(todoID string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return todoID + "/" + hex.EncodeToString(b), nil
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n, domain.ErrAttachmentTooLarge
+	}
+	return n, err
+}
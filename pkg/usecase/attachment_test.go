@@ -0,0 +1,149 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func newTestAttachmentUsecase(attachments *test.MockAttachmentService, blobs *test.MockBlobStore, opts ...usecase.AttachmentOption) *usecase.Attachment {
+	todos := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	return usecase.NewAttachment(attachments, blobs, todos, opts...)
+}
+
+func TestAttachment_Upload_SniffsContentType(t *testing.T) {
+	blobs := test.NewMockBlobStore()
+	var captured service.AttachmentInput
+	uc := newTestAttachmentUsecase(&test.MockAttachmentService{
+		CreateFn: func(ctx context.Context, input service.AttachmentInput) (domain.Attachment, error) {
+			captured = input
+			return domain.Attachment{ID: commentUUID, TodoID: input.TodoID}, nil
+		},
+	}, blobs)
+	content := append(append([]byte{}, pngHeader...), "pixels"...)
+
+	_, err := uc.Upload(context.Background(), validUUID, usecase.UploadInput{
+		Filename: "C:\\shots\\screen.txt",
+		Size:     int64(len(content)),
+		Content:  bytes.NewReader(content),
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if captured.ContentType != "image/png" || captured.Filename != "screen.txt" || captured.Size != int64(len(content)) {
+		t.Errorf("expected a png named screen.txt, got %+v", captured)
+	}
+	if !strings.HasPrefix(captured.StorageKey, validUUID+"/") || !bytes.Equal(blobs.Blobs[captured.StorageKey], content) {
+		t.Errorf("expected the content under a key of the todo, got %q", captured.StorageKey)
+	}
+}
+
+func TestAttachment_Upload_RejectsUnsupportedType(t *testing.T) {
+	uc := newTestAttachmentUsecase(&test.MockAttachmentService{}, test.NewMockBlobStore())
+
+	_, err := uc.Upload(context.Background(), validUUID, usecase.UploadInput{
+		Filename: "page.txt",
+		Size:     30,
+		Content:  strings.NewReader("<html><body>hi</body></html>"),
+	})
+
+	if !errors.Is(err, domain.ErrUnsupportedAttachmentType) {
+		t.Errorf("expected ErrUnsupportedAttachmentType, got %v", err)
+	}
+}
+
+func TestAttachment_Upload_RejectsContentLargerThanAnnounced(t *testing.T) {
+	blobs := test.NewMockBlobStore()
+	uc := newTestAttachmentUsecase(&test.MockAttachmentService{}, blobs, usecase.WithMaxAttachmentSize(600))
+
+	_, err := uc.Upload(context.Background(), validUUID, usecase.UploadInput{
+		Filename: "notes.txt",
+		Size:     10,
+		Content:  strings.NewReader(strings.Repeat("a", 601)),
+	})
+
+	if !errors.Is(err, domain.ErrAttachmentTooLarge) {
+		t.Errorf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if len(blobs.Blobs) != 0 {
+		t.Errorf("expected nothing to be stored, got %d blobs", len(blobs.Blobs))
+	}
+}
+
+func TestAttachment_Upload_RejectsAnnouncedSize(t *testing.T) {
+	uc := newTestAttachmentUsecase(&test.MockAttachmentService{}, test.NewMockBlobStore(), usecase.WithMaxAttachmentSize(10))
+
+	_, err := uc.Upload(context.Background(), validUUID, usecase.UploadInput{Filename: "notes.txt", Size: 11, Content: strings.NewReader("")})
+
+	if !errors.Is(err, domain.ErrAttachmentTooLarge) {
+		t.Errorf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+}
+
+func TestAttachment_Upload_RemovesBlobWhenMetadataFails(t *testing.T) {
+	blobs := test.NewMockBlobStore()
+	createErr := errors.New("database unavailable")
+	uc := newTestAttachmentUsecase(&test.MockAttachmentService{
+		CreateFn: func(ctx context.Context, input service.AttachmentInput) (domain.Attachment, error) {
+			return domain.Attachment{}, createErr
+		},
+	}, blobs)
+
+	_, err := uc.Upload(context.Background(), validUUID, usecase.UploadInput{Filename: "notes.txt", Size: 5, Content: strings.NewReader("notes")})
+
+	if !errors.Is(err, createErr) {
+		t.Errorf("expected error %v, got %v", createErr, err)
+	}
+	if len(blobs.Blobs) != 0 {
+		t.Errorf("expected the blob to be removed, got %d blobs", len(blobs.Blobs))
+	}
+}
+
+func TestAttachment_Download_MissingBlob(t *testing.T) {
+	uc := newTestAttachmentUsecase(&test.MockAttachmentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Attachment, error) {
+			return domain.Attachment{ID: id, TodoID: todoID, StorageKey: todoID + "/gone"}, nil
+		},
+	}, test.NewMockBlobStore())
+
+	_, err := uc.Download(context.Background(), validUUID, commentUUID)
+
+	if !errors.Is(err, domain.ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound, got %v", err)
+	}
+}
+
+func TestAttachment_Download_ReturnsContent(t *testing.T) {
+	blobs := test.NewMockBlobStore()
+	blobs.Blobs[validUUID+"/key"] = []byte("notes")
+	uc := newTestAttachmentUsecase(&test.MockAttachmentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Attachment, error) {
+			return domain.Attachment{ID: id, TodoID: todoID, StorageKey: todoID + "/key"}, nil
+		},
+	}, blobs)
+
+	output, err := uc.Download(context.Background(), validUUID, commentUUID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer output.Content.Close()
+
+	if b, _ := io.ReadAll(output.Content); string(b) != "notes" {
+		t.Errorf("expected the stored content, got %q", b)
+	}
+}
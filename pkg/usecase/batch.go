@@ -0,0 +1,596 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+const MaxBatchSize = 1000
+
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+type (
+	BatchOp string
+
+	// BatchOperation is one item of a batch. ID is only used by updates and deletes,
+	// and only the input matching Op is read. Deletes always use domain.ChildrenRestrict.
+	// Completing a recurring todo spawns its next occurrence, as Update does.
+	BatchOperation struct {
+		Op     BatchOp
+		ID     string
+		Create CreateInput
+		Update UpdateInput
+		Delete DeleteInput
+	}
+
+	// BatchInput lists the operations to run. When Atomic is set either every
+	// operation is applied or none is; otherwise each one succeeds or fails on its own.
+	BatchInput struct {
+		Operations []BatchOperation
+		Atomic     bool
+	}
+
+	// BatchResult is the outcome of the operation at the same index. Todo is set
+	// for successful creates and updates, and Next for updates that spawned the
+	// next occurrence of a recurring todo.
+	BatchResult struct {
+		Todo *domain.Todo
+		Next *domain.Todo
+		Err  error
+	}
+
+	BatchOutput struct {
+		Results []BatchResult
+	}
+)
+
+// Batch runs the operations grouped by kind, issuing one statement per kind. In atomic
+// mode everything happens in one transaction; once an operation fails the remaining
+// ones are skipped and every operation that did not fail reports domain.ErrBatchAborted.
+// Otherwise the creates or updates whose grouped statement fails are run again one at a
+// time, so that each reports its own outcome instead of the error of another one.
+func (u *Todo) Batch(ctx context.Context, input BatchInput) (BatchOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.Batch")
+	defer span.End()
+
+	if len(input.Operations) == 0 || len(input.Operations) > MaxBatchSize {
+		return BatchOutput{}, domain.ErrInvalidBatchSize
+	}
+
+	// the whole batch is denied when any of its operations is
+	actions := map[BatchOp]policy.Action{
+		BatchCreate: policy.ActionCreate,
+		BatchUpdate: policy.ActionUpdate,
+		BatchDelete: policy.ActionDelete,
+	}
+	for _, op := range input.Operations {
+		if action, ok := actions[op.Op]; ok {
+			if err := u.policy.Authorize(ctx, action); err != nil {
+				return BatchOutput{}, err
+			}
+		}
+	}
+
+	seen := make(map[string]struct{}, len(input.Operations))
+	for _, op := range input.Operations {
+		if op.Op == BatchCreate {
+			continue
+		}
+		if _, ok := seen[op.ID]; ok {
+			return BatchOutput{}, domain.ErrDuplicateBatchID
+		}
+		seen[op.ID] = struct{}{}
+	}
+
+	// runBatch resolves the assignees in place
+	ops := slices.Clone(input.Operations)
+
+	if !input.Atomic {
+		return BatchOutput{Results: u.runBatch(ctx, u.service, ops, false)}, nil
+	}
+
+	var results []BatchResult
+	err := u.service.Transaction(ctx, func(svc service.Todo) error {
+		results = u.runBatch(ctx, svc, ops, true)
+		if anyFailed(results) {
+			return domain.ErrBatchAborted
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, domain.ErrBatchAborted) {
+		return BatchOutput{}, err
+	}
+
+	if err != nil {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = BatchResult{Err: domain.ErrBatchAborted}
+			}
+		}
+	}
+
+	return BatchOutput{Results: results}, nil
+}
+
+// runBatch resolves assignees and checks visibility, parents and subtasks, then creates,
+// updates and deletes. The status changes and tags of the updates are checked by updateBatch,
+// on the todos it locks. When stopOnError is set a failing step prevents the following ones, whose
+// operations get ErrBatchAborted. Each step records its changes in the history within its own
+// transaction, or within the batch one when atomic.
+func (u *Todo) runBatch(ctx context.Context, svc service.Todo, ops []BatchOperation, stopOnError bool) []BatchResult {
+	results := make([]BatchResult, len(ops))
+
+	var creates, updates, deletes []int
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case BatchCreate:
+			if ops[i].Create.AssigneeID, err = resolveAssignee(ctx, op.Create.AssigneeID); err != nil {
+				results[i].Err = err
+				continue
+			}
+			creates = append(creates, i)
+		case BatchUpdate:
+			if ops[i].Update.AssigneeID, err = resolveAssignee(ctx, op.Update.AssigneeID); err != nil {
+				results[i].Err = err
+				continue
+			}
+			updates = append(updates, i)
+		case BatchDelete:
+			deletes = append(deletes, i)
+		default:
+			results[i].Err = domain.ErrInvalidBatchOp
+		}
+	}
+
+	updates, deletes = checkBatchVisibility(ctx, svc, ops, updates, deletes, results)
+	creates = checkBatchParents(ctx, svc, ops, creates, results)
+	updates = checkBatchReparents(ctx, svc, ops, updates, results)
+	deletes = checkBatchDeletes(ctx, svc, ops, deletes, results)
+	if stopOnError && anyFailed(results) {
+		abortBatch(results, creates, updates, deletes)
+		return results
+	}
+
+	u.createBatch(ctx, svc, ops, creates, results, !stopOnError)
+	if stopOnError && anyFailed(results) {
+		abortBatch(results, updates, deletes)
+		return results
+	}
+
+	u.updateBatch(ctx, svc, ops, updates, results, !stopOnError)
+	if stopOnError && anyFailed(results) {
+		abortBatch(results, deletes)
+		return results
+	}
+
+	u.deleteBatch(ctx, svc, ops, deletes, results)
+
+	return results
+}
+
+// checkBatchVisibility reports the update and delete operations targeting todos hidden from
+// the acting user as domain.ErrTodoNotFound, fetching the todos in one query. Missing todos
+// are left to the following steps. It returns the updates and deletes that may proceed.
+func checkBatchVisibility(ctx context.Context, svc service.Todo, ops []BatchOperation, updates, deletes []int, results []BatchResult) ([]int, []int) {
+	user := actingUser(ctx)
+	if user == "" || len(updates)+len(deletes) == 0 {
+		return updates, deletes
+	}
+
+	ids := make([]string, 0, len(updates)+len(deletes))
+	for _, idx := range [][]int{updates, deletes} {
+		for _, i := range idx {
+			ids = append(ids, ops[i].ID)
+		}
+	}
+
+	todos, err := svc.GetByIDs(ctx, ids)
+	if err != nil {
+		for _, idx := range [][]int{updates, deletes} {
+			for _, i := range idx {
+				results[i].Err = err
+			}
+		}
+		return []int{}, []int{}
+	}
+
+	hidden := make(map[string]struct{})
+	for _, todo := range todos {
+		if !todo.VisibleTo(user) {
+			hidden[todo.ID] = struct{}{}
+		}
+	}
+
+	keep := func(idx []int) []int {
+		valid := make([]int, 0, len(idx))
+		for _, i := range idx {
+			if _, ok := hidden[ops[i].ID]; ok {
+				results[i].Err = domain.ErrTodoNotFound
+				continue
+			}
+			valid = append(valid, i)
+		}
+		return valid
+	}
+	return keep(updates), keep(deletes)
+}
+
+// checkBatchParents verifies, in one query, that the parents of the create operations at
+// idx exist and are visible to the acting user. It returns the creates that may proceed.
+func checkBatchParents(ctx context.Context, svc service.Todo, ops []BatchOperation, idx []int, results []BatchResult) []int {
+	var ids []string
+	for _, i := range idx {
+		if ops[i].Create.ParentID != nil {
+			ids = append(ids, *ops[i].Create.ParentID)
+		}
+	}
+	if len(ids) == 0 {
+		return idx
+	}
+
+	todos, err := svc.GetByIDs(ctx, ids)
+	if err != nil {
+		for _, i := range idx {
+			results[i].Err = err
+		}
+		return []int{}
+	}
+
+	exists := make(map[string]struct{}, len(todos))
+	for _, todo := range todos {
+		if todo.VisibleTo(actingUser(ctx)) {
+			exists[todo.ID] = struct{}{}
+		}
+	}
+
+	valid := make([]int, 0, len(idx))
+	for _, i := range idx {
+		if parentID := ops[i].Create.ParentID; parentID != nil {
+			if _, ok := exists[*parentID]; !ok {
+				results[i].Err = domain.ErrInvalidParent
+				continue
+			}
+		}
+		valid = append(valid, i)
+	}
+	return valid
+}
+
+// checkBatchReparents runs the cycle check of the update operations at idx that move a todo
+// under a new parent. Each check walks the ancestors of the new parent, so unlike the other
+// checks it costs one query per reparented todo. It returns the updates that may proceed.
+func checkBatchReparents(ctx context.Context, svc service.Todo, ops []BatchOperation, idx []int, results []BatchResult) []int {
+	valid := make([]int, 0, len(idx))
+	for _, i := range idx {
+		op := ops[i]
+		if op.Update.ParentID != nil && !op.Update.ClearParent {
+			if err := checkParent(ctx, svc, op.ID, *op.Update.ParentID); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+		valid = append(valid, i)
+	}
+	return valid
+}
+
+// checkBatchDeletes applies the restrict children policy to the delete operations at idx,
+// fetching the todos in one query. It returns the deletes that may proceed.
+func checkBatchDeletes(ctx context.Context, svc service.Todo, ops []BatchOperation, idx []int, results []BatchResult) []int {
+	if len(idx) == 0 {
+		return idx
+	}
+
+	ids := make([]string, len(idx))
+	for j, i := range idx {
+		ids[j] = ops[i].ID
+	}
+
+	todos, err := svc.GetByIDs(ctx, ids)
+	if err != nil {
+		for _, i := range idx {
+			results[i].Err = err
+		}
+		return []int{}
+	}
+
+	parents := make(map[string]struct{}, len(todos))
+	for _, todo := range todos {
+		if todo.Subtasks != nil && todo.Subtasks.Total > 0 {
+			parents[todo.ID] = struct{}{}
+		}
+	}
+
+	valid := make([]int, 0, len(idx))
+	for _, i := range idx {
+		if _, ok := parents[ops[i].ID]; ok {
+			results[i].Err = domain.ErrTodoHasChildren
+			continue
+		}
+		valid = append(valid, i)
+	}
+	return valid
+}
+
+// createBatch creates the todos of the create operations at idx with one statement. When
+// retryAlone is set and that fails, each operation is run again on its own.
+func (u *Todo) createBatch(ctx context.Context, svc service.Todo, ops []BatchOperation, idx []int, results []BatchResult, retryAlone bool) {
+	if len(idx) == 0 {
+		return
+	}
+
+	inputs := make([]service.CreateInput, len(idx))
+	for j, i := range idx {
+		inputs[j] = newServiceCreateInput(ctx, ops[i].Create)
+	}
+
+	var todos []domain.Todo
+	err := svc.Transaction(ctx, func(svc service.Todo) error {
+		var err error
+		todos, err = svc.CreateBatch(ctx, inputs)
+		if err != nil {
+			return err
+		}
+
+		var tags []service.TodoTags
+		entries := make([]domain.HistoryEntry, len(idx))
+		for j, i := range idx {
+			if len(ops[i].Create.Tags) > 0 {
+				todos[j].Tags = ops[i].Create.Tags
+				tags = append(tags, service.TodoTags{TodoID: todos[j].ID, Names: todos[j].Tags})
+			}
+			entries[j] = newHistoryEntry(ctx, domain.HistoryCreate, todos[j].ID, domain.Todo{}, todos[j])
+		}
+		if len(tags) > 0 {
+			if err := svc.AddTags(ctx, tags); err != nil {
+				return err
+			}
+		}
+		return addHistory(ctx, svc, entries...)
+	})
+	if err != nil && retryAlone && len(idx) > 1 {
+		for _, i := range idx {
+			u.createBatch(ctx, svc, ops, []int{i}, results, false)
+		}
+		return
+	}
+
+	for j, i := range idx {
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		todo := todos[j]
+		results[i].Todo = &todo
+	}
+}
+
+// updateBatch applies the update operations at idx with one statement. When retryAlone is
+// set and the transaction fails, each operation is run again on its own.
+func (u *Todo) updateBatch(ctx context.Context, svc service.Todo, ops []BatchOperation, idx []int, results []BatchResult, retryAlone bool) {
+	if len(idx) == 0 {
+		return
+	}
+
+	ids := make([]string, len(idx))
+	items := make([]service.BatchUpdate, len(idx))
+	for j, i := range idx {
+		ids[j] = ops[i].ID
+		items[j] = service.BatchUpdate{ID: ops[i].ID, Input: newServiceUpdateInput(ops[i].Update)}
+	}
+
+	out := make([]service.BatchResult, len(idx))
+	next := make([]*domain.Todo, len(idx))
+	err := svc.Transaction(ctx, func(svc service.Todo) error {
+		// the states before the update, for the checks, the history and the recurrences,
+		// read under lock so that no concurrent change slips between the checks and the
+		// update, and a todo completed concurrently spawns a single occurrence
+		if err := svc.Lock(ctx, ids); err != nil {
+			return err
+		}
+		todos, err := svc.GetByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		before := make(map[string]domain.Todo, len(todos))
+		for _, todo := range todos {
+			before[todo.ID] = todo
+		}
+
+		// the positions of the updates passing the checks; the other missing todos are
+		// reported by UpdateBatch
+		var pending []int
+		rules := make([]*domain.Recurrence, len(idx))
+		for j, i := range idx {
+			update := ops[i].Update
+			current, ok := before[items[j].ID]
+			if err := u.checkBatchUpdate(current, ok, update); err != nil {
+				out[j].Err = err
+				continue
+			}
+			pending = append(pending, j)
+			if !ok || update.Status == nil || *update.Status != domain.StatusCompleted || current.Status == domain.StatusCompleted {
+				continue
+			}
+			if rules[j] = recurrenceAfterUpdate(current, update); rules[j] != nil {
+				items[j].Input.Recurrence = nil
+				items[j].Input.ClearRecurrence = true
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		batch := make([]service.BatchUpdate, len(pending))
+		for k, j := range pending {
+			batch[k] = items[j]
+		}
+		updated, err := svc.UpdateBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for k, j := range pending {
+			out[j] = updated[k]
+		}
+		if err := updateBatchTags(ctx, svc, ops, idx, out); err != nil {
+			return err
+		}
+
+		var entries []domain.HistoryEntry
+		for j, item := range items {
+			if out[j].Err == nil {
+				entries = append(entries, newHistoryEntry(ctx, domain.HistoryUpdate, item.ID, before[item.ID], out[j].Todo))
+			}
+		}
+		if err := addHistory(ctx, svc, entries...); err != nil {
+			return err
+		}
+
+		for j, rule := range rules {
+			if rule == nil || out[j].Err != nil {
+				continue
+			}
+			if next[j], err = spawnNextOccurrence(ctx, svc, out[j].Todo, *rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && retryAlone && len(idx) > 1 {
+		for _, i := range idx {
+			u.updateBatch(ctx, svc, ops, []int{i}, results, false)
+		}
+		return
+	}
+
+	for j, i := range idx {
+		switch {
+		case err != nil:
+			results[i].Err = err
+		case out[j].Err != nil:
+			results[i].Err = out[j].Err
+		default:
+			todo := out[j].Todo
+			results[i].Todo = &todo
+			results[i].Next = next[j]
+		}
+	}
+}
+
+// checkBatchUpdate validates the status change and tag additions of an update against the
+// current todo, which found reports the existence of.
+func (u *Todo) checkBatchUpdate(current domain.Todo, found bool, update UpdateInput) error {
+	if update.Status == nil && len(update.AddTags) == 0 {
+		return nil
+	}
+	if !found {
+		return domain.ErrTodoNotFound
+	}
+	if update.Status != nil {
+		if err := u.checkStatusChange(current, update); err != nil {
+			return err
+		}
+	}
+	if len(domain.ApplyTags(current.Tags, update.AddTags, update.RemoveTags)) > domain.MaxTags {
+		return domain.ErrTooManyTags
+	}
+	return nil
+}
+
+// updateBatchTags applies the tag changes of the successful updates with one statement per
+// kind of change, and sets the resulting tags on their todos.
+func updateBatchTags(ctx context.Context, svc service.Todo, ops []BatchOperation, idx []int, out []service.BatchResult) error {
+	var add, remove []service.TodoTags
+	for j, i := range idx {
+		update := ops[i].Update
+		if out[j].Err != nil || len(update.AddTags)+len(update.RemoveTags) == 0 {
+			continue
+		}
+		if len(update.RemoveTags) > 0 {
+			remove = append(remove, service.TodoTags{TodoID: ops[i].ID, Names: update.RemoveTags})
+		}
+		if len(update.AddTags) > 0 {
+			add = append(add, service.TodoTags{TodoID: ops[i].ID, Names: update.AddTags})
+		}
+		out[j].Todo.Tags = domain.ApplyTags(out[j].Todo.Tags, update.AddTags, update.RemoveTags)
+	}
+
+	if len(remove) > 0 {
+		if err := svc.RemoveTags(ctx, remove); err != nil {
+			return err
+		}
+	}
+	if len(add) > 0 {
+		return svc.AddTags(ctx, add)
+	}
+	return nil
+}
+
+func (u *Todo) deleteBatch(ctx context.Context, svc service.Todo, ops []BatchOperation, idx []int, results []BatchResult) {
+	if len(idx) == 0 {
+		return
+	}
+
+	items := make([]service.BatchDelete, len(idx))
+	for j, i := range idx {
+		items[j] = service.BatchDelete{
+			ID:    ops[i].ID,
+			Input: service.DeleteInput{ExpectedVersion: ops[i].Delete.ExpectedVersion},
+		}
+	}
+
+	var out []service.BatchResult
+	err := svc.Transaction(ctx, func(svc service.Todo) error {
+		var err error
+		out, err = svc.DeleteBatch(ctx, items)
+		if err != nil {
+			return err
+		}
+
+		var entries []domain.HistoryEntry
+		for j, item := range items {
+			if out[j].Err == nil {
+				entries = append(entries, newHistoryEntry(ctx, domain.HistoryDelete, item.ID, out[j].Todo, domain.Todo{}))
+			}
+		}
+		return addHistory(ctx, svc, entries...)
+	})
+	for j, i := range idx {
+		switch {
+		case err != nil:
+			results[i].Err = err
+		case out[j].Err != nil:
+			results[i].Err = out[j].Err
+		}
+	}
+}
+
+// abortBatch marks the operations of the skipped steps as not applied.
+func abortBatch(results []BatchResult, groups ...[]int) {
+	for _, idx := range groups {
+		for _, i := range idx {
+			if results[i].Err == nil {
+				results[i].Err = domain.ErrBatchAborted
+			}
+		}
+	}
+}
+
+func anyFailed(results []BatchResult) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
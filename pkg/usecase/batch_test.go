@@ -0,0 +1,373 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+func TestTodo_Batch_RejectsEmptyBatch(t *testing.T) {
+	uc := usecase.New(&test.MockTodoService{})
+
+	_, err := uc.Batch(context.Background(), usecase.BatchInput{})
+
+	if !errors.Is(err, domain.ErrInvalidBatchSize) {
+		t.Errorf("expected ErrInvalidBatchSize, got %v", err)
+	}
+}
+
+func TestTodo_Batch_RejectsOversizedBatch(t *testing.T) {
+	uc := usecase.New(&test.MockTodoService{})
+	ops := make([]usecase.BatchOperation, usecase.MaxBatchSize+1)
+
+	_, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: ops})
+
+	if !errors.Is(err, domain.ErrInvalidBatchSize) {
+		t.Errorf("expected ErrInvalidBatchSize, got %v", err)
+	}
+}
+
+func TestTodo_Batch_RejectsDuplicateIDs(t *testing.T) {
+	uc := usecase.New(&test.MockTodoService{})
+
+	_, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchUpdate, ID: validUUID},
+		{Op: usecase.BatchDelete, ID: validUUID},
+	}})
+
+	if !errors.Is(err, domain.ErrDuplicateBatchID) {
+		t.Errorf("expected ErrDuplicateBatchID, got %v", err)
+	}
+}
+
+func TestTodo_Batch_PartialKeepsSuccessfulOperations(t *testing.T) {
+	todo := buildValidTodo()
+	mock := &test.MockTodoService{
+		CreateBatchFn: func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+			if inputs[0].Status != domain.StatusPending || inputs[0].Priority != domain.PriorityMedium {
+				t.Errorf("expected default status and priority, got %+v", inputs[0])
+			}
+			return []domain.Todo{todo}, nil
+		},
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		DeleteBatchFn: func(ctx context.Context, items []service.BatchDelete) ([]service.BatchResult, error) {
+			return []service.BatchResult{{Err: domain.ErrTodoNotFound}}, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchDelete, ID: nonExistentID},
+		{Op: usecase.BatchCreate, Create: usecase.CreateInput{Title: validTitle}},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(result.Results[0].Err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", result.Results[0].Err)
+	}
+	if result.Results[1].Err != nil || result.Results[1].Todo == nil || result.Results[1].Todo.ID != todo.ID {
+		t.Errorf("expected created todo, got %+v", result.Results[1])
+	}
+}
+
+func TestTodo_Batch_AtomicAbortsOnFailure(t *testing.T) {
+	var mock *test.MockTodoService
+	mock = &test.MockTodoService{
+		TransactionFn: func(ctx context.Context, fn func(service.Todo) error) error {
+			return fn(mock)
+		},
+		CreateBatchFn: func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		UpdateBatchFn: func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+			return []service.BatchResult{{Err: domain.ErrVersionMismatch}}, nil
+		},
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		DeleteBatchFn: func(ctx context.Context, items []service.BatchDelete) ([]service.BatchResult, error) {
+			t.Error("expected deletes to be skipped after a failed update")
+			return nil, nil
+		},
+	}
+	uc := usecase.New(mock)
+	title := updatedTitle
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{
+		Atomic: true,
+		Operations: []usecase.BatchOperation{
+			{Op: usecase.BatchCreate, Create: usecase.CreateInput{Title: validTitle}},
+			{Op: usecase.BatchUpdate, ID: validUUID, Update: usecase.UpdateInput{Title: &title}},
+			{Op: usecase.BatchDelete, ID: nonExistentID},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(result.Results[0].Err, domain.ErrBatchAborted) {
+		t.Errorf("expected create to be aborted, got %v", result.Results[0].Err)
+	}
+	if !errors.Is(result.Results[1].Err, domain.ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch, got %v", result.Results[1].Err)
+	}
+	if !errors.Is(result.Results[2].Err, domain.ErrBatchAborted) {
+		t.Errorf("expected delete to be aborted, got %v", result.Results[2].Err)
+	}
+}
+
+func TestTodo_Batch_RejectsIllegalTransition(t *testing.T) {
+	completed := buildValidTodo()
+	completed.Status = domain.StatusCompleted
+	mock := &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return []domain.Todo{completed}, nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusPending
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchUpdate, ID: validUUID, Update: usecase.UpdateInput{Status: &status}},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(result.Results[0].Err, domain.ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition, got %v", result.Results[0].Err)
+	}
+}
+
+func TestTodo_Batch_ChecksTransitionsOnTheLockedTodos(t *testing.T) {
+	// the todo is completed concurrently, between a read without the lock and the update
+	locked := false
+	mock := &test.MockTodoService{
+		LockFn: func(ctx context.Context, ids []string) error {
+			locked = true
+			return nil
+		},
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Status = domain.StatusInProgress
+			if locked {
+				todo.Status = domain.StatusCompleted
+			}
+			return []domain.Todo{todo}, nil
+		},
+		UpdateBatchFn: func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+			t.Error("expected the illegal transition not to be applied")
+			return nil, nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusPending
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchUpdate, ID: validUUID, Update: usecase.UpdateInput{Status: &status}},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(result.Results[0].Err, domain.ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition, got %v", result.Results[0].Err)
+	}
+}
+
+func TestTodo_Batch_UpdatesTagsOfSuccessfulUpdates(t *testing.T) {
+	var added []service.TodoTags
+	mock := &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		UpdateBatchFn: func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+			return []service.BatchResult{{Todo: buildValidTodo()}}, nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			added = items
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchUpdate, ID: validUUID, Update: usecase.UpdateInput{AddTags: []string{"backend"}}},
+		{Op: usecase.BatchUpdate, ID: nonExistentID, Update: usecase.UpdateInput{AddTags: []string{"backend"}}},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(added) != 1 || added[0].TodoID != validUUID {
+		t.Errorf("expected tags to be added to the updated todo only, got %+v", added)
+	}
+	if len(result.Results[0].Todo.Tags) != 1 {
+		t.Errorf("expected the updated todo to carry its tag, got %+v", result.Results[0].Todo)
+	}
+	if !errors.Is(result.Results[1].Err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", result.Results[1].Err)
+	}
+}
+
+func TestTodo_Batch_CompletingRecurringTodoSpawnsNextOccurrence(t *testing.T) {
+	var updated []service.BatchUpdate
+	var created service.CreateInput
+	mock := &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return []domain.Todo{buildRecurringTodo(t, "FREQ=WEEKLY;COUNT=3")}, nil
+		},
+		UpdateBatchFn: func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+			updated = items
+			todo := buildRecurringTodo(t, "FREQ=WEEKLY;COUNT=3")
+			todo.Status = domain.StatusCompleted
+			todo.Recurrence = nil
+			return []service.BatchResult{{Todo: todo}}, nil
+		},
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			created = input
+			return domain.Todo{ID: nextUUID, Title: input.Title, Status: input.Status, DueAt: input.DueAt, Recurrence: input.Recurrence}, nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusCompleted
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchUpdate, ID: validUUID, Update: usecase.UpdateInput{Status: &status}},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(updated) != 1 || !updated[0].Input.ClearRecurrence {
+		t.Errorf("expected the completed todo to hand its rule over, got %+v", updated)
+	}
+	if next := result.Results[0].Next; next == nil || next.ID != nextUUID {
+		t.Fatalf("expected the next occurrence, got %+v", next)
+	}
+	if created.Recurrence == nil || created.Recurrence.Count != 2 {
+		t.Errorf("expected 2 occurrences left, got %+v", created.Recurrence)
+	}
+}
+
+func TestTodo_Batch_PartialReportsEachCreateOwnError(t *testing.T) {
+	constraintErr := errors.New("value too long for type character varying(255)")
+	var statements int
+	mock := &test.MockTodoService{
+		CreateBatchFn: func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+			statements++
+			for _, input := range inputs {
+				if input.Title == "bad" {
+					return nil, constraintErr
+				}
+			}
+			todos := make([]domain.Todo, len(inputs))
+			for i, input := range inputs {
+				todos[i] = domain.Todo{ID: validUUID, Title: input.Title}
+			}
+			return todos, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchCreate, Create: usecase.CreateInput{Title: validTitle}},
+		{Op: usecase.BatchCreate, Create: usecase.CreateInput{Title: "bad"}},
+		{Op: usecase.BatchCreate, Create: usecase.CreateInput{Title: updatedTitle}},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if statements != 4 {
+		t.Errorf("expected the grouped statement then one per create, got %d statements", statements)
+	}
+	if !errors.Is(result.Results[1].Err, constraintErr) {
+		t.Errorf("expected the failing create to report its error, got %v", result.Results[1].Err)
+	}
+	for _, i := range []int{0, 2} {
+		if result.Results[i].Err != nil || result.Results[i].Todo == nil {
+			t.Errorf("expected create %d to succeed, got %+v", i, result.Results[i])
+		}
+	}
+}
+
+func TestTodo_Batch_PartialReportsEachUpdateOwnError(t *testing.T) {
+	constraintErr := errors.New("value too long for type character varying(255)")
+	mock := &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		UpdateBatchFn: func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+			out := make([]service.BatchResult, len(items))
+			for i, item := range items {
+				if item.ID == nonExistentID {
+					return nil, constraintErr
+				}
+				out[i] = service.BatchResult{Todo: domain.Todo{ID: item.ID, Title: *item.Input.Title}}
+			}
+			return out, nil
+		},
+	}
+	uc := usecase.New(mock)
+	title := updatedTitle
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchUpdate, ID: validUUID, Update: usecase.UpdateInput{Title: &title}},
+		{Op: usecase.BatchUpdate, ID: nonExistentID, Update: usecase.UpdateInput{Title: &title}},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Results[0].Err != nil || result.Results[0].Todo == nil || result.Results[0].Todo.Title != updatedTitle {
+		t.Errorf("expected the first update to succeed, got %+v", result.Results[0])
+	}
+	if !errors.Is(result.Results[1].Err, constraintErr) {
+		t.Errorf("expected the failing update to report its error, got %v", result.Results[1].Err)
+	}
+}
+
+func TestTodo_Batch_AtomicDoesNotRetryAlone(t *testing.T) {
+	constraintErr := errors.New("value too long for type character varying(255)")
+	var statements int
+	mock := &test.MockTodoService{
+		CreateBatchFn: func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+			statements++
+			return nil, constraintErr
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Batch(context.Background(), usecase.BatchInput{
+		Atomic: true,
+		Operations: []usecase.BatchOperation{
+			{Op: usecase.BatchCreate, Create: usecase.CreateInput{Title: validTitle}},
+			{Op: usecase.BatchCreate, Create: usecase.CreateInput{Title: "bad"}},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if statements != 1 {
+		t.Errorf("expected a single statement, got %d", statements)
+	}
+	for i, r := range result.Results {
+		if !errors.Is(r.Err, constraintErr) {
+			t.Errorf("expected create %d to fail with the transaction, got %v", i, r.Err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+// commentCursorSort tags comment cursors so that they cannot be replayed on other listings.
+const commentCursorSort = "comments"
+
+type (
+	ListCommentsInput struct {
+		Limit  int
+		Cursor string
+	}
+
+	ListCommentsOutput struct {
+		Comments   []domain.Comment
+		Total      int
+		NextCursor string
+	}
+
+	CommentOutput struct {
+		Comment domain.Comment
+	}
+
+	// Comment manages the discussion threads of todos. Comments are only reachable
+	// while their todo is live: a todo in the trash keeps its comments, which come back
+	// when it is restored, and purging the todo removes them for good.
+	Comment struct {
+		comments service.Comment
+		todos    service.Todo
+		policy   *policy.Policy
+	}
+
+	CommentOption func(*Comment)
+)
+
+// WithCommentPolicy checks every action against the policy before performing it.
+func WithCommentPolicy(p *policy.Policy) CommentOption {
+	return func(u *Comment) {
+		u.policy = p
+	}
+}
+
+func NewComment(comments service.Comment, todos service.Todo, opts ...CommentOption) *Comment {
+	u := &Comment{
+		comments: comments,
+		todos:    todos,
+	}
+	for _, o := range opts {
+		o(u)
+	}
+	return u
+}
+
+// List returns a page of the comments of the todo, oldest first.
+func (u *Comment) List(ctx context.Context, todoID string, input ListCommentsInput) (ListCommentsOutput, error) {
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return ListCommentsOutput{}, err
+	}
+
+	if _, err := getTodo(ctx, u.todos, todoID); err != nil {
+		return ListCommentsOutput{}, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	// one extra row tells us whether there is a next page without a second query
+	page := service.CommentPage{Limit: limit + 1}
+	if input.Cursor != "" {
+		cursor, err := decodeTimeCursor(input.Cursor, commentCursorSort)
+		if err != nil {
+			return ListCommentsOutput{}, err
+		}
+		page.After = &cursor
+	}
+
+	comments, err := u.comments.List(ctx, todoID, page)
+	if err != nil {
+		return ListCommentsOutput{}, err
+	}
+
+	total, err := u.comments.Count(ctx, todoID)
+	if err != nil {
+		return ListCommentsOutput{}, err
+	}
+
+	var nextCursor string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		nextCursor = encodeTimeCursor(commentCursorSort, comments[limit-1].CreatedAt, comments[limit-1].ID)
+	}
+
+	return ListCommentsOutput{
+		Comments:   comments,
+		Total:      total,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (u *Comment) Create(ctx context.Context, todoID, body string) (CommentOutput, error) {
+	if err := u.policy.Authorize(ctx, policy.ActionComment); err != nil {
+		return CommentOutput{}, err
+	}
+
+	if _, err := getTodo(ctx, u.todos, todoID); err != nil {
+		return CommentOutput{}, err
+	}
+
+	comment, err := u.comments.Create(ctx, todoID, body, actingUser(ctx))
+	if err != nil {
+		return CommentOutput{}, err
+	}
+
+	return CommentOutput{Comment: comment}, nil
+}
+
+// Update edits the body of the comment, which only its author or an admin may do.
+func (u *Comment) Update(ctx context.Context, todoID, id, body string) (CommentOutput, error) {
+	if err := u.authorizeAuthor(ctx, todoID, id); err != nil {
+		return CommentOutput{}, err
+	}
+
+	comment, err := u.comments.Update(ctx, todoID, id, body)
+	if err != nil {
+		return CommentOutput{}, err
+	}
+
+	return CommentOutput{Comment: comment}, nil
+}
+
+// Delete removes the comment, which only its author or an admin may do.
+func (u *Comment) Delete(ctx context.Context, todoID, id string) error {
+	if err := u.authorizeAuthor(ctx, todoID, id); err != nil {
+		return err
+	}
+
+	return u.comments.Delete(ctx, todoID, id)
+}
+
+// authorizeAuthor checks that the acting user may change the comment: its author or an
+// admin. Requests without a user may change every comment, as they see every todo, while
+// comments without an author are left to admins.
+func (u *Comment) authorizeAuthor(ctx context.Context, todoID, id string) error {
+	if err := u.policy.Authorize(ctx, policy.ActionComment); err != nil {
+		return err
+	}
+
+	if _, err := getTodo(ctx, u.todos, todoID); err != nil {
+		return err
+	}
+
+	comment, err := u.comments.GetByID(ctx, todoID, id)
+	if err != nil {
+		return err
+	}
+
+	user := actingUser(ctx)
+	if user == "" || comment.Author == user || isAdmin(u.policy, user) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
@@ -0,0 +1,201 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+const commentUUID = "423e4567-e89b-12d3-a456-426614174000"
+
+func liveTodoService() *test.MockTodoService {
+	return &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+}
+
+func buildComments(n int) []domain.Comment {
+	comments := make([]domain.Comment, n)
+	for i := range comments {
+		comments[i] = domain.Comment{ID: commentUUID, TodoID: validUUID, Body: "comment", CreatedAt: fixedTime, UpdatedAt: fixedTime}
+	}
+	return comments
+}
+
+func TestComment_List_ReturnsNextCursorThatResumes(t *testing.T) {
+	var pages []service.CommentPage
+	comments := &test.MockCommentService{
+		ListFn: func(ctx context.Context, todoID string, page service.CommentPage) ([]domain.Comment, error) {
+			pages = append(pages, page)
+			return buildComments(page.Limit), nil
+		},
+		CountFn: func(ctx context.Context, todoID string) (int, error) {
+			return 10, nil
+		},
+	}
+	uc := usecase.NewComment(comments, liveTodoService())
+
+	first, err := uc.List(context.Background(), validUUID, usecase.ListCommentsInput{Limit: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(first.Comments) != 2 || first.Total != 10 || first.NextCursor == "" {
+		t.Fatalf("expected a first page of 2 with a next cursor, got %+v", first)
+	}
+
+	if _, err := uc.List(context.Background(), validUUID, usecase.ListCommentsInput{Limit: 2, Cursor: first.NextCursor}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if after := pages[1].After; after == nil || after.ID != commentUUID || len(after.Values) != 1 {
+		t.Errorf("expected the second page to resume after the last comment, got %+v", after)
+	}
+}
+
+func TestComment_List_RejectsTodoCursor(t *testing.T) {
+	todos := liveTodoService()
+	todos.GetFn = func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+		return []domain.Todo{buildValidTodo(), buildValidTodo()}, nil
+	}
+	todos.CountFn = func(ctx context.Context, filters service.Filters) (int, error) {
+		return 2, nil
+	}
+	listed, err := usecase.New(todos).Get(context.Background(), usecase.ListInput{Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	uc := usecase.NewComment(&test.MockCommentService{}, todos)
+
+	_, err = uc.List(context.Background(), validUUID, usecase.ListCommentsInput{Cursor: listed.NextCursor})
+
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestComment_Create_ReturnsErrTodoNotFoundForTrashedTodo(t *testing.T) {
+	todos := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	comments := &test.MockCommentService{
+		CreateFn: func(ctx context.Context, todoID, body, author string) (domain.Comment, error) {
+			t.Error("expected no comment to be created")
+			return domain.Comment{}, nil
+		},
+	}
+	uc := usecase.NewComment(comments, todos)
+
+	_, err := uc.Create(context.Background(), validUUID, "Hello")
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+func TestComment_Create_RecordsTheActingUserAsAuthor(t *testing.T) {
+	var captured string
+	comments := &test.MockCommentService{
+		CreateFn: func(ctx context.Context, todoID, body, author string) (domain.Comment, error) {
+			captured = author
+			return domain.Comment{ID: commentUUID, TodoID: todoID, Body: body, Author: author}, nil
+		},
+	}
+	uc := usecase.NewComment(comments, liveTodoService())
+
+	result, err := uc.Create(asUser("alice"), validUUID, "Hello")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if captured != "alice" || result.Comment.Author != "alice" {
+		t.Errorf("expected alice to be the author, got %q", captured)
+	}
+}
+
+func TestComment_Update_Successfully(t *testing.T) {
+	comments := &test.MockCommentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Comment, error) {
+			return domain.Comment{ID: id, TodoID: todoID, Body: "comment"}, nil
+		},
+		UpdateFn: func(ctx context.Context, todoID, id, body string) (domain.Comment, error) {
+			return domain.Comment{ID: id, TodoID: todoID, Body: body}, nil
+		},
+	}
+	uc := usecase.NewComment(comments, liveTodoService())
+
+	result, err := uc.Update(context.Background(), validUUID, commentUUID, "Edited")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Comment.Body != "Edited" {
+		t.Errorf("expected body Edited, got %s", result.Comment.Body)
+	}
+}
+
+func TestComment_Delete_ReturnsErrCommentNotFound(t *testing.T) {
+	comments := &test.MockCommentService{
+		GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Comment, error) {
+			return domain.Comment{}, domain.ErrCommentNotFound
+		},
+	}
+	uc := usecase.NewComment(comments, liveTodoService())
+
+	err := uc.Delete(context.Background(), validUUID, commentUUID)
+
+	if !errors.Is(err, domain.ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestComment_OnlyTheAuthorOrAnAdminChangesAComment(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		author string
+		err    error
+	}{
+		{name: "author", ctx: asUser("bob"), author: "bob"},
+		{name: "admin", ctx: asUser("alice"), author: "carol"},
+		{name: "no user", ctx: context.Background(), author: "carol"},
+		{name: "other member", ctx: asUser("bob"), author: "carol", err: domain.ErrForbidden},
+		{name: "comment without author", ctx: asUser("bob"), err: domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changed int
+			comments := &test.MockCommentService{
+				GetByIDFn: func(ctx context.Context, todoID, id string) (domain.Comment, error) {
+					return domain.Comment{ID: id, TodoID: todoID, Body: "comment", Author: tt.author}, nil
+				},
+				UpdateFn: func(ctx context.Context, todoID, id, body string) (domain.Comment, error) {
+					changed++
+					return domain.Comment{ID: id, TodoID: todoID, Body: body, Author: tt.author}, nil
+				},
+				DeleteFn: func(ctx context.Context, todoID, id string) error {
+					changed++
+					return nil
+				},
+			}
+			uc := usecase.NewComment(comments, liveTodoService(), usecase.WithCommentPolicy(newTestPolicy(t)))
+
+			_, updateErr := uc.Update(tt.ctx, validUUID, commentUUID, "Edited")
+			deleteErr := uc.Delete(tt.ctx, validUUID, commentUUID)
+
+			if !errors.Is(updateErr, tt.err) || !errors.Is(deleteErr, tt.err) {
+				t.Errorf("expected %v, got %v and %v", tt.err, updateErr, deleteErr)
+			}
+			if allowed := tt.err == nil; allowed != (changed == 2) {
+				t.Errorf("expected the comment to be changed only when allowed, got %d changes", changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// cursorPayload is the decoded form of the opaque next_cursor handed to clients.
+// The sort it was issued for is kept so a cursor cannot be replayed under another ordering.
+type cursorPayload struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     string   `json:"i"`
+}
+
+func encodeCursor(todo domain.Todo, sort []domain.SortOrder) string {
+	c := service.NewCursor(todo, sort)
+	b, _ := json.Marshal(cursorPayload{Sort: domain.FormatSort(sort), Values: c.Values, ID: c.ID}) // safe mute, payload is always serializable
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string, sort []domain.SortOrder) (service.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return service.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return service.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	if payload.Sort != domain.FormatSort(sort) || domain.ValidateUUID(payload.ID) != nil {
+		return service.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	return service.Cursor{Values: payload.Values, ID: payload.ID}, nil
+}
+
+// encodeTimeCursor builds the cursor of a listing ordered by creation time, such as the
+// comments or the history of a todo. sort names the listing the cursor is issued for.
+func encodeTimeCursor(sort string, createdAt time.Time, id string) string {
+	payload := cursorPayload{
+		Sort:   sort,
+		Values: []string{createdAt.Format(time.RFC3339Nano)},
+		ID:     id,
+	}
+	b, _ := json.Marshal(payload) // safe mute, payload is always serializable
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeTimeCursor(s, sort string) (service.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return service.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return service.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	if payload.Sort != sort || len(payload.Values) != 1 || domain.ValidateUUID(payload.ID) != nil {
+		return service.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	return service.Cursor{Values: payload.Values, ID: payload.ID}, nil
+}
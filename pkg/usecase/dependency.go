@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+type (
+	DependenciesOutput struct {
+		Blockers []domain.Todo
+	}
+
+	AddDependencyOutput struct {
+		Todo domain.Todo
+	}
+)
+
+// GetDependencies lists the live todos the todo is blocked by, leaving out those the
+// acting user cannot see. They still count in the dependencies of the todo.
+func (u *Todo) GetDependencies(ctx context.Context, id string) (DependenciesOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.GetDependencies")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return DependenciesOutput{}, err
+	}
+
+	if _, err := getTodo(ctx, u.service, id); err != nil {
+		return DependenciesOutput{}, err
+	}
+
+	blockers, err := u.service.GetBlockers(ctx, id)
+	if err != nil {
+		return DependenciesOutput{}, err
+	}
+
+	user := actingUser(ctx)
+	visible := blockers[:0]
+	for _, blocker := range blockers {
+		if blocker.VisibleTo(user) {
+			visible = append(visible, blocker)
+		}
+	}
+
+	return DependenciesOutput{Blockers: visible}, nil
+}
+
+// AddDependency makes the todo blocked by blockerID, refusing dependencies that would
+// close a cycle. It returns the todo with its updated dependency counts.
+func (u *Todo) AddDependency(ctx context.Context, id, blockerID string) (AddDependencyOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.AddDependency")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionUpdate); err != nil {
+		return AddDependencyOutput{}, err
+	}
+
+	var todo domain.Todo
+	err := u.service.Transaction(ctx, func(svc service.Todo) error {
+		if _, err := getTodo(ctx, svc, id); err != nil {
+			return err
+		}
+		if err := checkBlocker(ctx, svc, id, blockerID); err != nil {
+			return err
+		}
+		if err := svc.AddDependency(ctx, id, blockerID); err != nil {
+			return err
+		}
+
+		var err error
+		todo, err = getTodo(ctx, svc, id)
+		return err
+	})
+	if err != nil {
+		return AddDependencyOutput{}, err
+	}
+
+	return AddDependencyOutput{Todo: todo}, nil
+}
+
+// RemoveDependency makes the todo no longer blocked by blockerID.
+func (u *Todo) RemoveDependency(ctx context.Context, id, blockerID string) error {
+	ctx, span := tracer.Start(ctx, "Todo.RemoveDependency")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionUpdate); err != nil {
+		return err
+	}
+
+	if _, err := getTodo(ctx, u.service, id); err != nil {
+		return err
+	}
+
+	return u.service.RemoveDependency(ctx, id, blockerID)
+}
+
+// checkBlocker verifies that blockerID names a live todo and that id blocked by it would not
+// create a cycle, i.e. that blockerID is not id and does not already depend on id.
+func checkBlocker(ctx context.Context, svc service.Todo, id, blockerID string) error {
+	if _, err := getTodo(ctx, svc, blockerID); err != nil {
+		if errors.Is(err, domain.ErrTodoNotFound) {
+			return domain.ErrInvalidDependency
+		}
+		return err
+	}
+
+	blockers, err := svc.GetBlockerIDs(ctx, blockerID)
+	if err != nil {
+		return err
+	}
+	for _, blocker := range blockers {
+		if blocker == id {
+			return domain.ErrDependencyCycle
+		}
+	}
+	return nil
+}
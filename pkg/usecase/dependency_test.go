@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+const blockerUUID = "223e4567-e89b-12d3-a456-426614174000"
+
+func TestTodo_AddDependency_Successfully(t *testing.T) {
+	var added bool
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.ID = id
+			if added && id == validUUID {
+				todo.Dependencies = &domain.Dependencies{Total: 1, Open: 1}
+			}
+			return todo, nil
+		},
+		GetBlockerIDsFn: func(ctx context.Context, id string) ([]string, error) {
+			return []string{id}, nil
+		},
+		AddDependencyFn: func(ctx context.Context, id, blockerID string) error {
+			if id != validUUID || blockerID != blockerUUID {
+				t.Errorf("expected %s to be blocked by %s, got %s and %s", validUUID, blockerUUID, id, blockerID)
+			}
+			added = true
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.AddDependency(context.Background(), validUUID, blockerUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Todo.Dependencies == nil || !result.Todo.Dependencies.Blocked() {
+		t.Errorf("expected the todo to be blocked, got %+v", result.Todo.Dependencies)
+	}
+}
+
+func TestTodo_AddDependency_RejectsUnknownBlocker(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			if id == blockerUUID {
+				return domain.Todo{}, domain.ErrTodoNotFound
+			}
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.AddDependency(context.Background(), validUUID, blockerUUID)
+
+	if !errors.Is(err, domain.ErrInvalidDependency) {
+		t.Errorf("expected error %v, got %v", domain.ErrInvalidDependency, err)
+	}
+}
+
+func TestTodo_AddDependency_RejectsCycle(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetBlockerIDsFn: func(ctx context.Context, id string) ([]string, error) {
+			return []string{blockerUUID, nonExistentID, validUUID}, nil
+		},
+		AddDependencyFn: func(ctx context.Context, id, blockerID string) error {
+			t.Error("expected the dependency not to be added")
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.AddDependency(context.Background(), validUUID, blockerUUID)
+
+	if !errors.Is(err, domain.ErrDependencyCycle) {
+		t.Errorf("expected error %v, got %v", domain.ErrDependencyCycle, err)
+	}
+}
+
+func TestTodo_RemoveDependency_ReturnsErrTodoNotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	uc := usecase.New(mock)
+
+	err := uc.RemoveDependency(context.Background(), nonExistentID, blockerUUID)
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected error %v, got %v", domain.ErrTodoNotFound, err)
+	}
+}
+
+func TestTodo_GetDependencies_ReturnsBlockers(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetBlockersFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			blocker := buildValidTodo()
+			blocker.ID = blockerUUID
+			return []domain.Todo{blocker}, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.GetDependencies(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Blockers) != 1 || result.Blockers[0].ID != blockerUUID {
+		t.Errorf("expected blocker %s, got %+v", blockerUUID, result.Blockers)
+	}
+}
+
+func TestTodo_Update_RejectsStartingBlockedTodo(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Dependencies = &domain.Dependencies{Total: 2, Open: 1}
+			return todo, nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			t.Error("expected the todo not to be updated")
+			return domain.Todo{}, nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusInProgress
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status})
+
+	if !errors.Is(err, domain.ErrTodoBlocked) {
+		t.Errorf("expected error %v, got %v", domain.ErrTodoBlocked, err)
+	}
+}
+
+func TestTodo_Update_AllowsCompletingBlockedTodo(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Dependencies = &domain.Dependencies{Total: 1, Open: 1}
+			return todo, nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusCompleted
+
+	if _, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
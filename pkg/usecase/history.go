@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+// historyCursorSort tags history cursors so that they cannot be replayed on other listings.
+const historyCursorSort = "history"
+
+type (
+	HistoryInput struct {
+		Limit  int
+		Cursor string
+	}
+
+	HistoryOutput struct {
+		Entries    []domain.HistoryEntry
+		Total      int
+		NextCursor string
+	}
+)
+
+// History returns a page of the changes made to the todo, newest first. Like its
+// comments, the history of a todo in the trash is only reachable once it is restored.
+func (u *Todo) History(ctx context.Context, id string, input HistoryInput) (HistoryOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.History")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return HistoryOutput{}, err
+	}
+
+	if _, err := getTodo(ctx, u.service, id); err != nil {
+		return HistoryOutput{}, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	// one extra row tells us whether there is a next page without a second query
+	page := service.HistoryPage{Limit: limit + 1}
+	if input.Cursor != "" {
+		cursor, err := decodeTimeCursor(input.Cursor, historyCursorSort)
+		if err != nil {
+			return HistoryOutput{}, err
+		}
+		page.After = &cursor
+	}
+
+	entries, err := u.service.GetHistory(ctx, id, page)
+	if err != nil {
+		return HistoryOutput{}, err
+	}
+
+	total, err := u.service.CountHistory(ctx, id)
+	if err != nil {
+		return HistoryOutput{}, err
+	}
+
+	var nextCursor string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		nextCursor = encodeTimeCursor(historyCursorSort, entries[limit-1].CreatedAt, entries[limit-1].ID)
+	}
+
+	return HistoryOutput{
+		Entries:    entries,
+		Total:      total,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// newHistoryEntry records the action taken on a todo by the caller of ctx, with the
+// fields that differ between its state before and after. Creations and restorations diff
+// against the zero todo as before, and deletions as after, so that they record the whole
+// state of the todo.
+func newHistoryEntry(ctx context.Context, action domain.HistoryAction, id string, before, after domain.Todo) domain.HistoryEntry {
+	return domain.HistoryEntry{
+		TodoID:  id,
+		Action:  action,
+		Changes: domain.DiffTodos(before, after),
+		Caller:  domain.CallerFromContext(ctx),
+	}
+}
+
+// addHistory stores the entries through svc, which should be bound to the transaction
+// of the mutation they record. Updates that changed nothing are left out.
+func addHistory(ctx context.Context, svc service.Todo, entries ...domain.HistoryEntry) error {
+	var kept []domain.HistoryEntry
+	for _, entry := range entries {
+		if entry.Action == domain.HistoryUpdate && len(entry.Changes) == 0 {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	return svc.AddHistory(ctx, kept)
+}
@@ -0,0 +1,298 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+func TestTodo_Update_RecordsHistoryInTransaction(t *testing.T) {
+	current := buildValidTodo()
+	caller := domain.Caller{App: "web", Scope: "prod"}
+	var inTx bool
+	var recorded []domain.HistoryEntry
+	mock := &test.MockTodoService{}
+	mock.TransactionFn = func(ctx context.Context, fn func(service.Todo) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(mock)
+	}
+	mock.GetByIDFn = func(ctx context.Context, id string) (domain.Todo, error) {
+		return current, nil
+	}
+	mock.UpdateFn = func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+		updated := current
+		updated.Title = *input.Title
+		return updated, nil
+	}
+	mock.AddHistoryFn = func(ctx context.Context, entries []domain.HistoryEntry) error {
+		if !inTx {
+			t.Error("expected the history to be written in the transaction of the update")
+		}
+		recorded = entries
+		return nil
+	}
+	uc := usecase.New(mock)
+	title := updatedTitle
+
+	_, err := uc.Update(domain.WithCaller(context.Background(), caller), validUUID, usecase.UpdateInput{Title: &title})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(recorded) != 1 || recorded[0].Action != domain.HistoryUpdate || recorded[0].Caller != caller {
+		t.Fatalf("expected one update entry by the caller, got %+v", recorded)
+	}
+	if c := recorded[0].Changes; len(c) != 1 || c[0].Field != "title" || c[0].After != updatedTitle {
+		t.Errorf("expected the title change, got %+v", c)
+	}
+}
+
+func TestTodo_Update_SkipsHistoryWhenNothingChanged(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		AddHistoryFn: func(ctx context.Context, entries []domain.HistoryEntry) error {
+			t.Errorf("expected no history, got %+v", entries)
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	if _, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestTodo_Create_FailsWhenHistoryFails(t *testing.T) {
+	historyErr := errors.New("history unavailable")
+	mock := &test.MockTodoService{
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		AddHistoryFn: func(ctx context.Context, entries []domain.HistoryEntry) error {
+			if len(entries) != 1 || entries[0].Action != domain.HistoryCreate || len(entries[0].Changes) == 0 {
+				t.Errorf("expected a create entry, got %+v", entries)
+			}
+			return historyErr
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Create(context.Background(), usecase.CreateInput{Title: validTitle})
+
+	if !errors.Is(err, historyErr) {
+		t.Errorf("expected error %v, got %v", historyErr, err)
+	}
+}
+
+func TestTodo_Delete_RecordsCascadedSubtasks(t *testing.T) {
+	parent := buildValidTodo()
+	var recorded []domain.HistoryEntry
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return parent, nil
+		},
+		DeleteDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return []domain.Todo{{ID: blockerUUID, Title: validTitle}}, nil
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
+			return nil
+		},
+		AddHistoryFn: func(ctx context.Context, entries []domain.HistoryEntry) error {
+			recorded = entries
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	err := uc.Delete(context.Background(), validUUID, usecase.DeleteInput{Children: domain.ChildrenCascade})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(recorded) != 2 || recorded[0].TodoID != blockerUUID || recorded[1].TodoID != validUUID {
+		t.Fatalf("expected the subtask and the todo to be recorded, got %+v", recorded)
+	}
+	if c := recorded[0].Changes; len(c) != 1 || c[0].Field != "title" || c[0].Before != validTitle || c[0].After != nil {
+		t.Errorf("expected the state of the subtask before it was trashed, got %+v", c)
+	}
+	if len(recorded[1].Changes) == 0 {
+		t.Errorf("expected the state of the todo before it was trashed, got %+v", recorded[1])
+	}
+}
+
+func TestTodo_Delete_RecordsOrphanedSubtasks(t *testing.T) {
+	var recorded []domain.HistoryEntry
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		OrphanChildrenFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return []domain.Todo{{ID: blockerUUID, Title: validTitle}}, nil
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
+			return nil
+		},
+		AddHistoryFn: func(ctx context.Context, entries []domain.HistoryEntry) error {
+			recorded = entries
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	err := uc.Delete(context.Background(), validUUID, usecase.DeleteInput{Children: domain.ChildrenOrphan})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(recorded) != 2 || recorded[0].TodoID != blockerUUID || recorded[0].Action != domain.HistoryUpdate {
+		t.Fatalf("expected an update of the subtask, got %+v", recorded)
+	}
+	if c := recorded[0].Changes; len(c) != 1 || c[0].Field != "parent_id" || c[0].Before != validUUID || c[0].After != nil {
+		t.Errorf("expected the subtask to be detached from the todo, got %+v", c)
+	}
+}
+
+func TestTodo_Batch_RecordsDeletedTodos(t *testing.T) {
+	var recorded []domain.HistoryEntry
+	mock := &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		DeleteBatchFn: func(ctx context.Context, items []service.BatchDelete) ([]service.BatchResult, error) {
+			return []service.BatchResult{{Todo: buildValidTodo()}, {Err: domain.ErrTodoNotFound}}, nil
+		},
+		AddHistoryFn: func(ctx context.Context, entries []domain.HistoryEntry) error {
+			recorded = entries
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Batch(context.Background(), usecase.BatchInput{Operations: []usecase.BatchOperation{
+		{Op: usecase.BatchDelete, ID: validUUID},
+		{Op: usecase.BatchDelete, ID: nonExistentID},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(recorded) != 1 || recorded[0].TodoID != validUUID || recorded[0].Action != domain.HistoryDelete {
+		t.Fatalf("expected the deleted todo to be recorded, got %+v", recorded)
+	}
+	if c := recorded[0].Changes; len(c) == 0 || c[0].Field != "title" || c[0].Before != validTitle || c[0].After != nil {
+		t.Errorf("expected the state of the todo before it was trashed, got %+v", c)
+	}
+}
+
+func TestTodo_Restore_RestoresAndRecordsCascadedSubtasks(t *testing.T) {
+	var calls []string
+	var recorded []domain.HistoryEntry
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			calls = append(calls, "descendants")
+			return []domain.Todo{{ID: blockerUUID, Title: validTitle}, {ID: commentUUID, Title: validTitle}}, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			calls = append(calls, "todo")
+			return buildValidTodo(), nil
+		},
+		AddHistoryFn: func(ctx context.Context, entries []domain.HistoryEntry) error {
+			recorded = entries
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Restore(context.Background(), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "descendants" {
+		t.Errorf("expected the subtasks to be restored while the todo is still trashed, got %v", calls)
+	}
+	if len(recorded) != 3 || recorded[0].TodoID != blockerUUID || recorded[1].TodoID != commentUUID || recorded[2].TodoID != validUUID {
+		t.Errorf("expected the subtasks and the todo to be recorded, got %+v", recorded)
+	}
+	for _, entry := range recorded {
+		if entry.Action != domain.HistoryRestore {
+			t.Errorf("expected restore entries, got %s", entry.Action)
+		}
+		if c := entry.Changes; len(c) == 0 || c[0].Field != "title" || c[0].Before != nil || c[0].After != validTitle {
+			t.Errorf("expected the restored state of %s, got %+v", entry.TodoID, c)
+		}
+	}
+}
+
+func TestTodo_History_ReturnsNextCursorThatResumes(t *testing.T) {
+	entries := []domain.HistoryEntry{
+		{ID: commentUUID, TodoID: validUUID, Action: domain.HistoryUpdate, CreatedAt: fixedTime},
+		{ID: blockerUUID, TodoID: validUUID, Action: domain.HistoryCreate, CreatedAt: fixedTime},
+	}
+	var pages []service.HistoryPage
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetHistoryFn: func(ctx context.Context, todoID string, page service.HistoryPage) ([]domain.HistoryEntry, error) {
+			pages = append(pages, page)
+			return entries, nil
+		},
+		CountHistoryFn: func(ctx context.Context, todoID string) (int, error) {
+			return 5, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	first, err := uc.History(context.Background(), validUUID, usecase.HistoryInput{Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(first.Entries) != 1 || first.Total != 5 || first.NextCursor == "" {
+		t.Fatalf("expected one entry and a next cursor, got %+v", first)
+	}
+
+	if _, err := uc.History(context.Background(), validUUID, usecase.HistoryInput{Limit: 1, Cursor: first.NextCursor}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pages[1].After == nil || pages[1].After.ID != commentUUID {
+		t.Errorf("expected the second page to start after the first entry, got %+v", pages[1].After)
+	}
+}
+
+func TestTodo_History_RejectsCommentCursor(t *testing.T) {
+	comments := &test.MockCommentService{
+		ListFn: func(ctx context.Context, todoID string, page service.CommentPage) ([]domain.Comment, error) {
+			return []domain.Comment{{ID: commentUUID, CreatedAt: fixedTime}, {ID: blockerUUID, CreatedAt: fixedTime}}, nil
+		},
+		CountFn: func(ctx context.Context, todoID string) (int, error) {
+			return 2, nil
+		},
+	}
+	todos := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	listed, err := usecase.NewComment(comments, todos).List(context.Background(), validUUID, usecase.ListCommentsInput{Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = usecase.New(todos).History(context.Background(), validUUID, usecase.HistoryInput{Cursor: listed.NextCursor})
+
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
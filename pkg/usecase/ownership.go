@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+type (
+	SharesOutput struct {
+		Users []string
+	}
+
+	ShareOutput struct {
+		Todo domain.Todo
+	}
+)
+
+// actingUser returns the user the request acts for, or "" when it carries none, in
+// which case every todo is visible.
+func actingUser(ctx context.Context) string {
+	return domain.CallerFromContext(ctx).User
+}
+
+// getTodo reads a live todo the acting user can see. Todos hidden from the user are
+// reported as domain.ErrTodoNotFound so that their existence does not leak.
+func getTodo(ctx context.Context, svc service.Todo, id string) (domain.Todo, error) {
+	todo, err := svc.GetByID(ctx, id)
+	if err != nil {
+		return domain.Todo{}, err
+	}
+	if !todo.VisibleTo(actingUser(ctx)) {
+		return domain.Todo{}, domain.ErrTodoNotFound
+	}
+	return todo, nil
+}
+
+// isAdmin reports whether the user is an admin of the policy. Without a policy nobody is,
+// and clients without a user are not either.
+func isAdmin(p *policy.Policy, user string) bool {
+	return p != nil && user != "" && p.RoleOf(user) == policy.RoleAdmin
+}
+
+// resolveAssignee replaces domain.AssigneeMe with the acting user, which is required then.
+func resolveAssignee(ctx context.Context, assignee *string) (*string, error) {
+	if assignee == nil || *assignee != domain.AssigneeMe {
+		return assignee, nil
+	}
+
+	user := actingUser(ctx)
+	if user == "" {
+		return nil, domain.ErrInvalidAssignee
+	}
+	return &user, nil
+}
+
+// Shares lists the users the todo was shared with, besides its owner and assignee.
+func (u *Todo) Shares(ctx context.Context, id string) (SharesOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.Shares")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return SharesOutput{}, err
+	}
+
+	todo, err := getTodo(ctx, u.service, id)
+	if err != nil {
+		return SharesOutput{}, err
+	}
+
+	users := todo.SharedWith
+	if users == nil {
+		users = []string{}
+	}
+	return SharesOutput{Users: users}, nil
+}
+
+// Share lets the user see the todo, and returns the todo with its updated shares.
+func (u *Todo) Share(ctx context.Context, id, userID string) (ShareOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.Share")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionShare); err != nil {
+		return ShareOutput{}, err
+	}
+
+	var todo domain.Todo
+	err := u.service.Transaction(ctx, func(svc service.Todo) error {
+		if _, err := getTodo(ctx, svc, id); err != nil {
+			return err
+		}
+		if err := svc.ShareTodo(ctx, id, userID); err != nil {
+			return err
+		}
+
+		var err error
+		todo, err = svc.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return ShareOutput{}, err
+	}
+
+	return ShareOutput{Todo: todo}, nil
+}
+
+// Unshare hides the todo from the user again, unless they own it or are assigned to it.
+func (u *Todo) Unshare(ctx context.Context, id, userID string) error {
+	ctx, span := tracer.Start(ctx, "Todo.Unshare")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionShare); err != nil {
+		return err
+	}
+
+	if _, err := getTodo(ctx, u.service, id); err != nil {
+		return err
+	}
+
+	return u.service.UnshareTodo(ctx, id, userID)
+}
@@ -0,0 +1,282 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+func asUser(user string) context.Context {
+	return domain.WithCaller(context.Background(), domain.Caller{User: user})
+}
+
+func buildOwnedTodo(owner string) domain.Todo {
+	todo := buildValidTodo()
+	todo.OwnerID = &owner
+	return todo
+}
+
+func TestTodo_Get_FiltersByActingUser(t *testing.T) {
+	var captured service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			captured = filters
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Get(asUser("alice"), usecase.ListInput{Assignee: domain.AssigneeMe})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if captured.VisibleTo == nil || *captured.VisibleTo != "alice" {
+		t.Errorf("expected the listing to be restricted to alice, got %v", captured.VisibleTo)
+	}
+	if captured.AssigneeID == nil || *captured.AssigneeID != "alice" {
+		t.Errorf("expected me to resolve to alice, got %v", captured.AssigneeID)
+	}
+}
+
+func TestTodo_ListTags_CountsTheTodosVisibleToTheActingUser(t *testing.T) {
+	var captured *string
+	mock := &test.MockTodoService{
+		ListTagsFn: func(ctx context.Context, visibleTo *string) ([]domain.TagUsage, error) {
+			captured = visibleTo
+			return nil, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	if _, err := uc.ListTags(asUser("alice")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if captured == nil || *captured != "alice" {
+		t.Errorf("expected the tags to be restricted to alice, got %v", captured)
+	}
+
+	if _, err := uc.ListTags(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if captured != nil {
+		t.Errorf("expected every todo to count without a user, got %v", *captured)
+	}
+}
+
+func TestTodo_Get_AssigneeMeRequiresUser(t *testing.T) {
+	uc := usecase.New(&test.MockTodoService{})
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{Assignee: domain.AssigneeMe})
+
+	if !errors.Is(err, domain.ErrInvalidAssignee) {
+		t.Errorf("expected ErrInvalidAssignee, got %v", err)
+	}
+}
+
+func TestTodo_GetByID_HidesTodosOfOtherUsers(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildOwnedTodo("alice"), nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.GetByID(asUser("bob"), validUUID, usecase.GetByIDInput{})
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
+
+func TestTodo_GetByID_ShowsSharedTodos(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildOwnedTodo("alice")
+			todo.SharedWith = []string{"bob"}
+			return todo, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.GetByID(asUser("bob"), validUUID, usecase.GetByIDInput{})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestTodo_Create_OwnedByActingUser(t *testing.T) {
+	var captured service.CreateInput
+	mock := &test.MockTodoService{
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			captured = input
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+	me := domain.AssigneeMe
+
+	_, err := uc.Create(asUser("alice"), usecase.CreateInput{Title: validTitle, AssigneeID: &me})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if captured.OwnerID == nil || *captured.OwnerID != "alice" {
+		t.Errorf("expected owner alice, got %v", captured.OwnerID)
+	}
+	if captured.AssigneeID == nil || *captured.AssigneeID != "alice" {
+		t.Errorf("expected assignee alice, got %v", captured.AssigneeID)
+	}
+}
+
+func TestTodo_Update_Reassigns(t *testing.T) {
+	var captured service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildOwnedTodo("alice"), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			captured = input
+			todo := buildOwnedTodo("alice")
+			todo.AssigneeID = input.AssigneeID
+			return todo, nil
+		},
+	}
+	uc := usecase.New(mock)
+	bob := "bob"
+
+	result, err := uc.Update(asUser("alice"), validUUID, usecase.UpdateInput{AssigneeID: &bob})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if captured.AssigneeID == nil || *captured.AssigneeID != bob {
+		t.Errorf("expected the todo to be reassigned to bob, got %v", captured.AssigneeID)
+	}
+	if result.Todo.AssigneeID == nil || *result.Todo.AssigneeID != bob {
+		t.Errorf("expected assignee bob, got %v", result.Todo.AssigneeID)
+	}
+}
+
+func TestTodo_Restore_HidesTodosOfOtherUsers(t *testing.T) {
+	var rolledBack bool
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildOwnedTodo("alice"), nil
+		},
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildOwnedTodo("alice"), nil
+		},
+	}
+	mock.TransactionFn = func(ctx context.Context, fn func(service.Todo) error) error {
+		err := fn(mock)
+		rolledBack = err != nil
+		return err
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Restore(asUser("bob"), validUUID)
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+	if !rolledBack {
+		t.Error("expected the restore to be rolled back")
+	}
+}
+
+func TestTodo_GetDependencies_LeavesOutHiddenBlockers(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildOwnedTodo("alice"), nil
+		},
+		GetBlockersFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			hidden := buildOwnedTodo("carol")
+			hidden.ID = blockerUUID
+			return []domain.Todo{buildOwnedTodo("alice"), hidden}, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.GetDependencies(asUser("alice"), validUUID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Blockers) != 1 || result.Blockers[0].ID != validUUID {
+		t.Errorf("expected only the visible blocker, got %+v", result.Blockers)
+	}
+}
+
+func TestTodo_Batch_HidesTodosOfOtherUsers(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDsFn: func(ctx context.Context, ids []string) ([]domain.Todo, error) {
+			return []domain.Todo{buildOwnedTodo("alice")}, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Batch(asUser("bob"), usecase.BatchInput{
+		Operations: []usecase.BatchOperation{{Op: usecase.BatchDelete, ID: validUUID}},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(result.Results[0].Err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", result.Results[0].Err)
+	}
+}
+
+func TestTodo_Share(t *testing.T) {
+	var shared []string
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			todo := buildOwnedTodo("alice")
+			todo.SharedWith = shared
+			return todo, nil
+		},
+		ShareTodoFn: func(ctx context.Context, id, userID string) error {
+			shared = append(shared, userID)
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Share(asUser("alice"), validUUID, "bob")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(result.Todo.SharedWith, []string{"bob"}) {
+		t.Errorf("expected the todo to be shared with bob, got %v", result.Todo.SharedWith)
+	}
+}
+
+func TestTodo_Unshare_HiddenTodo(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildOwnedTodo("alice"), nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	err := uc.Unshare(asUser("bob"), validUUID, "bob")
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected ErrTodoNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+func newTestPolicy(t *testing.T) *policy.Policy {
+	t.Helper()
+
+	p, err := policy.New(policy.Config{
+		DefaultRole: policy.RoleViewer,
+		Roles:       policy.Default().Roles,
+		Users:       map[string]string{"alice": policy.RoleAdmin, "bob": policy.RoleMember},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestTodo_Policy_DeniesViewersChanges(t *testing.T) {
+	// the service is never reached: any call would panic on the nil functions
+	uc := usecase.New(&test.MockTodoService{}, usecase.WithPolicy(newTestPolicy(t)))
+	ctx := asUser("victor")
+
+	if _, err := uc.Create(ctx, usecase.CreateInput{Title: "Write report"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden on create, got %v", err)
+	}
+	if _, err := uc.Update(ctx, validUUID, usecase.UpdateInput{}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden on update, got %v", err)
+	}
+	if err := uc.Delete(ctx, validUUID, usecase.DeleteInput{}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden on delete, got %v", err)
+	}
+	if _, err := uc.Share(ctx, validUUID, "carol"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden on share, got %v", err)
+	}
+}
+
+func TestTodo_Policy_LetsViewersRead(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock, usecase.WithPolicy(newTestPolicy(t)))
+
+	if _, err := uc.GetByID(asUser("victor"), validUUID, usecase.GetByIDInput{}); err != nil {
+		t.Errorf("expected viewers to read todos, got %v", err)
+	}
+}
+
+func TestTodo_Policy_OnlyAdminsPurge(t *testing.T) {
+	mock := &test.MockTodoService{
+		PurgeFn: func(ctx context.Context, before time.Time) (service.PurgeResult, error) {
+			return service.PurgeResult{Purged: 3}, nil
+		},
+	}
+	uc := usecase.New(mock, usecase.WithPolicy(newTestPolicy(t)))
+
+	if _, err := uc.Purge(asUser("bob")); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for a member, got %v", err)
+	}
+	if output, err := uc.Purge(asUser("alice")); err != nil || output.Purged != 3 {
+		t.Errorf("expected an admin to purge 3 todos, got %+v %v", output, err)
+	}
+}
+
+func TestTodo_Policy_DeniesBatchWithForbiddenOperation(t *testing.T) {
+	uc := usecase.New(&test.MockTodoService{}, usecase.WithPolicy(newTestPolicy(t)))
+
+	_, err := uc.Batch(asUser("victor"), usecase.BatchInput{
+		Operations: []usecase.BatchOperation{{Op: usecase.BatchDelete, ID: validUUID}},
+	})
+
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestComment_Policy_DeniesViewersComments(t *testing.T) {
+	uc := usecase.NewComment(&test.MockCommentService{}, liveTodoService(), usecase.WithCommentPolicy(newTestPolicy(t)))
+
+	if _, err := uc.Create(asUser("victor"), validUUID, "Looks good"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestAttachment_Policy_DeniesViewersUploads(t *testing.T) {
+	uc := usecase.NewAttachment(&test.MockAttachmentService{}, &test.MockBlobStore{}, liveTodoService(), usecase.WithAttachmentPolicy(newTestPolicy(t)))
+
+	if _, err := uc.Upload(asUser("victor"), validUUID, usecase.UploadInput{Filename: "notes.txt"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestAPIKey_Policy_OnlyAdminsManageKeys(t *testing.T) {
+	mock := &test.MockAPIKeyService{
+		RevokeFn: func(ctx context.Context, id string) error {
+			return nil
+		},
+	}
+	uc := usecase.NewAPIKey(mock, usecase.WithAPIKeyPolicy(newTestPolicy(t)))
+
+	if err := uc.Revoke(asUser("bob"), apiKeyUUID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("expected ErrForbidden for a member, got %v", err)
+	}
+	if err := uc.Revoke(asUser("alice"), apiKeyUUID); err != nil {
+		t.Errorf("expected an admin to revoke keys, got %v", err)
+	}
+	if err := uc.Revoke(domain.WithAPIKey(context.Background(), domain.APIKey{App: "reminders-job"}), apiKeyUUID); err != nil {
+		t.Errorf("expected clients authenticated by a key not to be subject to the policy, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
+	"todo-api/pkg/service"
+)
+
+// DefaultOccurrences is how many occurrences Occurrences previews when no limit is given.
+const DefaultOccurrences = 10
+
+type (
+	// OccurrencesOutput lists the upcoming due dates of a recurring todo. It is empty
+	// for a todo that does not recur or whose rule is exhausted.
+	OccurrencesOutput struct {
+		Recurrence  *domain.Recurrence
+		Occurrences []time.Time
+	}
+)
+
+// Occurrences previews up to limit due dates the todo would get by being completed over
+// and over, starting from its current due date, or from now when it has none.
+func (u *Todo) Occurrences(ctx context.Context, id string, limit int) (OccurrencesOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.Occurrences")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return OccurrencesOutput{}, err
+	}
+
+	todo, err := getTodo(ctx, u.service, id)
+	if err != nil {
+		return OccurrencesOutput{}, err
+	}
+
+	if todo.Recurrence == nil {
+		return OccurrencesOutput{Occurrences: []time.Time{}}, nil
+	}
+
+	if limit <= 0 {
+		limit = DefaultOccurrences
+	}
+
+	start := time.Now().UTC()
+	if todo.DueAt != nil {
+		start = *todo.DueAt
+	}
+
+	return OccurrencesOutput{
+		Recurrence:  todo.Recurrence,
+		Occurrences: todo.Recurrence.Occurrences(start, limit),
+	}, nil
+}
+
+// spawnNextOccurrence creates the occurrence following the todo, which has just been
+// completed under the rule. The new todo is a pending copy of the completed one, with
+// the same parent, tags, owner and assignee but no shares, due on the next date the rule
+// yields after the due date of the completed todo, or after its completion when it had
+// none. Nothing is created once the rule is exhausted.
+func spawnNextOccurrence(ctx context.Context, svc service.Todo, todo domain.Todo, rule domain.Recurrence) (*domain.Todo, error) {
+	start := time.Now().UTC()
+	switch {
+	case todo.DueAt != nil:
+		start = *todo.DueAt
+	case todo.CompletedAt != nil:
+		start = *todo.CompletedAt
+	}
+
+	dueAt, ok := rule.Next(start)
+	if !ok {
+		return nil, nil
+	}
+
+	var description *string
+	if todo.Description != "" {
+		description = &todo.Description
+	}
+	next := rule.Advance()
+
+	spawned, err := svc.Create(ctx, service.CreateInput{
+		Title:       todo.Title,
+		Description: description,
+		Status:      domain.StatusPending,
+		Priority:    todo.Priority,
+		DueAt:       &dueAt,
+		ParentID:    todo.ParentID,
+		Recurrence:  &next,
+		OwnerID:     todo.OwnerID,
+		AssigneeID:  todo.AssigneeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := updateTags(ctx, svc, &spawned, todo.Tags, nil); err != nil {
+		return nil, err
+	}
+	if err := addHistory(ctx, svc, newHistoryEntry(ctx, domain.HistoryCreate, spawned.ID, domain.Todo{}, spawned)); err != nil {
+		return nil, err
+	}
+
+	return &spawned, nil
+}
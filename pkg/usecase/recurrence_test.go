@@ -0,0 +1,230 @@
+package usecase_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"todo-api/pkg/domain"
+	"todo-api/pkg/service"
+	"todo-api/pkg/usecase"
+	"todo-api/test"
+)
+
+const nextUUID = "323e4567-e89b-12d3-a456-426614174000"
+
+func buildRecurringTodo(t *testing.T, rule string) domain.Todo {
+	t.Helper()
+	recurrence, err := domain.ParseRecurrence(rule)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", rule, err)
+	}
+	todo := buildValidTodo()
+	todo.DueAt = &fixedTime
+	todo.Recurrence = &recurrence
+	todo.Tags = []string{"chores"}
+	return todo
+}
+
+func TestTodo_Update_CompletingRecurringTodoSpawnsNextOccurrence(t *testing.T) {
+	var updateInput service.UpdateInput
+	var created service.CreateInput
+	var tagged []service.TodoTags
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildRecurringTodo(t, "FREQ=WEEKLY;COUNT=3"), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			updateInput = input
+			todo := buildRecurringTodo(t, "FREQ=WEEKLY;COUNT=3")
+			todo.Status = domain.StatusCompleted
+			todo.Recurrence = nil
+			return todo, nil
+		},
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			created = input
+			return domain.Todo{ID: nextUUID, Title: input.Title, Status: input.Status, DueAt: input.DueAt, Recurrence: input.Recurrence}, nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			tagged = items
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusCompleted
+
+	result, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !updateInput.ClearRecurrence {
+		t.Error("expected the completed todo to hand its rule over")
+	}
+	if result.Next == nil || result.Next.ID != nextUUID {
+		t.Fatalf("expected the next occurrence, got %+v", result.Next)
+	}
+	if created.Status != domain.StatusPending || created.Title != validTitle {
+		t.Errorf("expected a pending copy, got %+v", created)
+	}
+	if expected := fixedTime.AddDate(0, 0, 7); created.DueAt == nil || !created.DueAt.Equal(expected) {
+		t.Errorf("expected due date %v, got %v", expected, created.DueAt)
+	}
+	if created.Recurrence == nil || created.Recurrence.Count != 2 {
+		t.Errorf("expected 2 occurrences left, got %+v", created.Recurrence)
+	}
+	if len(tagged) != 1 || tagged[0].TodoID != nextUUID || !reflect.DeepEqual(result.Next.Tags, []string{"chores"}) {
+		t.Errorf("expected the tags to be copied, got %+v", tagged)
+	}
+}
+
+func TestTodo_Update_CompletingLastOccurrenceSpawnsNothing(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildRecurringTodo(t, "FREQ=DAILY;COUNT=1"), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			t.Error("expected no occurrence to be created")
+			return domain.Todo{}, nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusCompleted
+
+	result, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Next != nil {
+		t.Errorf("expected no next occurrence, got %+v", result.Next)
+	}
+}
+
+func TestTodo_Update_ClearingRecurrenceWhileCompletingSpawnsNothing(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildRecurringTodo(t, "daily"), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			t.Error("expected no occurrence to be created")
+			return domain.Todo{}, nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusCompleted
+
+	if _, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status, ClearRecurrence: true}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestTodo_Occurrences_PreviewsFromDueDate(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildRecurringTodo(t, "FREQ=DAILY;INTERVAL=2"), nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Occurrences(context.Background(), validUUID, 2)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []time.Time{fixedTime.AddDate(0, 0, 2), fixedTime.AddDate(0, 0, 4)}
+	if !reflect.DeepEqual(result.Occurrences, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Occurrences)
+	}
+}
+
+func TestTodo_Occurrences_EmptyForNonRecurringTodo(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Occurrences(context.Background(), validUUID, 0)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Recurrence != nil || len(result.Occurrences) != 0 {
+		t.Errorf("expected no occurrences, got %+v", result)
+	}
+}
+
+func TestTodo_Update_ConcurrentCompletionsSpawnOneOccurrence(t *testing.T) {
+	// the mock plays the database: Lock holds the row until the transaction ends,
+	// and the write is slow enough for an unlocked read to see the todo still pending
+	var row sync.Mutex
+	var mu sync.Mutex
+	stored := buildRecurringTodo(t, "daily")
+	var spawned atomic.Int32
+	mock := &test.MockTodoService{
+		LockFn: func(ctx context.Context, ids []string) error {
+			row.Lock()
+			return nil
+		},
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return stored, nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			stored.Status = *input.Status
+			if input.ClearRecurrence {
+				stored.Recurrence = nil
+			}
+			stored.Version++
+			return stored, nil
+		},
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			spawned.Add(1)
+			return domain.Todo{ID: nextUUID, Title: input.Title, Status: input.Status, DueAt: input.DueAt}, nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			return nil
+		},
+	}
+	mock.TransactionFn = func(ctx context.Context, fn func(service.Todo) error) error {
+		defer row.Unlock()
+		return fn(mock)
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusCompleted
+
+	var wg sync.WaitGroup
+	nexts := make([]*domain.Todo, 2)
+	for i := range nexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// the second completion may be refused by the workflow, which is fine
+			result, _ := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status})
+			nexts[i] = result.Next
+		}()
+	}
+	wg.Wait()
+
+	if n := spawned.Load(); n != 1 {
+		t.Errorf("expected one occurrence to be spawned, got %d", n)
+	}
+	if (nexts[0] == nil) == (nexts[1] == nil) {
+		t.Errorf("expected a single completion to report the next occurrence, got %+v", nexts)
+	}
+}
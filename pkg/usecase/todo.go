@@ -2,24 +2,64 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
 
 	"todo-api/pkg/domain"
+	"todo-api/pkg/policy"
 	"todo-api/pkg/service"
 )
 
+// DefaultTrashRetention is how long deleted todos are kept when WithTrashRetention is not used.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// tracer starts a span per use case, within the span of the request. It goes through the
+// global provider, so nothing is recorded until one is set up.
+var tracer = otel.Tracer("todo-api/pkg/usecase")
+
 type (
 	ListInput struct {
-		Status   *domain.Status
-		Priority *domain.Priority
+		Statuses   []domain.Status
+		Priorities []domain.Priority
+		Query      string
+		DueBefore  *time.Time
+		DueAfter   *time.Time
+		Overdue    *bool
+		Sort       []domain.SortOrder
+		Limit      int
+		Cursor     string
+		// Deleted lists the trash instead of live todos.
+		Deleted bool
+		// ParentID lists the direct subtasks of a todo.
+		ParentID *string
+		// Tags keeps the todos carrying the tags; TagMatch defaults to domain.TagMatchAny.
+		Tags     []string
+		TagMatch domain.TagMatch
+		// Blocked keeps the todos that are (or are not) waiting on an open dependency.
+		Blocked *bool
+		// Assignee keeps the todos assigned to the user; domain.AssigneeMe stands for the acting user.
+		Assignee string
 	}
 
 	ListOutput struct {
-		Todos []domain.Todo
-		Total int
+		Todos      []domain.Todo
+		Total      int
+		NextCursor string
+	}
+
+	// GetByIDInput selects the expansions of GetByID. IncludeChildren loads the
+	// first page of direct subtasks, ordered like GetChildren.
+	GetByIDInput struct {
+		IncludeChildren bool
 	}
 
 	GetByIDOutput struct {
-		Todo domain.Todo
+		Todo               domain.Todo
+		Children           []domain.Todo
+		ChildrenNextCursor string
 	}
 
 	CreateInput struct {
@@ -27,79 +67,271 @@ type (
 		Description *string
 		Status      *domain.Status
 		Priority    *domain.Priority
+		DueAt       *time.Time
+		ParentID    *string
+		Tags        []string
+		Recurrence  *domain.Recurrence
+		// AssigneeID may be domain.AssigneeMe. The todo is owned by the acting user.
+		AssigneeID *string
 	}
 
 	CreateOutput struct {
 		Todo domain.Todo
 	}
 
+	// UpdateInput changes the todo fields that are set. AddTags and RemoveTags are
+	// applied to the current tags, additions first. Completing a recurring todo spawns
+	// its next occurrence, which takes the rule over from it. AssigneeID reassigns the
+	// todo and may be domain.AssigneeMe, while ClearAssignee unassigns it.
 	UpdateInput struct {
-		Title       *string
-		Description *string
-		Status      *domain.Status
-		Priority    *domain.Priority
+		Title           *string
+		Description     *string
+		Status          *domain.Status
+		Priority        *domain.Priority
+		DueAt           *time.Time
+		ClearDueAt      bool
+		ParentID        *string
+		ClearParent     bool
+		AddTags         []string
+		RemoveTags      []string
+		Recurrence      *domain.Recurrence
+		ClearRecurrence bool
+		AssigneeID      *string
+		ClearAssignee   bool
+		ExpectedVersion *int
 	}
 
+	// UpdateOutput holds the updated todo and, when the update completed a recurring
+	// todo, the next occurrence it spawned.
 	UpdateOutput struct {
 		Todo domain.Todo
+		Next *domain.Todo
+	}
+
+	// DeleteInput guards a delete with an expected version. Children decides what happens
+	// to the subtasks and defaults to domain.ChildrenRestrict.
+	DeleteInput struct {
+		ExpectedVersion *int
+		Children        domain.ChildPolicy
+	}
+
+	RestoreOutput struct {
+		Todo domain.Todo
+	}
+
+	PurgeOutput struct {
+		Purged int
+	}
+
+	ListTagsOutput struct {
+		Tags []domain.TagUsage
 	}
 
 	Todo struct {
-		service service.Todo
+		service        service.Todo
+		workflow       domain.Workflow
+		trashRetention time.Duration
+		policy         *policy.Policy
+		blobs          service.BlobStore
 	}
+
+	Option func(*Todo)
 )
 
-func New(svc service.Todo) *Todo {
-	return &Todo{
-		service: svc,
+// WithWorkflow replaces the domain.DefaultWorkflow used to validate status changes.
+func WithWorkflow(w domain.Workflow) Option {
+	return func(u *Todo) {
+		u.workflow = w
+	}
+}
+
+// WithTrashRetention sets how long deleted todos stay in the trash before Purge removes them.
+func WithTrashRetention(d time.Duration) Option {
+	return func(u *Todo) {
+		u.trashRetention = d
 	}
 }
 
+// WithPolicy checks every action against the policy before performing it.
+func WithPolicy(p *policy.Policy) Option {
+	return func(u *Todo) {
+		u.policy = p
+	}
+}
+
+// WithBlobStore has Purge remove the content of the attachments of the todos it removes.
+func WithBlobStore(b service.BlobStore) Option {
+	return func(u *Todo) {
+		u.blobs = b
+	}
+}
+
+func New(svc service.Todo, opts ...Option) *Todo {
+	u := &Todo{
+		service:        svc,
+		workflow:       domain.DefaultWorkflow,
+		trashRetention: DefaultTrashRetention,
+	}
+	for _, o := range opts {
+		o(u)
+	}
+	return u
+}
+
 func (u *Todo) Get(ctx context.Context, input ListInput) (ListOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.Get")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return ListOutput{}, err
+	}
+
 	filters := service.Filters{
-		Status:   input.Status,
-		Priority: input.Priority,
+		Statuses:     input.Statuses,
+		Priorities:   input.Priorities,
+		Query:        input.Query,
+		DueBefore:    input.DueBefore,
+		DueAfter:     input.DueAfter,
+		Overdue:      input.Overdue,
+		Deleted:      input.Deleted,
+		ParentID:     input.ParentID,
+		Tags:         input.Tags,
+		MatchAllTags: input.TagMatch == domain.TagMatchAll,
+		Blocked:      input.Blocked,
+	}
+
+	if input.Assignee != "" {
+		assignee, err := resolveAssignee(ctx, &input.Assignee)
+		if err != nil {
+			return ListOutput{}, err
+		}
+		filters.AssigneeID = assignee
+	}
+	if user := actingUser(ctx); user != "" {
+		filters.VisibleTo = &user
 	}
 
-	todos, err := u.service.Get(ctx, filters)
+	sort := input.Sort
+	switch {
+	case len(sort) == 0 && input.Deleted:
+		sort = domain.TrashSort
+	case len(sort) == 0 && input.Query != "":
+		sort = domain.RelevanceSort
+	case len(sort) == 0:
+		sort = domain.DefaultSort
+	case input.Query == "" && domain.HasField(sort, domain.SortRelevance):
+		return ListOutput{}, domain.ErrRelevanceSortQuery
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	// one extra row tells us whether there is a next page without a second query
+	page := service.Page{Limit: limit + 1, Sort: sort}
+	if input.Cursor != "" {
+		cursor, err := decodeCursor(input.Cursor, sort)
+		if err != nil {
+			return ListOutput{}, err
+		}
+		page.After = &cursor
+	}
+
+	todos, err := u.service.Get(ctx, filters, page)
+	if err != nil {
+		return ListOutput{}, err
+	}
+
+	total, err := u.service.Count(ctx, filters)
 	if err != nil {
 		return ListOutput{}, err
 	}
 
+	var nextCursor string
+	if len(todos) > limit {
+		todos = todos[:limit]
+		nextCursor = encodeCursor(todos[limit-1], sort)
+	}
+
 	return ListOutput{
-		Todos: todos,
-		Total: len(todos),
+		Todos:      todos,
+		Total:      total,
+		NextCursor: nextCursor,
 	}, nil
 }
 
-func (u *Todo) GetByID(ctx context.Context, id string) (GetByIDOutput, error) {
-	todo, err := u.service.GetByID(ctx, id)
+func (u *Todo) GetByID(ctx context.Context, id string, input GetByIDInput) (GetByIDOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.GetByID")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return GetByIDOutput{}, err
+	}
+
+	todo, err := getTodo(ctx, u.service, id)
 	if err != nil {
 		return GetByIDOutput{}, err
 	}
 
-	return GetByIDOutput{Todo: todo}, nil
+	output := GetByIDOutput{Todo: todo}
+	if input.IncludeChildren && todo.Subtasks != nil && todo.Subtasks.Total > 0 {
+		children, err := u.Get(ctx, ListInput{ParentID: &id, Limit: MaxLimit})
+		if err != nil {
+			return GetByIDOutput{}, err
+		}
+		output.Children = children.Todos
+		output.ChildrenNextCursor = children.NextCursor
+	}
+
+	return output, nil
+}
+
+// GetChildren lists the direct subtasks of a todo, which must exist.
+func (u *Todo) GetChildren(ctx context.Context, id string, input ListInput) (ListOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.GetChildren")
+	defer span.End()
+
+	if _, err := getTodo(ctx, u.service, id); err != nil {
+		return ListOutput{}, err
+	}
+
+	input.ParentID = &id
+	input.Deleted = false
+	return u.Get(ctx, input)
 }
 
 func (u *Todo) Create(ctx context.Context, input CreateInput) (CreateOutput, error) {
-	status := domain.StatusPending
-	if input.Status != nil {
-		status = *input.Status
+	ctx, span := tracer.Start(ctx, "Todo.Create")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionCreate); err != nil {
+		return CreateOutput{}, err
 	}
 
-	priority := domain.PriorityMedium
-	if input.Priority != nil {
-		priority = *input.Priority
+	var err error
+	if input.AssigneeID, err = resolveAssignee(ctx, input.AssigneeID); err != nil {
+		return CreateOutput{}, err
 	}
 
-	svcInput := service.CreateInput{
-		Title:       input.Title,
-		Description: input.Description,
-		Status:      status,
-		Priority:    priority,
+	if input.ParentID != nil {
+		if err := checkParent(ctx, u.service, "", *input.ParentID); err != nil {
+			return CreateOutput{}, err
+		}
 	}
 
-	todo, err := u.service.Create(ctx, svcInput)
+	var todo domain.Todo
+	err = u.service.Transaction(ctx, func(svc service.Todo) error {
+		var err error
+		todo, err = svc.Create(ctx, newServiceCreateInput(ctx, input))
+		if err != nil {
+			return err
+		}
+		if err := updateTags(ctx, svc, &todo, input.Tags, nil); err != nil {
+			return err
+		}
+		return addHistory(ctx, svc, newHistoryEntry(ctx, domain.HistoryCreate, todo.ID, domain.Todo{}, todo))
+	})
 	if err != nil {
 		return CreateOutput{}, err
 	}
@@ -107,22 +339,365 @@ func (u *Todo) Create(ctx context.Context, input CreateInput) (CreateOutput, err
 	return CreateOutput{Todo: todo}, nil
 }
 
+// Update applies the changes and records them in the history of the todo, all in one
+// transaction, which the current state of the todo is read in as well. The todo is locked
+// first, so that concurrent updates are validated one after the other against the state
+// left by the previous one.
 func (u *Todo) Update(ctx context.Context, id string, input UpdateInput) (UpdateOutput, error) {
-	svcInput := service.UpdateInput{
-		Title:       input.Title,
-		Description: input.Description,
-		Status:      input.Status,
-		Priority:    input.Priority,
+	ctx, span := tracer.Start(ctx, "Todo.Update")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionUpdate); err != nil {
+		return UpdateOutput{}, err
+	}
+
+	var err error
+	if input.AssigneeID, err = resolveAssignee(ctx, input.AssigneeID); err != nil {
+		return UpdateOutput{}, err
 	}
 
-	todo, err := u.service.Update(ctx, id, svcInput)
+	var output UpdateOutput
+	err = u.service.Transaction(ctx, func(svc service.Todo) error {
+		if err := svc.Lock(ctx, []string{id}); err != nil {
+			return err
+		}
+		current, err := getTodo(ctx, svc, id)
+		if err != nil {
+			return err
+		}
+
+		serviceInput := newServiceUpdateInput(input)
+
+		var rule *domain.Recurrence
+		if input.Status != nil {
+			if err := u.checkStatusChange(current, input); err != nil {
+				return err
+			}
+
+			if *input.Status == domain.StatusCompleted && current.Status != domain.StatusCompleted {
+				rule = recurrenceAfterUpdate(current, input)
+			}
+			if rule != nil {
+				// the rule moves on to the next occurrence, so reopening and completing
+				// this todo again does not spawn a second one
+				serviceInput.Recurrence = nil
+				serviceInput.ClearRecurrence = true
+			}
+		}
+
+		if input.ParentID != nil && !input.ClearParent {
+			if err := checkParent(ctx, svc, id, *input.ParentID); err != nil {
+				return err
+			}
+		}
+
+		output.Todo, err = svc.Update(ctx, id, serviceInput)
+		if err != nil {
+			return err
+		}
+		if err := updateTags(ctx, svc, &output.Todo, input.AddTags, input.RemoveTags); err != nil {
+			return err
+		}
+		if err := addHistory(ctx, svc, newHistoryEntry(ctx, domain.HistoryUpdate, id, current, output.Todo)); err != nil {
+			return err
+		}
+		if rule != nil {
+			output.Next, err = spawnNextOccurrence(ctx, svc, output.Todo, *rule)
+		}
+		return err
+	})
 	if err != nil {
 		return UpdateOutput{}, err
 	}
 
-	return UpdateOutput{Todo: todo}, nil
+	return output, nil
+}
+
+// recurrenceAfterUpdate returns the rule the todo follows once the update is applied.
+func recurrenceAfterUpdate(current domain.Todo, input UpdateInput) *domain.Recurrence {
+	switch {
+	case input.ClearRecurrence:
+		return nil
+	case input.Recurrence != nil:
+		return input.Recurrence
+	}
+	return current.Recurrence
+}
+
+// updateTags applies the tag changes to the todo, which must carry its current tags,
+// and refuses them when the todo would end up with more than domain.MaxTags tags.
+func updateTags(ctx context.Context, svc service.Todo, todo *domain.Todo, add, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	tags := domain.ApplyTags(todo.Tags, add, remove)
+	if len(tags) > domain.MaxTags {
+		return domain.ErrTooManyTags
+	}
+
+	if len(remove) > 0 {
+		if err := svc.RemoveTags(ctx, []service.TodoTags{{TodoID: todo.ID, Names: remove}}); err != nil {
+			return err
+		}
+	}
+	if len(add) > 0 {
+		if err := svc.AddTags(ctx, []service.TodoTags{{TodoID: todo.ID, Names: add}}); err != nil {
+			return err
+		}
+	}
+
+	todo.Tags = tags
+	return nil
+}
+
+// checkStatusChange validates a status change against the current state of the todo.
+// The version is checked first so a stale client gets a precondition failure rather
+// than a transition error computed from a status it has not seen. Work cannot start on
+// a todo that still depends on open todos.
+func (u *Todo) checkStatusChange(current domain.Todo, input UpdateInput) error {
+	if input.ExpectedVersion != nil && current.Version != *input.ExpectedVersion {
+		return domain.ErrVersionMismatch
+	}
+	if err := u.workflow.Transition(current.Status, *input.Status); err != nil {
+		return err
+	}
+	if *input.Status == domain.StatusInProgress && current.Status != domain.StatusInProgress &&
+		current.Dependencies != nil && current.Dependencies.Blocked() {
+		return domain.ErrTodoBlocked
+	}
+	return nil
+}
+
+// checkParent verifies that parentID names a live todo and, when id is set, that making it
+// the parent of id would not create a cycle, i.e. that id is not parentID or one of its ancestors.
+func checkParent(ctx context.Context, svc service.Todo, id, parentID string) error {
+	if _, err := getTodo(ctx, svc, parentID); err != nil {
+		if errors.Is(err, domain.ErrTodoNotFound) {
+			return domain.ErrInvalidParent
+		}
+		return err
+	}
+
+	if id == "" {
+		return nil
+	}
+
+	ancestors, err := svc.GetAncestorIDs(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor == id {
+			return domain.ErrParentCycle
+		}
+	}
+	return nil
+}
+
+// Delete moves the todo to the trash and applies the children policy to its subtasks,
+// recording every todo it touches in the history, all in one transaction. With the default restrict policy a todo that still has live
+// subtasks is not deleted.
+func (u *Todo) Delete(ctx context.Context, id string, input DeleteInput) error {
+	ctx, span := tracer.Start(ctx, "Todo.Delete")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionDelete); err != nil {
+		return err
+	}
+
+	childPolicy := input.Children
+	if childPolicy == "" {
+		childPolicy = domain.ChildrenRestrict
+	}
+
+	return u.service.Transaction(ctx, func(svc service.Todo) error {
+		current, err := getTodo(ctx, svc, id)
+		if err != nil {
+			return err
+		}
+		if input.ExpectedVersion != nil && current.Version != *input.ExpectedVersion {
+			return domain.ErrVersionMismatch
+		}
+
+		var entries []domain.HistoryEntry
+		switch childPolicy {
+		case domain.ChildrenCascade:
+			children, err := svc.DeleteDescendants(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, child := range children {
+				entries = append(entries, newHistoryEntry(ctx, domain.HistoryDelete, child.ID, child, domain.Todo{}))
+			}
+		case domain.ChildrenOrphan:
+			children, err := svc.OrphanChildren(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, child := range children {
+				before := child
+				before.ParentID = &id
+				entries = append(entries, newHistoryEntry(ctx, domain.HistoryUpdate, child.ID, before, child))
+			}
+		default:
+			if current.Subtasks != nil && current.Subtasks.Total > 0 {
+				return domain.ErrTodoHasChildren
+			}
+		}
+
+		if err := svc.Delete(ctx, id, service.DeleteInput{ExpectedVersion: input.ExpectedVersion}); err != nil {
+			return err
+		}
+
+		entries = append(entries, newHistoryEntry(ctx, domain.HistoryDelete, id, current, domain.Todo{}))
+		return addHistory(ctx, svc, entries...)
+	})
+}
+
+// Restore takes the todo out of the trash along with the subtasks that were cascaded into
+// it with the todo, recording every todo it touches in the history, all in one
+// transaction. A subtask cannot be restored while its parent is still in the trash.
+func (u *Todo) Restore(ctx context.Context, id string) (RestoreOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.Restore")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionDelete); err != nil {
+		return RestoreOutput{}, err
+	}
+
+	var todo domain.Todo
+	err := u.service.Transaction(ctx, func(svc service.Todo) error {
+		// the subtasks are found through the deleted_at of the todo, so they come back first
+		children, err := svc.RestoreDescendants(ctx, id)
+		if err != nil {
+			return err
+		}
+		todo, err = svc.Restore(ctx, id)
+		if err != nil {
+			return err
+		}
+		// the restored todo comes without its shares, which only matter when it is not
+		// visible to the user as owner or assignee
+		if !todo.VisibleTo(actingUser(ctx)) {
+			if _, err := getTodo(ctx, svc, id); err != nil {
+				return err
+			}
+		}
+
+		var entries []domain.HistoryEntry
+		for _, child := range children {
+			entries = append(entries, newHistoryEntry(ctx, domain.HistoryRestore, child.ID, domain.Todo{}, child))
+		}
+		entries = append(entries, newHistoryEntry(ctx, domain.HistoryRestore, id, domain.Todo{}, todo))
+		return addHistory(ctx, svc, entries...)
+	})
+	if err != nil {
+		return RestoreOutput{}, err
+	}
+
+	return RestoreOutput{Todo: todo}, nil
+}
+
+// Purge permanently removes the todos that have been in the trash for longer than the
+// retention, then the content of their attachments from the blob store. Every blob is
+// attempted even when one cannot be removed, and the failures are reported together.
+func (u *Todo) Purge(ctx context.Context) (PurgeOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.Purge")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionPurge); err != nil {
+		return PurgeOutput{}, err
+	}
+
+	result, err := u.service.Purge(ctx, time.Now().Add(-u.trashRetention))
+	if err != nil {
+		return PurgeOutput{}, err
+	}
+
+	if u.blobs != nil {
+		var errs []error
+		for _, key := range result.StorageKeys {
+			if err := u.blobs.Delete(ctx, key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return PurgeOutput{}, fmt.Errorf("purge attachments: %w", err)
+		}
+	}
+
+	return PurgeOutput{Purged: result.Purged}, nil
 }
 
-func (u *Todo) Delete(ctx context.Context, id string) error {
-	return u.service.Delete(ctx, id)
+// ListTags returns the tags in use with the number of live todos carrying each one,
+// counting only the todos the acting user can see.
+func (u *Todo) ListTags(ctx context.Context) (ListTagsOutput, error) {
+	ctx, span := tracer.Start(ctx, "Todo.ListTags")
+	defer span.End()
+
+	if err := u.policy.Authorize(ctx, policy.ActionRead); err != nil {
+		return ListTagsOutput{}, err
+	}
+
+	var visibleTo *string
+	if user := actingUser(ctx); user != "" {
+		visibleTo = &user
+	}
+
+	tags, err := u.service.ListTags(ctx, visibleTo)
+	if err != nil {
+		return ListTagsOutput{}, err
+	}
+
+	return ListTagsOutput{Tags: tags}, nil
+}
+
+// newServiceCreateInput applies the creation defaults: pending status and medium priority.
+// The todo is owned by the acting user, if any.
+func newServiceCreateInput(ctx context.Context, input CreateInput) service.CreateInput {
+	status := domain.StatusPending
+	if input.Status != nil {
+		status = *input.Status
+	}
+
+	priority := domain.PriorityMedium
+	if input.Priority != nil {
+		priority = *input.Priority
+	}
+
+	var ownerID *string
+	if user := actingUser(ctx); user != "" {
+		ownerID = &user
+	}
+
+	return service.CreateInput{
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      status,
+		Priority:    priority,
+		DueAt:       input.DueAt,
+		ParentID:    input.ParentID,
+		Recurrence:  input.Recurrence,
+		OwnerID:     ownerID,
+		AssigneeID:  input.AssigneeID,
+	}
+}
+
+func newServiceUpdateInput(input UpdateInput) service.UpdateInput {
+	return service.UpdateInput{
+		Title:           input.Title,
+		Description:     input.Description,
+		Status:          input.Status,
+		Priority:        input.Priority,
+		DueAt:           input.DueAt,
+		ClearDueAt:      input.ClearDueAt,
+		ParentID:        input.ParentID,
+		ClearParent:     input.ClearParent,
+		Recurrence:      input.Recurrence,
+		ClearRecurrence: input.ClearRecurrence,
+		AssigneeID:      input.AssigneeID,
+		ClearAssignee:   input.ClearAssignee,
+		ExpectedVersion: input.ExpectedVersion,
+	}
 }
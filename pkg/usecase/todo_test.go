@@ -3,9 +3,17 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
 	"todo-api/pkg/domain"
 	"todo-api/pkg/service"
 	"todo-api/pkg/usecase"
@@ -28,6 +36,7 @@ func buildValidTodo() domain.Todo {
 		Description: "This is a test description",
 		Status:      domain.StatusPending,
 		Priority:    domain.PriorityMedium,
+		Version:     1,
 		CreatedAt:   fixedTime,
 		UpdatedAt:   fixedTime,
 	}
@@ -37,9 +46,12 @@ func TestTodo_Get_ReturnsListSuccessfully(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	expectedTodos := []domain.Todo{expectedTodo}
 	mock := &test.MockTodoService{
-		GetFn: func(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
 			return expectedTodos, nil
 		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 1, nil
+		},
 	}
 	uc := usecase.New(mock)
 	input := usecase.ListInput{}
@@ -60,32 +72,34 @@ func TestTodo_Get_ReturnsListSuccessfully(t *testing.T) {
 func TestTodo_Get_PassesStatusFilterToService(t *testing.T) {
 	var capturedFilters service.Filters
 	mock := &test.MockTodoService{
-		GetFn: func(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
 			capturedFilters = filters
 			return []domain.Todo{}, nil
 		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
 	}
 	uc := usecase.New(mock)
-	status := domain.StatusCompleted
-	input := usecase.ListInput{Status: &status}
+	input := usecase.ListInput{Statuses: []domain.Status{domain.StatusCompleted}}
 
 	_, err := uc.Get(context.Background(), input)
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
-	if capturedFilters.Status == nil {
-		t.Error("expected status filter to be set")
+	if len(capturedFilters.Statuses) != 1 {
+		t.Fatalf("expected 1 status filter, got %d", len(capturedFilters.Statuses))
 	}
-	if *capturedFilters.Status != domain.StatusCompleted {
-		t.Errorf("expected status %s, got %s", domain.StatusCompleted, *capturedFilters.Status)
+	if capturedFilters.Statuses[0] != domain.StatusCompleted {
+		t.Errorf("expected status %s, got %s", domain.StatusCompleted, capturedFilters.Statuses[0])
 	}
 }
 
 func TestTodo_Get_ReturnsErrorWhenServiceFails(t *testing.T) {
 	expectedErr := errors.New("database error")
 	mock := &test.MockTodoService{
-		GetFn: func(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
 			return nil, expectedErr
 		},
 	}
@@ -98,6 +112,293 @@ func TestTodo_Get_ReturnsErrorWhenServiceFails(t *testing.T) {
 	}
 }
 
+func TestTodo_Get_ListsTrashByDeletionTime(t *testing.T) {
+	var capturedFilters service.Filters
+	var capturedPage service.Page
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			capturedPage = page
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{Deleted: true})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if !capturedFilters.Deleted {
+		t.Error("expected the trash to be listed")
+	}
+	if domain.FormatSort(capturedPage.Sort) != domain.FormatSort(domain.TrashSort) {
+		t.Errorf("expected sort %s, got %s", domain.FormatSort(domain.TrashSort), domain.FormatSort(capturedPage.Sort))
+	}
+}
+
+func TestTodo_Get_AppliesDefaultLimit(t *testing.T) {
+	var capturedPage service.Page
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedPage = page
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if capturedPage.Limit != usecase.DefaultLimit+1 {
+		t.Errorf("expected page limit %d, got %d", usecase.DefaultLimit+1, capturedPage.Limit)
+	}
+	if capturedPage.After != nil {
+		t.Error("expected no cursor on first page")
+	}
+}
+
+func TestTodo_Get_ReturnsTotalFromCount(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 42, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Get(context.Background(), usecase.ListInput{})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if result.Total != 42 {
+		t.Errorf("expected total 42, got %d", result.Total)
+	}
+}
+
+func TestTodo_Get_NextCursorRoundTrips(t *testing.T) {
+	first := buildValidTodo()
+	second := buildValidTodo()
+	second.ID = nonExistentID
+	second.CreatedAt = fixedTime.Add(-time.Minute)
+	var capturedPage service.Page
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedPage = page
+			return []domain.Todo{first, second}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 2, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Get(context.Background(), usecase.ListInput{Limit: 1})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Todos) != 1 {
+		t.Fatalf("expected 1 todo, got %d", len(result.Todos))
+	}
+	if result.NextCursor == "" {
+		t.Fatal("expected next cursor to be set")
+	}
+
+	_, err = uc.Get(context.Background(), usecase.ListInput{Limit: 1, Cursor: result.NextCursor})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if capturedPage.After == nil {
+		t.Fatal("expected cursor to be passed to service")
+	}
+	if capturedPage.After.ID != first.ID {
+		t.Errorf("expected cursor ID %s, got %s", first.ID, capturedPage.After.ID)
+	}
+	if len(capturedPage.After.Values) != 1 || capturedPage.After.Values[0] != first.CreatedAt.Format(time.RFC3339Nano) {
+		t.Errorf("expected cursor values [%s], got %v", first.CreatedAt.Format(time.RFC3339Nano), capturedPage.After.Values)
+	}
+}
+
+func TestTodo_Get_NoNextCursorOnLastPage(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo()}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 1, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Get(context.Background(), usecase.ListInput{Limit: 1})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if result.NextCursor != "" {
+		t.Errorf("expected empty next cursor, got %s", result.NextCursor)
+	}
+}
+
+func TestTodo_Get_ReturnsErrInvalidCursor(t *testing.T) {
+	uc := usecase.New(&test.MockTodoService{})
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{Cursor: "not-a-cursor"})
+
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected error %v, got %v", domain.ErrInvalidCursor, err)
+	}
+}
+
+func TestTodo_Get_RejectsCursorFromAnotherSort(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			return []domain.Todo{buildValidTodo(), buildValidTodo()}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 2, nil
+		},
+	}
+	uc := usecase.New(mock)
+	first, err := uc.Get(context.Background(), usecase.ListInput{Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = uc.Get(context.Background(), usecase.ListInput{
+		Limit:  1,
+		Cursor: first.NextCursor,
+		Sort:   []domain.SortOrder{{Field: domain.SortTitle}},
+	})
+
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected error %v, got %v", domain.ErrInvalidCursor, err)
+	}
+}
+
+func TestTodo_Get_PassesSortToService(t *testing.T) {
+	var capturedPage service.Page
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedPage = page
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	uc := usecase.New(mock)
+	sort := []domain.SortOrder{{Field: domain.SortPriority, Desc: true}}
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{Sort: sort})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(capturedPage.Sort) != 1 || capturedPage.Sort[0] != sort[0] {
+		t.Errorf("expected sort %v, got %v", sort, capturedPage.Sort)
+	}
+}
+
+func TestTodo_Get_DefaultsToRelevanceSortWhenSearching(t *testing.T) {
+	var capturedPage service.Page
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			capturedPage = page
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{Query: "invoice"})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if capturedFilters.Query != "invoice" {
+		t.Errorf("expected query %s, got %s", "invoice", capturedFilters.Query)
+	}
+	if len(capturedPage.Sort) != 1 || capturedPage.Sort[0].Field != domain.SortRelevance {
+		t.Errorf("expected relevance sort, got %v", capturedPage.Sort)
+	}
+}
+
+func TestTodo_Get_RejectsRelevanceSortWithoutQuery(t *testing.T) {
+	uc := usecase.New(&test.MockTodoService{})
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{
+		Sort: []domain.SortOrder{{Field: domain.SortRelevance, Desc: true}},
+	})
+
+	if !errors.Is(err, domain.ErrRelevanceSortQuery) {
+		t.Errorf("expected error %v, got %v", domain.ErrRelevanceSortQuery, err)
+	}
+}
+
+func TestTodo_Get_ReturnsErrorWhenCountFails(t *testing.T) {
+	expectedErr := errors.New("database error")
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, expectedErr
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{})
+
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("expected error %v, got %v", expectedErr, err)
+	}
+}
+
+func TestTodo_Get_PassesDueFiltersToService(t *testing.T) {
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			return []domain.Todo{}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	uc := usecase.New(mock)
+	overdue := true
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{DueBefore: &fixedTime, Overdue: &overdue})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if capturedFilters.DueBefore == nil || !capturedFilters.DueBefore.Equal(fixedTime) {
+		t.Errorf("expected due_before %v, got %v", fixedTime, capturedFilters.DueBefore)
+	}
+	if capturedFilters.Overdue == nil || !*capturedFilters.Overdue {
+		t.Error("expected overdue filter to be true")
+	}
+}
+
 func TestTodo_GetByID_ReturnsTodoSuccessfully(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	mock := &test.MockTodoService{
@@ -107,7 +408,7 @@ func TestTodo_GetByID_ReturnsTodoSuccessfully(t *testing.T) {
 	}
 	uc := usecase.New(mock)
 
-	result, err := uc.GetByID(context.Background(), validUUID)
+	result, err := uc.GetByID(context.Background(), validUUID, usecase.GetByIDInput{})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -120,6 +421,37 @@ func TestTodo_GetByID_ReturnsTodoSuccessfully(t *testing.T) {
 	}
 }
 
+func TestTodo_GetByID_TracesWithinTheRequestSpan(t *testing.T) {
+	// the tracer of the use cases sticks to the first global provider, so no other test sets one
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	var serviceSpan trace.SpanContext
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			serviceSpan = trace.SpanContextFromContext(ctx)
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+	ctx, request := otel.Tracer("test").Start(context.Background(), "GET /todos/:id")
+
+	if _, err := uc.GetByID(ctx, validUUID, usecase.GetByIDInput{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected a single ended span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "Todo.GetByID" || span.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Errorf("expected a Todo.GetByID span child of the request, got %s with parent %s", span.Name(), span.Parent().SpanID())
+	}
+	if serviceSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("expected the service to be called within the usecase span")
+	}
+}
+
 func TestTodo_GetByID_ReturnsErrTodoNotFound(t *testing.T) {
 	mock := &test.MockTodoService{
 		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
@@ -128,7 +460,7 @@ func TestTodo_GetByID_ReturnsErrTodoNotFound(t *testing.T) {
 	}
 	uc := usecase.New(mock)
 
-	_, err := uc.GetByID(context.Background(), nonExistentID)
+	_, err := uc.GetByID(context.Background(), nonExistentID, usecase.GetByIDInput{})
 
 	if !errors.Is(err, domain.ErrTodoNotFound) {
 		t.Errorf("expected error %v, got %v", domain.ErrTodoNotFound, err)
@@ -213,6 +545,9 @@ func TestTodo_Update_Successfully(t *testing.T) {
 	expectedTodo := buildValidTodo()
 	expectedTodo.Title = updatedTitle
 	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
 		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
 			return expectedTodo, nil
 		},
@@ -233,6 +568,9 @@ func TestTodo_Update_Successfully(t *testing.T) {
 
 func TestTodo_Update_ReturnsErrTodoNotFound(t *testing.T) {
 	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
 		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
 			return domain.Todo{}, domain.ErrTodoNotFound
 		},
@@ -248,37 +586,597 @@ func TestTodo_Update_ReturnsErrTodoNotFound(t *testing.T) {
 	}
 }
 
-func TestTodo_Delete_Successfully(t *testing.T) {
-	var capturedID string
+func TestTodo_Update_LocksTheTodoBeforeReadingIt(t *testing.T) {
+	var calls []string
 	mock := &test.MockTodoService{
-		DeleteFn: func(ctx context.Context, id string) error {
-			capturedID = id
+		LockFn: func(ctx context.Context, ids []string) error {
+			calls = append(calls, "lock "+strings.Join(ids, ","))
 			return nil
 		},
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			calls = append(calls, "get "+id)
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			calls = append(calls, "update "+id)
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+	title := updatedTitle
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Title: &title})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{"lock " + validUUID, "get " + validUUID, "update " + validUUID}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+func TestTodo_Update_PassesDueAtChangesToService(t *testing.T) {
+	var capturedInput service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
+		},
 	}
 	uc := usecase.New(mock)
 
-	err := uc.Delete(context.Background(), validUUID)
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{ClearDueAt: true})
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
-	if capturedID != validUUID {
-		t.Errorf("expected ID %s, got %s", validUUID, capturedID)
+	if !capturedInput.ClearDueAt {
+		t.Error("expected ClearDueAt to be passed to service")
 	}
 }
 
-func TestTodo_Delete_ReturnsErrTodoNotFound(t *testing.T) {
+func TestTodo_Update_AllowsLegalTransition(t *testing.T) {
+	var capturedInput service.UpdateInput
 	mock := &test.MockTodoService{
-		DeleteFn: func(ctx context.Context, id string) error {
-			return domain.ErrTodoNotFound
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
 		},
 	}
 	uc := usecase.New(mock)
+	status := domain.StatusInProgress
 
-	err := uc.Delete(context.Background(), nonExistentID)
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status})
 
-	if !errors.Is(err, domain.ErrTodoNotFound) {
-		t.Errorf("expected error %v, got %v", domain.ErrTodoNotFound, err)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if capturedInput.Status == nil || *capturedInput.Status != domain.StatusInProgress {
+		t.Errorf("expected status %s to be passed to service", domain.StatusInProgress)
+	}
+}
+
+func TestTodo_Update_RejectsIllegalTransition(t *testing.T) {
+	current := buildValidTodo()
+	current.Status = domain.StatusCompleted
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return current, nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusPending
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status})
+
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("expected error %v, got %v", domain.ErrInvalidTransition, err)
+	}
+}
+
+func TestTodo_Update_UsesConfiguredWorkflow(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	workflow := domain.MustNewWorkflow(map[domain.Status][]domain.Status{
+		domain.StatusPending: {domain.StatusInProgress},
+	})
+	uc := usecase.New(mock, usecase.WithWorkflow(workflow))
+	status := domain.StatusCompleted
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status})
+
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("expected error %v, got %v", domain.ErrInvalidTransition, err)
+	}
+}
+
+func TestTodo_Update_ReturnsErrTodoNotFoundOnStatusChange(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusCompleted
+
+	_, err := uc.Update(context.Background(), nonExistentID, usecase.UpdateInput{Status: &status})
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected error %v, got %v", domain.ErrTodoNotFound, err)
+	}
+}
+
+func TestTodo_Update_RejectsStaleVersionOnStatusChange(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+	status := domain.StatusInProgress
+	version := 2
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Status: &status, ExpectedVersion: &version})
+
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Errorf("expected error %v, got %v", domain.ErrVersionMismatch, err)
+	}
+}
+
+func TestTodo_Update_PassesExpectedVersionToService(t *testing.T) {
+	var capturedInput service.UpdateInput
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			capturedInput = input
+			return buildValidTodo(), nil
+		},
+	}
+	uc := usecase.New(mock)
+	title := "Updated"
+	version := 1
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{Title: &title, ExpectedVersion: &version})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if capturedInput.ExpectedVersion == nil || *capturedInput.ExpectedVersion != version {
+		t.Errorf("expected version %d to be passed to service", version)
+	}
+}
+
+func TestTodo_Delete_Successfully(t *testing.T) {
+	var capturedID string
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
+			capturedID = id
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	err := uc.Delete(context.Background(), validUUID, usecase.DeleteInput{})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if capturedID != validUUID {
+		t.Errorf("expected ID %s, got %s", validUUID, capturedID)
+	}
+}
+
+func TestTodo_Delete_ReturnsErrTodoNotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
+			return domain.ErrTodoNotFound
+		},
+	}
+	uc := usecase.New(mock)
+
+	err := uc.Delete(context.Background(), nonExistentID, usecase.DeleteInput{})
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected error %v, got %v", domain.ErrTodoNotFound, err)
+	}
+}
+
+func TestTodo_Restore_Successfully(t *testing.T) {
+	expectedTodo := buildValidTodo()
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return expectedTodo, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	output, err := uc.Restore(context.Background(), validUUID)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if output.Todo.ID != expectedTodo.ID {
+		t.Errorf("expected ID %s, got %s", expectedTodo.ID, output.Todo.ID)
+	}
+}
+
+func TestTodo_Restore_ReturnsErrTodoNotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return nil, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Restore(context.Background(), nonExistentID)
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected error %v, got %v", domain.ErrTodoNotFound, err)
+	}
+}
+
+func TestTodo_Restore_ReturnsErrParentTrashed(t *testing.T) {
+	var rolledBack bool
+	mock := &test.MockTodoService{
+		RestoreDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			return []domain.Todo{{ID: blockerUUID}}, nil
+		},
+		RestoreFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrParentTrashed
+		},
+	}
+	mock.TransactionFn = func(ctx context.Context, fn func(service.Todo) error) error {
+		err := fn(mock)
+		rolledBack = err != nil
+		return err
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Restore(context.Background(), validUUID)
+
+	if !errors.Is(err, domain.ErrParentTrashed) {
+		t.Errorf("expected error %v, got %v", domain.ErrParentTrashed, err)
+	}
+	if !rolledBack {
+		t.Error("expected the restored subtasks to be rolled back")
+	}
+}
+
+func TestTodo_Purge_UsesConfiguredRetention(t *testing.T) {
+	var capturedCutoff time.Time
+	mock := &test.MockTodoService{
+		PurgeFn: func(ctx context.Context, deletedBefore time.Time) (service.PurgeResult, error) {
+			capturedCutoff = deletedBefore
+			return service.PurgeResult{Purged: 2}, nil
+		},
+	}
+	uc := usecase.New(mock, usecase.WithTrashRetention(48*time.Hour))
+
+	before := time.Now()
+	output, err := uc.Purge(context.Background())
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if output.Purged != 2 {
+		t.Errorf("expected 2 purged todos, got %d", output.Purged)
+	}
+	expected := before.Add(-48 * time.Hour)
+	if capturedCutoff.Before(expected) || capturedCutoff.After(expected.Add(time.Minute)) {
+		t.Errorf("expected cutoff around %v, got %v", expected, capturedCutoff)
+	}
+}
+
+func TestTodo_Purge_RemovesTheBlobsOfTheAttachments(t *testing.T) {
+	blobs := test.NewMockBlobStore()
+	blobs.Blobs[validUUID+"/a"] = []byte("a")
+	blobs.Blobs[validUUID+"/b"] = []byte("b")
+	blobs.Blobs[nonExistentID+"/c"] = []byte("c")
+	mock := &test.MockTodoService{
+		PurgeFn: func(ctx context.Context, deletedBefore time.Time) (service.PurgeResult, error) {
+			return service.PurgeResult{Purged: 1, StorageKeys: []string{validUUID + "/a", validUUID + "/b"}}, nil
+		},
+	}
+	uc := usecase.New(mock, usecase.WithBlobStore(blobs))
+
+	output, err := uc.Purge(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if output.Purged != 1 {
+		t.Errorf("expected 1 purged todo, got %d", output.Purged)
+	}
+	if _, ok := blobs.Blobs[nonExistentID+"/c"]; len(blobs.Blobs) != 1 || !ok {
+		t.Errorf("expected only the blobs of the purged todo to be removed, got %v", blobs.Blobs)
+	}
+}
+
+func TestTodo_GetByID_IncludesChildren(t *testing.T) {
+	parent := buildValidTodo()
+	parent.Subtasks = &domain.Subtasks{Total: 1}
+	child := buildValidTodo()
+	child.ID = nonExistentID
+	child.ParentID = &parent.ID
+	var capturedFilters service.Filters
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return parent, nil
+		},
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			capturedFilters = filters
+			return []domain.Todo{child}, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 1, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.GetByID(context.Background(), validUUID, usecase.GetByIDInput{IncludeChildren: true})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if capturedFilters.ParentID == nil || *capturedFilters.ParentID != validUUID {
+		t.Errorf("expected children of %s, got %v", validUUID, capturedFilters.ParentID)
+	}
+	if len(result.Children) != 1 || result.Children[0].ID != child.ID {
+		t.Errorf("expected child %s, got %+v", child.ID, result.Children)
+	}
+}
+
+func TestTodo_GetChildren_ReturnsErrTodoNotFound(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.GetChildren(context.Background(), nonExistentID, usecase.ListInput{})
+
+	if !errors.Is(err, domain.ErrTodoNotFound) {
+		t.Errorf("expected error %v, got %v", domain.ErrTodoNotFound, err)
+	}
+}
+
+func TestTodo_Create_RejectsUnknownParent(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return domain.Todo{}, domain.ErrTodoNotFound
+		},
+	}
+	uc := usecase.New(mock)
+	parentID := nonExistentID
+
+	_, err := uc.Create(context.Background(), usecase.CreateInput{Title: validTitle, ParentID: &parentID})
+
+	if !errors.Is(err, domain.ErrInvalidParent) {
+		t.Errorf("expected error %v, got %v", domain.ErrInvalidParent, err)
+	}
+}
+
+func TestTodo_Update_RejectsParentCycle(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		GetAncestorIDsFn: func(ctx context.Context, id string) ([]string, error) {
+			return []string{nonExistentID, validUUID}, nil
+		},
+	}
+	uc := usecase.New(mock)
+	parentID := nonExistentID
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{ParentID: &parentID})
+
+	if !errors.Is(err, domain.ErrParentCycle) {
+		t.Errorf("expected error %v, got %v", domain.ErrParentCycle, err)
+	}
+}
+
+func TestTodo_Delete_RestrictsTodoWithSubtasks(t *testing.T) {
+	parent := buildValidTodo()
+	parent.Subtasks = &domain.Subtasks{Total: 2}
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return parent, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	err := uc.Delete(context.Background(), validUUID, usecase.DeleteInput{})
+
+	if !errors.Is(err, domain.ErrTodoHasChildren) {
+		t.Errorf("expected error %v, got %v", domain.ErrTodoHasChildren, err)
+	}
+}
+
+func TestTodo_Delete_AppliesChildrenPolicy(t *testing.T) {
+	parent := buildValidTodo()
+	parent.Subtasks = &domain.Subtasks{Total: 2}
+	var cascaded, orphaned int
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return parent, nil
+		},
+		DeleteDescendantsFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			cascaded++
+			return []domain.Todo{{ID: blockerUUID}}, nil
+		},
+		OrphanChildrenFn: func(ctx context.Context, id string) ([]domain.Todo, error) {
+			orphaned++
+			return []domain.Todo{{ID: blockerUUID}}, nil
+		},
+		DeleteFn: func(ctx context.Context, id string, input service.DeleteInput) error {
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	if err := uc.Delete(context.Background(), validUUID, usecase.DeleteInput{Children: domain.ChildrenCascade}); err != nil {
+		t.Errorf("expected no error on cascade, got %v", err)
+	}
+	if err := uc.Delete(context.Background(), validUUID, usecase.DeleteInput{Children: domain.ChildrenOrphan}); err != nil {
+		t.Errorf("expected no error on orphan, got %v", err)
+	}
+	if cascaded != 1 || orphaned != 1 {
+		t.Errorf("expected one cascade and one orphan, got %d and %d", cascaded, orphaned)
+	}
+}
+
+func TestTodo_Get_PassesTagFilter(t *testing.T) {
+	mock := &test.MockTodoService{
+		GetFn: func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+			if len(filters.Tags) != 2 || !filters.MatchAllTags {
+				t.Errorf("expected both tags to be required, got %+v", filters)
+			}
+			return nil, nil
+		},
+		CountFn: func(ctx context.Context, filters service.Filters) (int, error) {
+			return 0, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Get(context.Background(), usecase.ListInput{Tags: []string{"backend", "q3"}, TagMatch: domain.TagMatchAll})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestTodo_Create_AddsTags(t *testing.T) {
+	var added []service.TodoTags
+	mock := &test.MockTodoService{
+		CreateFn: func(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			added = items
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Create(context.Background(), usecase.CreateInput{Title: validTitle, Tags: []string{"backend", "q3"}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(added) != 1 || added[0].TodoID != validUUID || len(added[0].Names) != 2 {
+		t.Errorf("expected both tags to be added to the todo, got %+v", added)
+	}
+	if len(result.Todo.Tags) != 2 {
+		t.Errorf("expected the created todo to carry its tags, got %v", result.Todo.Tags)
+	}
+}
+
+func TestTodo_Update_AppliesTagChanges(t *testing.T) {
+	var added, removed []service.TodoTags
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Tags = []string{"backend", "q3"}
+			return todo, nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			added = items
+			return nil
+		},
+		RemoveTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			removed = items
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{
+		AddTags:    []string{"billing"},
+		RemoveTags: []string{"q3"},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(added) != 1 || len(removed) != 1 {
+		t.Errorf("expected one addition and one removal, got %+v and %+v", added, removed)
+	}
+	if len(result.Todo.Tags) != 2 || result.Todo.Tags[0] != "backend" || result.Todo.Tags[1] != "billing" {
+		t.Errorf("expected tags [backend billing], got %v", result.Todo.Tags)
+	}
+}
+
+func TestTodo_Update_RejectsTooManyTags(t *testing.T) {
+	current := make([]string, domain.MaxTags)
+	for i := range current {
+		current[i] = fmt.Sprintf("tag%02d", i)
+	}
+	mock := &test.MockTodoService{
+		GetByIDFn: func(ctx context.Context, id string) (domain.Todo, error) {
+			return buildValidTodo(), nil
+		},
+		UpdateFn: func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
+			todo := buildValidTodo()
+			todo.Tags = current
+			return todo, nil
+		},
+		AddTagsFn: func(ctx context.Context, items []service.TodoTags) error {
+			t.Error("expected no tags to be added")
+			return nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	_, err := uc.Update(context.Background(), validUUID, usecase.UpdateInput{AddTags: []string{"one-more"}})
+
+	if !errors.Is(err, domain.ErrTooManyTags) {
+		t.Errorf("expected error %v, got %v", domain.ErrTooManyTags, err)
+	}
+}
+
+func TestTodo_ListTags_ReturnsUsage(t *testing.T) {
+	mock := &test.MockTodoService{
+		ListTagsFn: func(ctx context.Context, visibleTo *string) ([]domain.TagUsage, error) {
+			return []domain.TagUsage{{Name: "backend", Count: 3}}, nil
+		},
+	}
+	uc := usecase.New(mock)
+
+	result, err := uc.ListTags(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Tags) != 1 || result.Tags[0].Count != 3 {
+		t.Errorf("expected backend used 3 times, got %+v", result.Tags)
 	}
 }
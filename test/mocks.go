@@ -7,6 +7,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"time"
 
 	"todo-api/pkg/domain"
 	"todo-api/pkg/service"
@@ -14,45 +15,298 @@ import (
 )
 
 type MockTodoService struct {
-	GetFn      func(ctx context.Context, filters service.Filters) ([]domain.Todo, error)
-	GetByIDFn  func(ctx context.Context, id string) (domain.Todo, error)
-	CreateFn   func(ctx context.Context, input service.CreateInput) (domain.Todo, error)
-	UpdateFn   func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error)
-	DeleteFn   func(ctx context.Context, id string) error
+	GetFn         func(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error)
+	CountFn       func(ctx context.Context, filters service.Filters) (int, error)
+	GetByIDFn     func(ctx context.Context, id string) (domain.Todo, error)
+	GetByIDsFn    func(ctx context.Context, ids []string) ([]domain.Todo, error)
+	LockFn        func(ctx context.Context, ids []string) error
+	CreateFn      func(ctx context.Context, input service.CreateInput) (domain.Todo, error)
+	CreateBatchFn func(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error)
+	UpdateFn      func(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error)
+	UpdateBatchFn func(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error)
+	DeleteFn      func(ctx context.Context, id string, input service.DeleteInput) error
+	DeleteBatchFn func(ctx context.Context, items []service.BatchDelete) ([]service.BatchResult, error)
+	RestoreFn     func(ctx context.Context, id string) (domain.Todo, error)
+	PurgeFn       func(ctx context.Context, deletedBefore time.Time) (service.PurgeResult, error)
+	TransactionFn func(ctx context.Context, fn func(service.Todo) error) error
+
+	GetAncestorIDsFn     func(ctx context.Context, id string) ([]string, error)
+	DeleteDescendantsFn  func(ctx context.Context, id string) ([]domain.Todo, error)
+	RestoreDescendantsFn func(ctx context.Context, id string) ([]domain.Todo, error)
+	OrphanChildrenFn     func(ctx context.Context, id string) ([]domain.Todo, error)
+
+	AddTagsFn    func(ctx context.Context, items []service.TodoTags) error
+	RemoveTagsFn func(ctx context.Context, items []service.TodoTags) error
+	ListTagsFn   func(ctx context.Context, visibleTo *string) ([]domain.TagUsage, error)
+
+	GetBlockerIDsFn    func(ctx context.Context, id string) ([]string, error)
+	GetBlockersFn      func(ctx context.Context, id string) ([]domain.Todo, error)
+	AddDependencyFn    func(ctx context.Context, id, blockerID string) error
+	RemoveDependencyFn func(ctx context.Context, id, blockerID string) error
+
+	ShareTodoFn   func(ctx context.Context, id, userID string) error
+	UnshareTodoFn func(ctx context.Context, id, userID string) error
+
+	AddHistoryFn   func(ctx context.Context, entries []domain.HistoryEntry) error
+	GetHistoryFn   func(ctx context.Context, todoID string, page service.HistoryPage) ([]domain.HistoryEntry, error)
+	CountHistoryFn func(ctx context.Context, todoID string) (int, error)
+}
+
+func (m *MockTodoService) Get(ctx context.Context, filters service.Filters, page service.Page) ([]domain.Todo, error) {
+	return m.GetFn(ctx, filters, page)
 }
 
-func (m *MockTodoService) Get(ctx context.Context, filters service.Filters) ([]domain.Todo, error) {
-	return m.GetFn(ctx, filters)
+func (m *MockTodoService) Count(ctx context.Context, filters service.Filters) (int, error) {
+	return m.CountFn(ctx, filters)
 }
 
 func (m *MockTodoService) GetByID(ctx context.Context, id string) (domain.Todo, error) {
 	return m.GetByIDFn(ctx, id)
 }
 
+func (m *MockTodoService) GetByIDs(ctx context.Context, ids []string) ([]domain.Todo, error) {
+	return m.GetByIDsFn(ctx, ids)
+}
+
+// Lock does nothing unless LockFn is set.
+func (m *MockTodoService) Lock(ctx context.Context, ids []string) error {
+	if m.LockFn == nil {
+		return nil
+	}
+	return m.LockFn(ctx, ids)
+}
+
 func (m *MockTodoService) Create(ctx context.Context, input service.CreateInput) (domain.Todo, error) {
 	return m.CreateFn(ctx, input)
 }
 
+func (m *MockTodoService) CreateBatch(ctx context.Context, inputs []service.CreateInput) ([]domain.Todo, error) {
+	return m.CreateBatchFn(ctx, inputs)
+}
+
 func (m *MockTodoService) Update(ctx context.Context, id string, input service.UpdateInput) (domain.Todo, error) {
 	return m.UpdateFn(ctx, id, input)
 }
 
-func (m *MockTodoService) Delete(ctx context.Context, id string) error {
-	return m.DeleteFn(ctx, id)
+func (m *MockTodoService) UpdateBatch(ctx context.Context, items []service.BatchUpdate) ([]service.BatchResult, error) {
+	return m.UpdateBatchFn(ctx, items)
+}
+
+func (m *MockTodoService) Delete(ctx context.Context, id string, input service.DeleteInput) error {
+	return m.DeleteFn(ctx, id, input)
+}
+
+func (m *MockTodoService) DeleteBatch(ctx context.Context, items []service.BatchDelete) ([]service.BatchResult, error) {
+	return m.DeleteBatchFn(ctx, items)
+}
+
+func (m *MockTodoService) Restore(ctx context.Context, id string) (domain.Todo, error) {
+	return m.RestoreFn(ctx, id)
+}
+
+func (m *MockTodoService) Purge(ctx context.Context, deletedBefore time.Time) (service.PurgeResult, error) {
+	return m.PurgeFn(ctx, deletedBefore)
+}
+
+func (m *MockTodoService) GetAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	return m.GetAncestorIDsFn(ctx, id)
+}
+
+func (m *MockTodoService) DeleteDescendants(ctx context.Context, id string) ([]domain.Todo, error) {
+	return m.DeleteDescendantsFn(ctx, id)
+}
+
+func (m *MockTodoService) RestoreDescendants(ctx context.Context, id string) ([]domain.Todo, error) {
+	return m.RestoreDescendantsFn(ctx, id)
+}
+
+func (m *MockTodoService) OrphanChildren(ctx context.Context, id string) ([]domain.Todo, error) {
+	return m.OrphanChildrenFn(ctx, id)
+}
+
+func (m *MockTodoService) AddTags(ctx context.Context, items []service.TodoTags) error {
+	return m.AddTagsFn(ctx, items)
+}
+
+func (m *MockTodoService) RemoveTags(ctx context.Context, items []service.TodoTags) error {
+	return m.RemoveTagsFn(ctx, items)
+}
+
+func (m *MockTodoService) ListTags(ctx context.Context, visibleTo *string) ([]domain.TagUsage, error) {
+	return m.ListTagsFn(ctx, visibleTo)
+}
+
+func (m *MockTodoService) GetBlockerIDs(ctx context.Context, id string) ([]string, error) {
+	return m.GetBlockerIDsFn(ctx, id)
+}
+
+func (m *MockTodoService) GetBlockers(ctx context.Context, id string) ([]domain.Todo, error) {
+	return m.GetBlockersFn(ctx, id)
+}
+
+func (m *MockTodoService) AddDependency(ctx context.Context, id, blockerID string) error {
+	return m.AddDependencyFn(ctx, id, blockerID)
+}
+
+func (m *MockTodoService) RemoveDependency(ctx context.Context, id, blockerID string) error {
+	return m.RemoveDependencyFn(ctx, id, blockerID)
+}
+
+func (m *MockTodoService) ShareTodo(ctx context.Context, id, userID string) error {
+	return m.ShareTodoFn(ctx, id, userID)
+}
+
+func (m *MockTodoService) UnshareTodo(ctx context.Context, id, userID string) error {
+	return m.UnshareTodoFn(ctx, id, userID)
+}
+
+// AddHistory records nothing unless AddHistoryFn is set, so that tests of mutations
+// only need to stub it when they assert on the history.
+func (m *MockTodoService) AddHistory(ctx context.Context, entries []domain.HistoryEntry) error {
+	if m.AddHistoryFn == nil {
+		return nil
+	}
+	return m.AddHistoryFn(ctx, entries)
+}
+
+func (m *MockTodoService) GetHistory(ctx context.Context, todoID string, page service.HistoryPage) ([]domain.HistoryEntry, error) {
+	return m.GetHistoryFn(ctx, todoID, page)
+}
+
+func (m *MockTodoService) CountHistory(ctx context.Context, todoID string) (int, error) {
+	return m.CountHistoryFn(ctx, todoID)
+}
+
+// Transaction runs fn against the mock itself unless TransactionFn is set.
+func (m *MockTodoService) Transaction(ctx context.Context, fn func(service.Todo) error) error {
+	if m.TransactionFn == nil {
+		return fn(m)
+	}
+	return m.TransactionFn(ctx, fn)
+}
+
+type MockCommentService struct {
+	ListFn    func(ctx context.Context, todoID string, page service.CommentPage) ([]domain.Comment, error)
+	CountFn   func(ctx context.Context, todoID string) (int, error)
+	GetByIDFn func(ctx context.Context, todoID, id string) (domain.Comment, error)
+	CreateFn  func(ctx context.Context, todoID, body, author string) (domain.Comment, error)
+	UpdateFn  func(ctx context.Context, todoID, id, body string) (domain.Comment, error)
+	DeleteFn  func(ctx context.Context, todoID, id string) error
+}
+
+func (m *MockCommentService) List(ctx context.Context, todoID string, page service.CommentPage) ([]domain.Comment, error) {
+	return m.ListFn(ctx, todoID, page)
+}
+
+func (m *MockCommentService) Count(ctx context.Context, todoID string) (int, error) {
+	return m.CountFn(ctx, todoID)
+}
+
+func (m *MockCommentService) GetByID(ctx context.Context, todoID, id string) (domain.Comment, error) {
+	return m.GetByIDFn(ctx, todoID, id)
+}
+
+func (m *MockCommentService) Create(ctx context.Context, todoID, body, author string) (domain.Comment, error) {
+	return m.CreateFn(ctx, todoID, body, author)
+}
+
+func (m *MockCommentService) Update(ctx context.Context, todoID, id, body string) (domain.Comment, error) {
+	return m.UpdateFn(ctx, todoID, id, body)
+}
+
+func (m *MockCommentService) Delete(ctx context.Context, todoID, id string) error {
+	return m.DeleteFn(ctx, todoID, id)
+}
+
+type MockAttachmentService struct {
+	ListFn    func(ctx context.Context, todoID string) ([]domain.Attachment, error)
+	GetByIDFn func(ctx context.Context, todoID, id string) (domain.Attachment, error)
+	CreateFn  func(ctx context.Context, input service.AttachmentInput) (domain.Attachment, error)
+}
+
+func (m *MockAttachmentService) List(ctx context.Context, todoID string) ([]domain.Attachment, error) {
+	return m.ListFn(ctx, todoID)
+}
+
+func (m *MockAttachmentService) GetByID(ctx context.Context, todoID, id string) (domain.Attachment, error) {
+	return m.GetByIDFn(ctx, todoID, id)
+}
+
+func (m *MockAttachmentService) Create(ctx context.Context, input service.AttachmentInput) (domain.Attachment, error) {
+	return m.CreateFn(ctx, input)
+}
+
+// MockBlobStore keeps blobs in memory.
+type MockBlobStore struct {
+	Blobs map[string][]byte
+}
+
+func NewMockBlobStore() *MockBlobStore {
+	return &MockBlobStore{Blobs: make(map[string][]byte)}
+}
+
+func (m *MockBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.Blobs[key] = b
+	return nil
+}
+
+func (m *MockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	b, ok := m.Blobs[key]
+	if !ok {
+		return nil, service.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	delete(m.Blobs, key)
+	return nil
+}
+
+type MockAPIKeyService struct {
+	ListFn      func(ctx context.Context) ([]domain.APIKey, error)
+	GetByHashFn func(ctx context.Context, hash string) (domain.APIKey, error)
+	CreateFn    func(ctx context.Context, input service.APIKeyInput) (domain.APIKey, error)
+	RevokeFn    func(ctx context.Context, id string) error
+}
+
+func (m *MockAPIKeyService) List(ctx context.Context) ([]domain.APIKey, error) {
+	return m.ListFn(ctx)
+}
+
+func (m *MockAPIKeyService) GetByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	return m.GetByHashFn(ctx, hash)
+}
+
+func (m *MockAPIKeyService) Create(ctx context.Context, input service.APIKeyInput) (domain.APIKey, error) {
+	return m.CreateFn(ctx, input)
+}
+
+func (m *MockAPIKeyService) Revoke(ctx context.Context, id string) error {
+	return m.RevokeFn(ctx, id)
 }
 
 type MockRequest struct {
 	Ctx        context.Context
+	Path       string
 	ParamsMap  map[string]string
-	QueriesMap map[string]string
+	QueriesMap url.Values
+	HeadersMap http.Header
 	BodyStr    string
+	FilesMap   map[string]*multipart.FileHeader
 }
 
 func NewMockRequest() *MockRequest {
 	return &MockRequest{
 		Ctx:        context.Background(),
 		ParamsMap:  make(map[string]string),
-		QueriesMap: make(map[string]string),
+		QueriesMap: make(url.Values),
+		HeadersMap: make(http.Header),
+		FilesMap:   make(map[string]*multipart.FileHeader),
 	}
 }
 
@@ -62,7 +316,17 @@ func (m *MockRequest) WithParam(key, value string) *MockRequest {
 }
 
 func (m *MockRequest) WithQuery(key, value string) *MockRequest {
-	m.QueriesMap[key] = value
+	m.QueriesMap.Add(key, value)
+	return m
+}
+
+func (m *MockRequest) WithHeader(key, value string) *MockRequest {
+	m.HeadersMap.Add(key, value)
+	return m
+}
+
+func (m *MockRequest) WithPath(path string) *MockRequest {
+	m.Path = path
 	return m
 }
 
@@ -71,24 +335,65 @@ func (m *MockRequest) WithBody(body string) *MockRequest {
 	return m
 }
 
-func (m *MockRequest) Context() context.Context                           { return m.Ctx }
-func (m *MockRequest) Raw() *http.Request                                 { return &http.Request{} }
-func (m *MockRequest) DeclaredPath() string                               { return "" }
-func (m *MockRequest) Params() []web.Param                                { return nil }
-func (m *MockRequest) Queries() url.Values                                { return nil }
-func (m *MockRequest) Headers() http.Header                               { return nil }
-func (m *MockRequest) Body() io.ReadCloser                                { return io.NopCloser(bytes.NewBufferString(m.BodyStr)) }
-func (m *MockRequest) Header(key string) ([]string, bool)                 { return nil, false }
-func (m *MockRequest) FormFile(key string) (*multipart.FileHeader, error) { return nil, nil }
-func (m *MockRequest) FormValue(key string) (string, bool)                { return "", false }
-func (m *MockRequest) MultipartForm() (*multipart.Form, error)            { return nil, nil }
+// WithFile attaches a file to the multipart form of the request under the field.
+func (m *MockRequest) WithFile(field, filename string, content []byte) *MockRequest {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreateFormFile(field, filename) // safe mute, writing to memory cannot fail
+	_, _ = part.Write(content)
+	_ = w.Close()
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(int64(len(content)) + 1024)
+	if err != nil {
+		panic(err)
+	}
+	m.FilesMap[field] = form.File[field][0]
+	return m
+}
+
+func (m *MockRequest) Context() context.Context                { return m.Ctx }
+func (m *MockRequest) Raw() *http.Request                      { return &http.Request{Header: m.HeadersMap} }
+func (m *MockRequest) DeclaredPath() string                    { return m.Path }
+func (m *MockRequest) Params() []web.Param                     { return nil }
+func (m *MockRequest) Queries() url.Values                     { return m.QueriesMap }
+func (m *MockRequest) Headers() http.Header                    { return m.HeadersMap }
+func (m *MockRequest) Body() io.ReadCloser                     { return io.NopCloser(bytes.NewBufferString(m.BodyStr)) }
+func (m *MockRequest) FormValue(key string) (string, bool)     { return "", false }
+func (m *MockRequest) MultipartForm() (*multipart.Form, error) { return nil, nil }
+
+func (m *MockRequest) FormFile(key string) (*multipart.FileHeader, error) {
+	fh, ok := m.FilesMap[key]
+	if !ok {
+		return nil, http.ErrMissingFile
+	}
+	return fh, nil
+}
 
 func (m *MockRequest) Param(key string) (string, bool) {
 	v, ok := m.ParamsMap[key]
 	return v, ok
 }
 
+func (m *MockRequest) Header(key string) ([]string, bool) {
+	v := m.HeadersMap.Values(key)
+	return v, len(v) > 0
+}
+
 func (m *MockRequest) Query(key string) (string, bool) {
-	v, ok := m.QueriesMap[key]
-	return v, ok
+	v := m.QueriesMap[key]
+	if len(v) == 0 {
+		return "", false
+	}
+	return v[0], true
 }
+
+// MockInterceptedRequest runs an interceptor over a MockRequest; NextFn stands for the
+// rest of the chain and receives the request as the interceptor left it.
+type MockInterceptedRequest struct {
+	*MockRequest
+	NextFn func(req *MockRequest) web.Response
+}
+
+func (m *MockInterceptedRequest) Apply(ctx context.Context)   { m.Ctx = ctx }
+func (m *MockInterceptedRequest) Next() web.Response          { return m.NextFn(m.MockRequest) }
+func (m *MockInterceptedRequest) Writer() http.ResponseWriter { return nil }
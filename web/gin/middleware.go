@@ -4,9 +4,14 @@ package gin
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"mime"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -36,6 +41,21 @@ type (
 
 	// interceptedResponseKey is a context key for storing and retrieving the intercepted response
 	interceptedResponseKey struct{}
+
+	// bufferedResponse holds back the status and body written further down the chain so an
+	// interceptor can still replace them before anything reaches the client. Responses that
+	// are not JSON documents, such as downloads, and flushed ones are written through instead.
+	bufferedResponse struct {
+		gin.ResponseWriter
+		// status is the status code the downstream handler asked for
+		status int
+		// body buffers the downstream response body
+		body *bytes.Buffer
+		// sniffed is set once the first write decided whether the response is buffered
+		sniffed bool
+		// streaming is set once the response is written through to the client
+		streaming bool
+	}
 )
 
 // noticeError is a placeholder for error monitoring
@@ -93,6 +113,17 @@ func NewInterceptor(fn web.Interceptor) gin.HandlerFunc {
 
 		resp := fn(ireq)
 
+		// Apply any header changes from the interceptor, before a swallowed response gets written
+		for k := range resp.Headers {
+			v := resp.Headers.Values(k)
+			if !stringsEqual(v, ir.Header().Values(k)) {
+				ir.Header().Del(k)
+				for _, vr := range v {
+					ir.Header().Add(k, vr)
+				}
+			}
+		}
+
 		// If interceptor didn't call Next(), it swallowed the response
 		// Write the interceptor's response and abort the chain
 		if !ireq.nextCalled {
@@ -105,17 +136,6 @@ func NewInterceptor(fn web.Interceptor) gin.HandlerFunc {
 			}
 			c.Abort() // prevent Gin from calling next handlers
 		}
-
-		// Apply any header changes from the interceptor
-		for k := range resp.Headers {
-			v := resp.Headers.Values(k)
-			if !stringsEqual(v, ir.Header().Values(k)) {
-				ir.Header().Del(k)
-				for _, vr := range v {
-					ir.Header().Add(k, vr)
-				}
-			}
-		}
 	}
 }
 
@@ -165,6 +185,214 @@ func (w *interceptedResponse) Response() web.Response {
 	return web.NewResponseWithHeader(w.Status(), w.body.Bytes(), w.Header())
 }
 
+// NewConditionalGetInterceptor creates an interceptor that answers conditional GET and HEAD requests.
+//
+// Only JSON documents are considered: responses of another content type, those with a
+// Content-Disposition such as file downloads, and those the handler flushes are streamed to the
+// client untouched. Successful JSON responses get a strong ETag computed from the body and are matched against
+// If-None-Match. An ETag already set by the handler is kept as the prefix of the new one, as in
+// "3-<hash>", so that the handler can still recognise it in If-Match; the hash is what tells
+// representations apart, since parts of a body may change without the handler's tag. When the
+// request has no If-None-Match, If-Modified-Since is compared against the Last-Modified header set
+// by the handler. On a match the client gets 304 Not Modified with no body.
+//
+// Unlike regular interceptors it needs to change the status and body yielded by Next, so JSON
+// responses are buffered rather than streamed. Register it after any interceptor that
+// inspects response bodies.
+//
+// Returns:
+//   - A web.Interceptor handling conditional requests
+//
+// Example:
+//
+//	router.Use(gin.NewInterceptor(gin.NewConditionalGetInterceptor()))
+func NewConditionalGetInterceptor() web.Interceptor {
+	return func(req web.InterceptedRequest) web.Response {
+		ir, ok := req.(*interceptedRequest)
+		method := req.Raw().Method
+		if !ok || (method != http.MethodGet && method != http.MethodHead) {
+			return req.Next()
+		}
+
+		w := ir.ctx.Writer
+		buf := &bufferedResponse{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+			body:           new(bytes.Buffer),
+		}
+		ir.ctx.Writer = buf
+		req.Next()
+		ir.ctx.Writer = w
+
+		if buf.streaming {
+			return web.NewResponseWithHeader(w.Status(), nil, w.Header())
+		}
+
+		resp := evaluatePreconditions(req.Raw(), web.NewResponseWithHeader(buf.status, buf.body.Bytes(), w.Header()))
+
+		w.WriteHeader(resp.Status)
+		if len(resp.Body) > 0 {
+			if _, err := w.Write(resp.Body); err != nil {
+				noticeError(req.Context(), "gin_middleware", err)
+			}
+		}
+		return resp
+	}
+}
+
+// evaluatePreconditions tags a successful response and turns it into a 304 Not Modified
+// when the request preconditions show the client already holds the current representation.
+func evaluatePreconditions(r *http.Request, resp web.Response) web.Response {
+	if resp.Status != http.StatusOK {
+		return resp
+	}
+
+	sum := sha256.Sum256(resp.Body)
+	hash := hex.EncodeToString(sum[:16])
+	etag := `"` + hash + `"`
+	if tag := resp.Headers.Get("ETag"); strings.HasSuffix(tag, `"`) {
+		etag = strings.TrimSuffix(tag, `"`) + "-" + hash + `"`
+	}
+	resp.Headers.Set("ETag", etag)
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatches(inm, etag) {
+			return resp
+		}
+	} else if !notModifiedSince(r.Header.Get("If-Modified-Since"), resp.Headers.Get("Last-Modified")) {
+		return resp
+	}
+
+	resp.Headers.Del("Content-Type")
+	resp.Headers.Del("Content-Length")
+	return web.NewResponseWithHeader(http.StatusNotModified, nil, resp.Headers)
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using weak comparison.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModifiedSince reports whether the representation last modified at lastModified is not newer
+// than the If-Modified-Since date. Unparseable dates never match.
+func notModifiedSince(ifModifiedSince, lastModified string) bool {
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// bufferable reports whether a response with the headers is a JSON document worth tagging.
+func bufferable(h http.Header) bool {
+	if h.Get("Content-Disposition") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// sniff decides on the first write whether the response is buffered, from the headers the
+// handler set before writing its body, and reports whether it is streamed.
+func (w *bufferedResponse) sniff() bool {
+	if !w.sniffed {
+		w.sniffed = true
+		if !bufferable(w.Header()) {
+			w.stream()
+		}
+	}
+	return w.streaming
+}
+
+// stream sends the status and whatever was buffered, and writes everything through from then on.
+func (w *bufferedResponse) stream() {
+	if w.streaming {
+		return
+	}
+	w.streaming = true
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+}
+
+// WriteHeader records the status code instead of sending it, unless the response is streamed.
+func (w *bufferedResponse) WriteHeader(code int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+// WriteHeaderNow is a no-op unless the response is streamed; the status is sent once the
+// buffered response is released.
+func (w *bufferedResponse) WriteHeaderNow() {
+	if w.streaming {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Write buffers b instead of sending it, unless the response is streamed.
+func (w *bufferedResponse) Write(b []byte) (int, error) {
+	if w.sniff() {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+// WriteString buffers s instead of sending it, unless the response is streamed.
+func (w *bufferedResponse) WriteString(s string) (int, error) {
+	if w.sniff() {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+// Flush streams the response: a handler flushing wants the client to get the body as it goes.
+func (w *bufferedResponse) Flush() {
+	w.stream()
+	w.ResponseWriter.Flush()
+}
+
+// Status returns the buffered status code, or the one sent when the response is streamed.
+func (w *bufferedResponse) Status() int {
+	if w.streaming {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+// Size returns the number of buffered body bytes, or of those sent when the response is streamed.
+func (w *bufferedResponse) Size() int {
+	if w.streaming {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+// Written reports false until the response is streamed, as nothing reaches the client before
+// the buffer is released.
+func (w *bufferedResponse) Written() bool {
+	return w.streaming && w.ResponseWriter.Written()
+}
+
 // recoverInterceptorResp is a panic recovery function for interceptors.
 // It catches panics, logs them, and allows the middleware chain to continue.
 //
@@ -0,0 +1,246 @@
+package gin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todo-api/web"
+	webgin "todo-api/web/gin"
+)
+
+var lastModified = time.Date(2026, 1, 28, 10, 30, 0, 0, time.UTC)
+
+func newConditionalRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(webgin.NewInterceptor(webgin.NewConditionalGetInterceptor()))
+
+	todo := func(req web.Request) web.Response {
+		resp := web.NewJSONResponse(http.StatusOK, map[string]string{"title": "Test Todo"})
+		resp.Headers.Set("ETag", `"3"`)
+		resp.Headers.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		return resp
+	}
+	r.GET("/todo", webgin.NewHandlerJSON(todo))
+	r.POST("/todo", webgin.NewHandlerJSON(todo))
+	r.GET("/untagged", webgin.NewHandlerJSON(func(req web.Request) web.Response {
+		return web.NewJSONResponse(http.StatusOK, map[string]string{"title": "Test Todo"})
+	}))
+	r.GET("/missing", webgin.NewHandlerJSON(func(req web.Request) web.Response {
+		return web.NewJSONResponse(http.StatusNotFound, map[string]string{"error": "not found"})
+	}))
+	r.GET("/text", webgin.NewHandlerRaw(func(req web.Request) web.Response {
+		return web.NewResponseWithHeader(http.StatusOK, []byte("pong"), http.Header{"Content-Type": {"text/plain"}})
+	}))
+	r.GET("/download", webgin.NewHandlerRaw(func(req web.Request) web.Response {
+		return web.NewResponseWithHeader(http.StatusOK, []byte(`{"title": "Test Todo"}`), http.Header{
+			"Content-Type":        {"application/json"},
+			"Content-Disposition": {`attachment; filename="todo.json"`},
+		})
+	}))
+	r.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString(`{"title": `)
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString(`"Test Todo"}`)
+	})
+	return r
+}
+
+func serve(r http.Handler, method, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// currentETag fetches the path once to learn the tag the interceptor issues for it.
+func currentETag(t *testing.T, r http.Handler, path string) string {
+	t.Helper()
+	w := serve(r, http.MethodGet, path, nil)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected a tagged 200, got %d with ETag %q", w.Code, etag)
+	}
+	return etag
+}
+
+func TestConditionalGet_TagsResponsesWithTheBodyHash(t *testing.T) {
+	r := newConditionalRouter()
+
+	tagged := currentETag(t, r, "/todo")
+	untagged := currentETag(t, r, "/untagged")
+
+	if !strings.HasPrefix(tagged, `"3-`) {
+		t.Errorf("expected the handler tag to prefix the ETag, got %q", tagged)
+	}
+	if hash := strings.TrimPrefix(tagged, `"3-`); `"`+hash != untagged {
+		t.Errorf("expected both ETags to carry the hash of the same body, got %q and %q", tagged, untagged)
+	}
+}
+
+func TestConditionalGet_MatchingIfNoneMatchAnswersNotModifiedWithoutBody(t *testing.T) {
+	r := newConditionalRouter()
+	etag := currentETag(t, r, "/todo")
+
+	w := serve(r, http.MethodGet, "/todo", http.Header{"If-None-Match": {etag}})
+
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected an empty body, got %q", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "" {
+		t.Errorf("expected no Content-Type, got %q", ct)
+	}
+	if got := w.Header().Get("ETag"); got != etag {
+		t.Errorf("expected ETag %q, got %q", etag, got)
+	}
+}
+
+func TestConditionalGet_IfNoneMatch(t *testing.T) {
+	r := newConditionalRouter()
+	etag := currentETag(t, r, "/todo")
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "strong", header: etag, status: http.StatusNotModified},
+		{name: "weak", header: "W/" + etag, status: http.StatusNotModified},
+		{name: "wildcard", header: "*", status: http.StatusNotModified},
+		{name: "list", header: `"other", ` + etag, status: http.StatusNotModified},
+		{name: "version only", header: `"3"`, status: http.StatusOK},
+		{name: "stale", header: `"other"`, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, "/todo", http.Header{"If-None-Match": {tt.header}})
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if tt.status == http.StatusOK && w.Body.Len() == 0 {
+				t.Error("expected the body to be sent")
+			}
+		})
+	}
+}
+
+func TestConditionalGet_IfModifiedSince(t *testing.T) {
+	r := newConditionalRouter()
+
+	tests := []struct {
+		name   string
+		since  time.Time
+		status int
+	}{
+		{name: "same second", since: lastModified, status: http.StatusNotModified},
+		{name: "later", since: lastModified.Add(time.Hour), status: http.StatusNotModified},
+		{name: "earlier", since: lastModified.Add(-time.Hour), status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, "/todo", http.Header{"If-Modified-Since": {tt.since.Format(http.TimeFormat)}})
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestConditionalGet_IfNoneMatchTakesPrecedenceOverIfModifiedSince(t *testing.T) {
+	r := newConditionalRouter()
+
+	w := serve(r, http.MethodGet, "/todo", http.Header{
+		"If-None-Match":     {`"other"`},
+		"If-Modified-Since": {lastModified.Add(time.Hour).Format(http.TimeFormat)},
+	})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestConditionalGet_PassesThroughOtherMethods(t *testing.T) {
+	r := newConditionalRouter()
+
+	w := serve(r, http.MethodPost, "/todo", http.Header{"If-None-Match": {"*"}})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("expected the handler ETag to be left alone, got %q", etag)
+	}
+	if w.Body.Len() == 0 {
+		t.Error("expected the body to be sent")
+	}
+}
+
+func TestConditionalGet_PassesThroughUnsuccessfulResponses(t *testing.T) {
+	r := newConditionalRouter()
+
+	w := serve(r, http.MethodGet, "/missing", http.Header{"If-None-Match": {"*"}})
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if etag := w.Header().Get("ETag"); etag != "" {
+		t.Errorf("expected no ETag, got %q", etag)
+	}
+	if !strings.Contains(w.Body.String(), "not found") {
+		t.Errorf("expected the error body, got %q", w.Body.String())
+	}
+}
+
+func TestConditionalGet_StreamsResponsesThatAreNotJSONDocuments(t *testing.T) {
+	r := newConditionalRouter()
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{name: "other content type", path: "/text", body: "pong"},
+		{name: "download", path: "/download", body: `{"title": "Test Todo"}`},
+		{name: "flushed", path: "/stream", body: `{"title": "Test Todo"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, tt.path, http.Header{"If-None-Match": {"*"}})
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if etag := w.Header().Get("ETag"); etag != "" {
+				t.Errorf("expected no ETag, got %q", etag)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("expected the body %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestConditionalGet_StreamsFlushedResponsesAsTheyGo(t *testing.T) {
+	r := newConditionalRouter()
+
+	w := serve(r, http.MethodGet, "/stream", nil)
+
+	if !w.Flushed {
+		t.Error("expected the response to reach the client when flushed")
+	}
+}
@@ -14,6 +14,10 @@ const (
 	clientAppHeaderName = "X-Api-Client-Application"
 	// clientScopeHeaderName is the header used to identify the client scope
 	clientScopeHeaderName = "X-Api-Client-Scope"
+	// clientUserHeaderName is the header used to identify the user behind the client
+	clientUserHeaderName = "X-Api-Client-User"
+	// apiKeyHeaderName is the header used to send the API key the client authenticates with
+	apiKeyHeaderName = "X-Api-Key"
 
 	// Default values when caller app/scope headers are missing
 	defaultCallerApp   = "n/a"
@@ -79,19 +83,45 @@ func NewParam(k, v string) Param {
 // GetCallerApp extracts the client application identifier from the request headers.
 // Returns the value of the X-Api-Client-Application header or a default value if not present.
 func GetCallerApp(req Request) string {
-	ca := req.Raw().Header.Get(clientAppHeaderName)
-	if len(ca) == 0 {
+	ca, ok := LookupCallerApp(req)
+	if !ok {
 		return defaultCallerApp
 	}
 	return ca
 }
 
+// LookupCallerApp extracts the client application identifier from the request headers.
+// Returns the value of the X-Api-Client-Application header and whether it is present.
+func LookupCallerApp(req Request) (string, bool) {
+	ca := req.Raw().Header.Get(clientAppHeaderName)
+	return ca, len(ca) > 0
+}
+
 // GetCallerScope extracts the client scope identifier from the request headers.
 // Returns the value of the X-Api-Client-Scope header or a default value if not present.
 func GetCallerScope(req Request) string {
-	cs := req.Raw().Header.Get(clientScopeHeaderName)
-	if len(cs) == 0 {
+	cs, ok := LookupCallerScope(req)
+	if !ok {
 		return defaultCallerScope
 	}
 	return cs
 }
+
+// LookupCallerScope extracts the client scope identifier from the request headers.
+// Returns the value of the X-Api-Client-Scope header and whether it is present.
+func LookupCallerScope(req Request) (string, bool) {
+	cs := req.Raw().Header.Get(clientScopeHeaderName)
+	return cs, len(cs) > 0
+}
+
+// GetCallerUser extracts the user identifier from the request headers.
+// Returns the value of the X-Api-Client-User header or an empty string if not present.
+func GetCallerUser(req Request) string {
+	return req.Raw().Header.Get(clientUserHeaderName)
+}
+
+// GetCallerAPIKey extracts the API key the client authenticates with from the request headers.
+// Returns the value of the X-Api-Key header or an empty string if not present.
+func GetCallerAPIKey(req Request) string {
+	return req.Raw().Header.Get(apiKeyHeaderName)
+}